@@ -0,0 +1,10 @@
+// achievements/achievements.go
+package achievements
+
+import _ "embed"
+
+// Default - каталог достижений, вшитый в бинарник. ACHIEVEMENTS_FILE
+// подменяет его файлом того же формата без пересборки
+//
+//go:embed achievements.json
+var Default []byte
@@ -0,0 +1,105 @@
+// cmd/api/migrate.go
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"dojo/migrations"
+
+	"gorm.io/gorm"
+)
+
+const migrateUsage = "использование: migrate up | down [N] | status | create <имя>"
+
+// runMigrate - подкоманда "migrate": go run ./cmd/api migrate up
+func runMigrate(dbURL string, args []string) {
+	if len(args) == 0 {
+		log.Fatal(migrateUsage)
+	}
+
+	// create работает с файлами исходников, база не нужна
+	if args[0] == "create" {
+		if len(args) < 2 {
+			log.Fatal(migrateUsage)
+		}
+		dir := os.Getenv("MIGRATIONS_DIR")
+		if dir == "" {
+			dir = "migrations"
+		}
+
+		paths, err := migrations.Create(dir, args[1])
+		if err != nil {
+			log.Fatal("Не удалось создать миграцию:", err)
+		}
+		for _, path := range paths {
+			fmt.Println("Создан", path)
+		}
+		return
+	}
+
+	migrator := newMigrator(openDB(dbURL))
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Printf("Применена %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatal("Ошибка миграции:", err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("Схема актуальна")
+		}
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				log.Fatal(migrateUsage)
+			}
+			steps = n
+		}
+
+		reverted, err := migrator.Down(ctx, steps)
+		for _, m := range reverted {
+			fmt.Printf("Откачена %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatal("Ошибка отката:", err)
+		}
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatal("Ошибка чтения статуса:", err)
+		}
+		for _, s := range statuses {
+			state := "не применена"
+			if s.AppliedAt != nil {
+				state = "применена " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if s.Missing {
+				state += " (файла нет в сборке)"
+			}
+			fmt.Printf("%04d_%-30s %s\n", s.Version, s.Name, state)
+		}
+
+	default:
+		log.Fatal(migrateUsage)
+	}
+}
+
+func newMigrator(db *gorm.DB) *migrations.Migrator {
+	migrator, err := migrations.NewMigrator(db, migrations.Files)
+	if err != nil {
+		log.Fatal("Не удалось прочитать миграции:", err)
+	}
+	return migrator
+}
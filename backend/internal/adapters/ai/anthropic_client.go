@@ -0,0 +1,335 @@
+// internal/adapters/ai/anthropic_client.go
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+)
+
+const (
+	DefaultAnthropicBaseURL = "https://api.anthropic.com"
+	DefaultAnthropicModel   = "claude-3-5-haiku-latest"
+	anthropicVersion        = "2023-06-01"
+)
+
+// AnthropicClient - AIService поверх Anthropic Messages API.
+// Структурированный вывод - через принудительный вызов инструмента со схемой
+type AnthropicClient struct {
+	config Config
+	http   *http.Client
+	policy retryPolicy
+}
+
+func NewAnthropicClient(config Config) *AnthropicClient {
+	if config.BaseURL == "" {
+		config.BaseURL = DefaultAnthropicBaseURL
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+
+	if config.Model == "" {
+		config.Model = DefaultAnthropicModel
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 30 * time.Second
+	}
+	if config.RetryDelay == 0 {
+		config.RetryDelay = 500 * time.Millisecond
+	}
+
+	return &AnthropicClient{
+		config: config,
+		http:   &http.Client{},
+		policy: retryPolicy{
+			Timeout:    config.Timeout,
+			MaxRetries: config.MaxRetries,
+			BaseDelay:  config.RetryDelay,
+		},
+	}
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicTool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"input_schema"`
+}
+
+type anthropicToolChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+}
+
+type anthropicMetadata struct {
+	UserID string `json:"user_id,omitempty"`
+}
+
+type anthropicRequest struct {
+	Model       string               `json:"model"`
+	System      string               `json:"system,omitempty"`
+	Messages    []anthropicMessage   `json:"messages"`
+	MaxTokens   int                  `json:"max_tokens"`
+	Temperature float64              `json:"temperature"`
+	Tools       []anthropicTool      `json:"tools,omitempty"`
+	ToolChoice  *anthropicToolChoice `json:"tool_choice,omitempty"`
+	Metadata    *anthropicMetadata   `json:"metadata,omitempty"`
+	Stream      bool                 `json:"stream,omitempty"`
+}
+
+type anthropicContentBlock struct {
+	Type  string          `json:"type"`
+	Text  string          `json:"text,omitempty"`
+	Name  string          `json:"name,omitempty"`
+	Input json.RawMessage `json:"input,omitempty"`
+}
+
+type anthropicResponse struct {
+	Content    []anthropicContentBlock `json:"content"`
+	StopReason string                  `json:"stop_reason"`
+}
+
+// anthropicStreamEvent - нужные поля событий потока Messages API
+type anthropicStreamEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Type       string `json:"type"`
+		Text       string `json:"text"`
+		StopReason string `json:"stop_reason"`
+	} `json:"delta"`
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// AnalyzeTask - тип и сложность задания через инструмент со схемой
+func (c *AnthropicClient) AnalyzeTask(ctx context.Context, title, description string) (*ports.TaskAnalysis, error) {
+	req := anthropicRequest{
+		Model:  c.config.Model,
+		System: analyzePrompt,
+		Messages: []anthropicMessage{
+			{Role: "user", Content: analyzeUserMessage(title, description)},
+		},
+		MaxTokens:   400,
+		Temperature: 0.2,
+	}
+	withTool(&req, "task_analysis", "Сохранить оценку задания", taskAnalysisSchema)
+
+	input, err := c.toolInput(ctx, req, "task_analysis")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeAnalysis(input)
+}
+
+// Chat - ответ Сенсея с учетом истории
+func (c *AnthropicClient) Chat(ctx context.Context, userID int64, message string, history []ports.ChatMessage) (string, error) {
+	return c.text(ctx, c.chatRequest(userID, message, history))
+}
+
+// ChatStream - ответ Сенсея потоком (stream: true)
+func (c *AnthropicClient) ChatStream(ctx context.Context, userID int64, message string, history []ports.ChatMessage) (<-chan ports.ChatDelta, error) {
+	req := c.chatRequest(userID, message, history)
+	req.Stream = true
+
+	body, err := openStream(ctx, c.http, c.policy, c.config.BaseURL+"/v1/messages", c.headers(), req)
+	if err != nil {
+		log.Println("Anthropic: запрос не удался:", err)
+		return nil, domain.ErrAIServiceUnavailable
+	}
+
+	return relayStream(ctx, "Anthropic", body, parseAnthropicStreamEvent), nil
+}
+
+func (c *AnthropicClient) chatRequest(userID int64, message string, history []ports.ChatMessage) anthropicRequest {
+	return anthropicRequest{
+		Model:       c.config.Model,
+		System:      anthropicSystem(senseiPrompt, history),
+		Messages:    anthropicHistory(history, message),
+		MaxTokens:   600,
+		Temperature: 0.7,
+		Metadata:    &anthropicMetadata{UserID: userTag(userID)},
+	}
+}
+
+// parseAnthropicStreamEvent - текст из content_block_delta, конец по message_stop
+func parseAnthropicStreamEvent(event sseEvent) (string, bool, error) {
+	var e anthropicStreamEvent
+	if err := json.Unmarshal([]byte(event.Data), &e); err != nil {
+		return "", false, fmt.Errorf("неожиданное событие %s: %s", event.Name, truncate(event.Data, 200))
+	}
+
+	switch e.Type {
+	case "content_block_delta":
+		if e.Delta.Type == "text_delta" {
+			return e.Delta.Text, false, nil
+		}
+	case "message_delta":
+		if e.Delta.StopReason == "refusal" {
+			return "", false, fmt.Errorf("stop_reason=%s", e.Delta.StopReason)
+		}
+	case "message_stop":
+		return "", true, nil
+	case "error":
+		return "", false, fmt.Errorf("%s: %s", e.Error.Type, e.Error.Message)
+	}
+
+	// ping, message_start, content_block_start/stop
+	return "", false, nil
+}
+
+// Summarize - сжатие старых реплик диалога
+func (c *AnthropicClient) Summarize(ctx context.Context, previousSummary string, messages []ports.ChatMessage) (string, error) {
+	req := anthropicRequest{
+		Model:  c.config.Model,
+		System: summarizePrompt,
+		Messages: []anthropicMessage{
+			{Role: "user", Content: summaryUserMessage(previousSummary, messages)},
+		},
+		MaxTokens:   400,
+		Temperature: 0.2,
+	}
+
+	return c.text(ctx, req)
+}
+
+// text - текстовые блоки ответа одной строкой
+func (c *AnthropicClient) text(ctx context.Context, req anthropicRequest) (string, error) {
+	resp, err := c.send(ctx, req, domain.ErrAIServiceUnavailable)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for _, block := range resp.Content {
+		if block.Type == "text" {
+			sb.WriteString(block.Text)
+		}
+	}
+
+	text := strings.TrimSpace(sb.String())
+	if text == "" {
+		return "", domain.ErrAIServiceUnavailable
+	}
+	return text, nil
+}
+
+// GenerateUrgentCall - предложение срочного вызова
+func (c *AnthropicClient) GenerateUrgentCall(ctx context.Context, userID int64) (*ports.UrgentCallSuggestion, error) {
+	req := anthropicRequest{
+		Model:  c.config.Model,
+		System: urgentCallPrompt,
+		Messages: []anthropicMessage{
+			{Role: "user", Content: "Сгенерируй срочный вызов."},
+		},
+		MaxTokens:   400,
+		Temperature: 0.9,
+		Metadata:    &anthropicMetadata{UserID: userTag(userID)},
+	}
+	withTool(&req, "urgent_call", "Сохранить срочный вызов", urgentCallSchema)
+
+	input, err := c.toolInput(ctx, req, "urgent_call")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeUrgentCall(input)
+}
+
+// toolInput - аргументы принудительного вызова инструмента
+func (c *AnthropicClient) toolInput(ctx context.Context, req anthropicRequest, tool string) ([]byte, error) {
+	resp, err := c.send(ctx, req, domain.ErrAIAnalysisFailed)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, block := range resp.Content {
+		if block.Type == "tool_use" && block.Name == tool && len(block.Input) > 0 {
+			return block.Input, nil
+		}
+	}
+
+	log.Printf("Anthropic: нет вызова инструмента %s (stop_reason=%s)", tool, resp.StopReason)
+	return nil, domain.ErrAIAnalysisFailed
+}
+
+// send - запрос к /v1/messages; badAnswer возвращается при непригодном ответе
+func (c *AnthropicClient) send(ctx context.Context, req anthropicRequest, badAnswer error) (*anthropicResponse, error) {
+	body, err := postJSON(ctx, c.http, c.policy, c.config.BaseURL+"/v1/messages", c.headers(), req)
+	if err != nil {
+		log.Println("Anthropic: запрос не удался:", err)
+		return nil, domain.ErrAIServiceUnavailable
+	}
+
+	var resp anthropicResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		log.Println("Anthropic: неожиданный ответ:", truncate(string(body), 500))
+		return nil, badAnswer
+	}
+
+	if resp.StopReason == "max_tokens" || resp.StopReason == "refusal" {
+		log.Printf("Anthropic: ответ непригоден (stop_reason=%s)", resp.StopReason)
+		return nil, badAnswer
+	}
+
+	return &resp, nil
+}
+
+func (c *AnthropicClient) headers() map[string]string {
+	headers := map[string]string{"anthropic-version": anthropicVersion}
+	if c.config.APIKey != "" {
+		headers["x-api-key"] = c.config.APIKey
+	}
+	return headers
+}
+
+func withTool(req *anthropicRequest, name, description string, schema map[string]interface{}) {
+	req.Tools = []anthropicTool{{Name: name, Description: description, InputSchema: schema}}
+	req.ToolChoice = &anthropicToolChoice{Type: "tool", Name: name}
+}
+
+// anthropicSystem - системные сообщения истории дописываются к системному промпту
+func anthropicSystem(prompt string, history []ports.ChatMessage) string {
+	for _, m := range history {
+		if m.Role == ports.RoleSystem {
+			prompt += "\n\n" + m.Content
+		}
+	}
+	return prompt
+}
+
+// anthropicHistory - Messages API требует чередования ролей и первой реплики от user
+func anthropicHistory(history []ports.ChatMessage, message string) []anthropicMessage {
+	messages := make([]anthropicMessage, 0, len(history)+1)
+	for _, m := range history {
+		if m.Role != ports.RoleUser && m.Role != ports.RoleAssistant {
+			continue
+		}
+		if len(messages) == 0 && m.Role != "user" {
+			continue
+		}
+		if n := len(messages); n > 0 && messages[n-1].Role == m.Role {
+			messages[n-1].Content += "\n\n" + m.Content
+			continue
+		}
+		messages = append(messages, anthropicMessage{Role: m.Role, Content: m.Content})
+	}
+
+	if n := len(messages); n > 0 && messages[n-1].Role == "user" {
+		messages[n-1].Content += "\n\n" + message
+		return messages
+	}
+	return append(messages, anthropicMessage{Role: "user", Content: message})
+}
@@ -0,0 +1,205 @@
+// internal/adapters/ai/anthropic_client_test.go
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+)
+
+// fakeProvider - httptest-сервер, отвечающий по очереди заготовленными ответами.
+// Последний ответ повторяется для всех следующих запросов
+type fakeProvider struct {
+	*httptest.Server
+	calls    atomic.Int32
+	requests chan *http.Request
+	bodies   chan []byte
+}
+
+type fakeResponse struct {
+	status  int
+	headers map[string]string
+	body    string
+}
+
+func newFakeProvider(t *testing.T, responses ...fakeResponse) *fakeProvider {
+	t.Helper()
+
+	p := &fakeProvider{
+		requests: make(chan *http.Request, 16),
+		bodies:   make(chan []byte, 16),
+	}
+	p.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(p.calls.Add(1))
+		var body json.RawMessage
+		json.NewDecoder(r.Body).Decode(&body)
+		p.requests <- r
+		p.bodies <- body
+
+		resp := responses[min(n, len(responses))-1]
+		for k, v := range resp.headers {
+			w.Header().Set(k, v)
+		}
+		w.WriteHeader(resp.status)
+		w.Write([]byte(resp.body))
+	}))
+	t.Cleanup(p.Close)
+	return p
+}
+
+func ok(body string) fakeResponse {
+	return fakeResponse{status: http.StatusOK, body: body}
+}
+
+func testConfig(url string) Config {
+	return Config{
+		APIKey:     "test-key",
+		BaseURL:    url,
+		Timeout:    time.Second,
+		MaxRetries: 2,
+		RetryDelay: time.Millisecond,
+	}
+}
+
+func TestAnthropicAnalyzeTaskToolUse(t *testing.T) {
+	p := newFakeProvider(t, ok(`{
+		"content": [
+			{"type": "text", "text": "Оцениваю"},
+			{"type": "tool_use", "name": "task_analysis", "input": {"task_type": "strength", "difficulty": 14, "explanation": "тяжело"}}
+		],
+		"stop_reason": "tool_use"
+	}`))
+
+	analysis, err := NewAnthropicClient(testConfig(p.URL)).AnalyzeTask(context.Background(), "100 отжиманий", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if analysis.TaskType != domain.TypeStrength || analysis.Difficulty != 10 || analysis.XPReward != 100 {
+		t.Errorf("analysis = %+v, want strength with difficulty clamped to 10", analysis)
+	}
+
+	r := <-p.requests
+	if r.URL.Path != "/v1/messages" || r.Header.Get("x-api-key") != "test-key" || r.Header.Get("anthropic-version") != anthropicVersion {
+		t.Errorf("request %s with headers %v", r.URL.Path, r.Header)
+	}
+
+	var req anthropicRequest
+	if err := json.Unmarshal(<-p.bodies, &req); err != nil {
+		t.Fatal(err)
+	}
+	if req.ToolChoice == nil || req.ToolChoice.Type != "tool" || req.ToolChoice.Name != "task_analysis" {
+		t.Errorf("tool_choice = %+v", req.ToolChoice)
+	}
+	if len(req.Tools) != 1 || req.Tools[0].InputSchema["type"] != "object" {
+		t.Errorf("tools = %+v", req.Tools)
+	}
+}
+
+func TestAnthropicErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		response fakeResponse
+		want     error
+		calls    int32
+	}{
+		{"no tool call", ok(`{"content":[{"type":"text","text":"не буду"}],"stop_reason":"end_turn"}`), domain.ErrAIAnalysisFailed, 1},
+		{"wrong tool", ok(`{"content":[{"type":"tool_use","name":"other","input":{}}],"stop_reason":"tool_use"}`), domain.ErrAIAnalysisFailed, 1},
+		{"unknown task type", ok(`{"content":[{"type":"tool_use","name":"task_analysis","input":{"task_type":"magic","difficulty":3}}]}`), domain.ErrAIAnalysisFailed, 1},
+		{"max tokens", ok(`{"content":[],"stop_reason":"max_tokens"}`), domain.ErrAIAnalysisFailed, 1},
+		{"not json", ok(`<html>`), domain.ErrAIAnalysisFailed, 1},
+		{"bad request is not retried", fakeResponse{status: http.StatusBadRequest, body: `{"error":{}}`}, domain.ErrAIServiceUnavailable, 1},
+		{"overloaded is retried", fakeResponse{status: 529, body: `{"error":{}}`}, domain.ErrAIServiceUnavailable, 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := newFakeProvider(t, tc.response)
+
+			_, err := NewAnthropicClient(testConfig(p.URL)).AnalyzeTask(context.Background(), "Задание", "")
+			if err != tc.want {
+				t.Errorf("err = %v, want %v", err, tc.want)
+			}
+			if got := p.calls.Load(); got != tc.calls {
+				t.Errorf("calls = %d, want %d", got, tc.calls)
+			}
+		})
+	}
+}
+
+func TestAnthropicChatBadAnswerIsUnavailable(t *testing.T) {
+	p := newFakeProvider(t, ok(`{"content":[{"type":"text","text":"обрыв"}],"stop_reason":"max_tokens"}`))
+
+	_, err := NewAnthropicClient(testConfig(p.URL)).Chat(context.Background(), 1, "привет", nil)
+	if err != domain.ErrAIServiceUnavailable {
+		t.Errorf("err = %v, want ErrAIServiceUnavailable", err)
+	}
+}
+
+func TestAnthropicRetriesWithRetryAfter(t *testing.T) {
+	p := newFakeProvider(t,
+		fakeResponse{status: http.StatusTooManyRequests, headers: map[string]string{"Retry-After": "1"}, body: `{}`},
+		ok(`{"content":[{"type":"text","text":" Дыши глубже. "}],"stop_reason":"end_turn"}`),
+	)
+
+	start := time.Now()
+	reply, err := NewAnthropicClient(testConfig(p.URL)).Chat(context.Background(), 1, "привет", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply != "Дыши глубже." {
+		t.Errorf("reply = %q", reply)
+	}
+	if p.calls.Load() != 2 {
+		t.Errorf("calls = %d, want 2", p.calls.Load())
+	}
+	// Базовая пауза - миллисекунда: ждали столько, сколько попросил сервер
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want Retry-After of 1s", elapsed)
+	}
+}
+
+func TestAnthropicHistory(t *testing.T) {
+	p := newFakeProvider(t, ok(`{"content":[{"type":"text","text":"ok"}]}`))
+
+	history := []ports.ChatMessage{
+		{Role: ports.RoleAssistant, Content: "лишнее приветствие"},
+		{Role: ports.RoleUser, Content: "раз"},
+		{Role: ports.RoleUser, Content: "два"},
+		{Role: ports.RoleSystem, Content: "заметки"},
+		{Role: ports.RoleAssistant, Content: "ответ"},
+	}
+	if _, err := NewAnthropicClient(testConfig(p.URL)).Chat(context.Background(), 7, "три", history); err != nil {
+		t.Fatal(err)
+	}
+
+	var req anthropicRequest
+	if err := json.Unmarshal(<-p.bodies, &req); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []anthropicMessage{
+		{Role: "user", Content: "раз\n\nдва"},
+		{Role: "assistant", Content: "ответ"},
+		{Role: "user", Content: "три"},
+	}
+	if len(req.Messages) != len(want) {
+		t.Fatalf("messages = %+v", req.Messages)
+	}
+	for i := range want {
+		if req.Messages[i] != want[i] {
+			t.Errorf("message %d = %+v, want %+v", i, req.Messages[i], want[i])
+		}
+	}
+	if !strings.HasSuffix(req.System, "\n\nзаметки") {
+		t.Errorf("system notes not appended: %q", req.System)
+	}
+	if req.Metadata == nil || req.Metadata.UserID != "dojo-7" {
+		t.Errorf("metadata = %+v", req.Metadata)
+	}
+}
@@ -0,0 +1,97 @@
+// internal/adapters/ai/chain.go
+package ai
+
+import (
+	"context"
+	"log"
+
+	"dojo/internal/ports"
+)
+
+// Chain - сначала основной провайдер, при ошибке запасной
+type Chain struct {
+	primary  ports.AIService
+	fallback ports.AIService
+}
+
+func NewChain(primary, fallback ports.AIService) *Chain {
+	return &Chain{
+		primary:  primary,
+		fallback: fallback,
+	}
+}
+
+func (c *Chain) AnalyzeTask(ctx context.Context, title, description string) (*ports.TaskAnalysis, error) {
+	analysis, err := c.primary.AnalyzeTask(ctx, title, description)
+	if err == nil {
+		return analysis, nil
+	}
+
+	log.Println("ИИ: AnalyzeTask через запасной провайдер:", err)
+	return c.fallback.AnalyzeTask(ctx, title, description)
+}
+
+func (c *Chain) Chat(ctx context.Context, userID int64, message string, history []ports.ChatMessage) (string, error) {
+	reply, err := c.primary.Chat(ctx, userID, message, history)
+	if err == nil {
+		return reply, nil
+	}
+
+	log.Println("ИИ: Chat через запасной провайдер:", err)
+	return c.fallback.Chat(ctx, userID, message, history)
+}
+
+// ChatStream - запасной провайдер подключается, если основной не открыл поток
+// или оборвал его до первого фрагмента
+func (c *Chain) ChatStream(ctx context.Context, userID int64, message string, history []ports.ChatMessage) (<-chan ports.ChatDelta, error) {
+	deltas, err := c.primary.ChatStream(ctx, userID, message, history)
+	if err != nil {
+		log.Println("ИИ: ChatStream через запасной провайдер:", err)
+		return c.fallback.ChatStream(ctx, userID, message, history)
+	}
+
+	first, ok := <-deltas
+	if !ok || first.Err != nil {
+		log.Println("ИИ: ChatStream через запасной провайдер: поток оборвался до первого фрагмента")
+		return c.fallback.ChatStream(ctx, userID, message, history)
+	}
+
+	// Первый фрагмент уже прочитан - отдаем его и дальше пересылаем остальное
+	out := make(chan ports.ChatDelta)
+	go func() {
+		defer close(out)
+		for delta := first; ; {
+			select {
+			case out <- delta:
+			case <-ctx.Done():
+				return
+			}
+
+			var ok bool
+			if delta, ok = <-deltas; !ok {
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (c *Chain) Summarize(ctx context.Context, previousSummary string, messages []ports.ChatMessage) (string, error) {
+	summary, err := c.primary.Summarize(ctx, previousSummary, messages)
+	if err == nil {
+		return summary, nil
+	}
+
+	log.Println("ИИ: Summarize через запасной провайдер:", err)
+	return c.fallback.Summarize(ctx, previousSummary, messages)
+}
+
+func (c *Chain) GenerateUrgentCall(ctx context.Context, userID int64) (*ports.UrgentCallSuggestion, error) {
+	suggestion, err := c.primary.GenerateUrgentCall(ctx, userID)
+	if err == nil {
+		return suggestion, nil
+	}
+
+	log.Println("ИИ: GenerateUrgentCall через запасной провайдер:", err)
+	return c.fallback.GenerateUrgentCall(ctx, userID)
+}
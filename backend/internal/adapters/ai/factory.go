@@ -0,0 +1,96 @@
+// internal/adapters/ai/factory.go
+package ai
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"dojo/internal/ports"
+)
+
+type Provider string
+
+const (
+	// ProviderNone - без LLM, только правила HeuristicClient
+	ProviderNone      Provider = "none"
+	ProviderOpenAI    Provider = "openai"
+	ProviderAnthropic Provider = "anthropic"
+)
+
+// Config - настройки клиента ИИ-провайдера
+type Config struct {
+	Provider Provider
+	APIKey   string
+	// BaseURL - адрес API; можно указать локальный совместимый сервер или заглушку
+	BaseURL    string
+	Model      string
+	Timeout    time.Duration
+	MaxRetries int
+	RetryDelay time.Duration
+}
+
+// New - выбирает реализацию AIService по провайдеру. LLM всегда
+// подстрахован правилами: при его ошибке игрок получит эвристическую оценку
+func New(config Config) (ports.AIService, error) {
+	heuristic := NewHeuristicClient()
+
+	switch config.Provider {
+	case ProviderNone, "":
+		return heuristic, nil
+	case ProviderOpenAI:
+		return NewChain(NewOpenAIClient(config), heuristic), nil
+	case ProviderAnthropic:
+		if config.APIKey == "" && config.BaseURL == "" {
+			return nil, fmt.Errorf("для провайдера anthropic нужен ANTHROPIC_API_KEY")
+		}
+		return NewChain(NewAnthropicClient(config), heuristic), nil
+	default:
+		return nil, fmt.Errorf("неизвестный AI_PROVIDER: %s (ожидается openai, anthropic или none)", config.Provider)
+	}
+}
+
+// ConfigFromEnv - настройки из окружения.
+// AI_PROVIDER не задан - выбираем по тому, какой ключ указан
+func ConfigFromEnv() (Config, error) {
+	config := Config{Provider: Provider(os.Getenv("AI_PROVIDER"))}
+
+	if config.Provider == "" {
+		switch {
+		case os.Getenv("OPENAI_API_KEY") != "" || os.Getenv("OPENAI_BASE_URL") != "":
+			config.Provider = ProviderOpenAI
+		case os.Getenv("ANTHROPIC_API_KEY") != "":
+			config.Provider = ProviderAnthropic
+		default:
+			config.Provider = ProviderNone
+		}
+	}
+
+	switch config.Provider {
+	case ProviderOpenAI:
+		config.APIKey = os.Getenv("OPENAI_API_KEY")
+		config.BaseURL = os.Getenv("OPENAI_BASE_URL")
+		config.Model = os.Getenv("OPENAI_MODEL")
+	case ProviderAnthropic:
+		config.APIKey = os.Getenv("ANTHROPIC_API_KEY")
+		config.BaseURL = os.Getenv("ANTHROPIC_BASE_URL")
+		config.Model = os.Getenv("ANTHROPIC_MODEL")
+	}
+
+	var err error
+	if v := os.Getenv("AI_TIMEOUT"); v != "" {
+		if config.Timeout, err = time.ParseDuration(v); err != nil {
+			return config, fmt.Errorf("неверный AI_TIMEOUT: %w", err)
+		}
+	}
+
+	config.MaxRetries = 2
+	if v := os.Getenv("AI_MAX_RETRIES"); v != "" {
+		if config.MaxRetries, err = strconv.Atoi(v); err != nil {
+			return config, fmt.Errorf("неверный AI_MAX_RETRIES: %w", err)
+		}
+	}
+
+	return config, nil
+}
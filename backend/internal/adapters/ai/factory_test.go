@@ -0,0 +1,105 @@
+// internal/adapters/ai/factory_test.go
+package ai
+
+import (
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		config  Config
+		want    string
+		wantErr bool
+	}{
+		{"default", Config{}, "heuristic", false},
+		{"none", Config{Provider: ProviderNone}, "heuristic", false},
+		{"openai", Config{Provider: ProviderOpenAI, APIKey: "k"}, "openai", false},
+		// Совместимый локальный сервер может работать без ключа
+		{"openai without key", Config{Provider: ProviderOpenAI, BaseURL: "http://localhost:11434/v1"}, "openai", false},
+		{"anthropic", Config{Provider: ProviderAnthropic, APIKey: "k"}, "anthropic", false},
+		{"anthropic stub", Config{Provider: ProviderAnthropic, BaseURL: "http://stub"}, "anthropic", false},
+		{"anthropic without key", Config{Provider: ProviderAnthropic}, "", true},
+		{"unknown", Config{Provider: "gemini"}, "", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			service, err := New(tc.config)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("New(%+v) = %T, want error", tc.config, service)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got string
+			switch s := service.(type) {
+			case *HeuristicClient:
+				got = "heuristic"
+			case *Chain:
+				// LLM всегда подстрахован правилами
+				if _, ok := s.fallback.(*HeuristicClient); !ok {
+					t.Errorf("fallback = %T, want *HeuristicClient", s.fallback)
+				}
+				switch s.primary.(type) {
+				case *OpenAIClient:
+					got = "openai"
+				case *AnthropicClient:
+					got = "anthropic"
+				}
+			}
+			if got != tc.want {
+				t.Errorf("New(%+v) = %T (%s), want %s", tc.config, service, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		env  map[string]string
+		want Provider
+		key  string
+	}{
+		{"nothing set", nil, ProviderNone, ""},
+		{"openai key", map[string]string{"OPENAI_API_KEY": "sk-1"}, ProviderOpenAI, "sk-1"},
+		{"openai base url", map[string]string{"OPENAI_BASE_URL": "http://localhost"}, ProviderOpenAI, ""},
+		{"anthropic key", map[string]string{"ANTHROPIC_API_KEY": "ak-1"}, ProviderAnthropic, "ak-1"},
+		{"openai wins without AI_PROVIDER", map[string]string{"OPENAI_API_KEY": "sk-1", "ANTHROPIC_API_KEY": "ak-1"}, ProviderOpenAI, "sk-1"},
+		{"explicit provider", map[string]string{"AI_PROVIDER": "anthropic", "OPENAI_API_KEY": "sk-1", "ANTHROPIC_API_KEY": "ak-1"}, ProviderAnthropic, "ak-1"},
+		{"explicit none", map[string]string{"AI_PROVIDER": "none", "OPENAI_API_KEY": "sk-1"}, ProviderNone, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, key := range []string{"AI_PROVIDER", "OPENAI_API_KEY", "OPENAI_BASE_URL", "ANTHROPIC_API_KEY", "AI_TIMEOUT", "AI_MAX_RETRIES"} {
+				t.Setenv(key, tc.env[key])
+			}
+
+			config, err := ConfigFromEnv()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if config.Provider != tc.want || config.APIKey != tc.key {
+				t.Errorf("config = %+v, want provider %s with key %q", config, tc.want, tc.key)
+			}
+			if config.MaxRetries != 2 {
+				t.Errorf("MaxRetries = %d, want default 2", config.MaxRetries)
+			}
+		})
+	}
+}
+
+func TestConfigFromEnvInvalid(t *testing.T) {
+	for _, key := range []string{"AI_TIMEOUT", "AI_MAX_RETRIES"} {
+		t.Run(key, func(t *testing.T) {
+			t.Setenv("AI_PROVIDER", "none")
+			t.Setenv(key, "soon")
+
+			if _, err := ConfigFromEnv(); err == nil {
+				t.Errorf("%s=soon accepted", key)
+			}
+		})
+	}
+}
@@ -0,0 +1,369 @@
+// internal/adapters/ai/heuristic.go
+package ai
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+)
+
+// HeuristicClient - AIService на правилах, без сети и LLM.
+// Детерминирован: одинаковый вход дает одинаковый результат
+type HeuristicClient struct {
+	now func() time.Time
+}
+
+func NewHeuristicClient() *HeuristicClient {
+	return &HeuristicClient{now: time.Now}
+}
+
+// Начала слов (нижний регистр) для определения типа задания, русские и английские
+var taskTypeKeywords = map[domain.TaskType][]string{
+	domain.TypeStrength: {
+		"отжим", "присед", "подтяг", "трениров", "качал", "штанг", "гантел", "жим", "пресс", "планк", "турник", "тяжел",
+		"workout", "gym", "push-up", "pushup", "squat", "pull-up", "pullup", "lift", "strength", "plank", "deadlift", "bench",
+	},
+	domain.TypeAgility: {
+		"бег", "пробеж", "растяж", "йог", "танц", "велосипед", "плаван", "плыть", "прыж", "скакал", "прогул", "ходьб", "шаг",
+		"run", "jog", "stretch", "yoga", "dance", "bike", "cycl", "swim", "jump", "walk", "steps", "sprint", "cardio",
+	},
+	domain.TypeIntelligence: {
+		"чита", "книг", "учи", "изуч", "курс", "лекци", "код", "программ", "экзамен", "язык", "английск", "статью", "конспект", "домашк", "работ",
+		"read", "book", "study", "learn", "course", "lecture", "code", "program", "exam", "homework", "language", "article", "research", "write",
+	},
+	domain.TypeInsight: {
+		"медит", "дневник", "рефлекс", "дыхан", "сон", "спать", "осознан", "благодар", "итоги", "цели", "отдых", "тишин",
+		"meditat", "journal", "reflect", "breath", "sleep", "mindful", "gratitude", "review", "goals", "rest", "calm",
+	},
+}
+
+// Начала слов и фразы, повышающие и понижающие сложность
+var (
+	hardKeywords = []string{
+		"марафон", "весь день", "интенсив", "сложн", "тяжел", "максимум", "до отказа", "проект", "экзамен",
+		"marathon", "all day", "intense", "hard", "difficult", "max", "exam", "project",
+	}
+	easyKeywords = []string{
+		"немного", "легк", "быстр", "чуть", "пару", "разминк",
+		"easy", "quick", "light", "a bit", "couple", "warm-up", "warmup",
+	}
+)
+
+var (
+	minutesPattern = regexp.MustCompile(`(\d+)\s*(минут|мин|min)`)
+	// \b в RE2 работает только для ASCII, поэтому конец "ч" проверяем явно
+	hoursPattern    = regexp.MustCompile(`(\d+)\s*(час|ч(?:[\s.,]|$)|hour|hrs|h(?:[\s.,]|$))`)
+	distancePattern = regexp.MustCompile(`(\d+)\s*(км|km)`)
+	countPattern    = regexp.MustCompile(`\d+`)
+)
+
+// AnalyzeTask - тип по ключевым словам, сложность по формулировке и объему
+func (c *HeuristicClient) AnalyzeTask(ctx context.Context, title, description string) (*ports.TaskAnalysis, error) {
+	text := strings.ToLower(title + " " + description)
+	tokens := words(text)
+
+	taskType := classifyTaskType(tokens)
+	difficulty := estimateDifficulty(text, tokens, len([]rune(description)))
+
+	explanation := fmt.Sprintf("Оценка по правилам: тип %s, сложность %d/10", taskType, difficulty)
+	return analysisFromDifficulty(taskType, difficulty, explanation), nil
+}
+
+// Chat - заготовленные ответы Сенсея по теме вопроса
+func (c *HeuristicClient) Chat(ctx context.Context, userID int64, message string, history []ports.ChatMessage) (string, error) {
+	tokens := words(message)
+	topic := classifyTaskType(tokens)
+	matched := countKeywordMatches(tokens, taskTypeKeywords[topic]) > 0
+
+	var replies []string
+	switch {
+	case isMostlyLatin(message) && matched:
+		replies = senseiTopicRepliesEN[topic]
+	case isMostlyLatin(message):
+		replies = senseiGeneralRepliesEN
+	case matched:
+		replies = senseiTopicReplies[topic]
+	default:
+		replies = senseiGeneralReplies
+	}
+
+	return pick(replies, strconv.FormatInt(userID, 10), message, strconv.Itoa(len(history))), nil
+}
+
+// ChatStream - заготовленный ответ, отданный по словам
+func (c *HeuristicClient) ChatStream(ctx context.Context, userID int64, message string, history []ports.ChatMessage) (<-chan ports.ChatDelta, error) {
+	reply, err := c.Chat(ctx, userID, message, history)
+	if err != nil {
+		return nil, err
+	}
+
+	deltas := make(chan ports.ChatDelta)
+	go func() {
+		defer close(deltas)
+		for _, word := range strings.SplitAfter(reply, " ") {
+			select {
+			case deltas <- ports.ChatDelta{Text: word}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return deltas, nil
+}
+
+// Summarize - без модели просто сохраняем начала вопросов игрока
+func (c *HeuristicClient) Summarize(ctx context.Context, previousSummary string, messages []ports.ChatMessage) (string, error) {
+	var lines []string
+	if previousSummary != "" {
+		lines = append(lines, previousSummary)
+	}
+
+	for _, m := range messages {
+		if m.Role != ports.RoleUser {
+			continue
+		}
+		question := []rune(strings.TrimSpace(m.Content))
+		if len(question) > 80 {
+			question = append(question[:80], '…')
+		}
+		lines = append(lines, "Игрок спрашивал: "+string(question))
+	}
+
+	// Держим содержание коротким: только последние записи
+	if len(lines) > 10 {
+		lines = lines[len(lines)-10:]
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// GenerateUrgentCall - шаблон вызова; меняется раз в час для каждого игрока
+func (c *HeuristicClient) GenerateUrgentCall(ctx context.Context, userID int64) (*ports.UrgentCallSuggestion, error) {
+	hour := c.now().UTC().Truncate(time.Hour).Unix()
+	suggestion := urgentCallTemplates[index(len(urgentCallTemplates), strconv.FormatInt(userID, 10), strconv.FormatInt(hour, 10))]
+	return &suggestion, nil
+}
+
+func classifyTaskType(tokens []string) domain.TaskType {
+	best := domain.TypeIntelligence
+	bestScore := 0
+
+	// Фиксированный порядок, чтобы ничьи решались одинаково
+	for _, taskType := range []domain.TaskType{domain.TypeStrength, domain.TypeAgility, domain.TypeIntelligence, domain.TypeInsight} {
+		score := countKeywordMatches(tokens, taskTypeKeywords[taskType])
+		if score > bestScore {
+			best = taskType
+			bestScore = score
+		}
+	}
+
+	return best
+}
+
+func estimateDifficulty(text string, tokens []string, descriptionLen int) int {
+	difficulty := 2
+
+	// Длительность важнее всего остального
+	minutes := 0
+	if m := hoursPattern.FindStringSubmatch(text); m != nil {
+		h, _ := strconv.Atoi(m[1])
+		minutes = h * 60
+	} else if m := minutesPattern.FindStringSubmatch(text); m != nil {
+		minutes, _ = strconv.Atoi(m[1])
+	}
+
+	switch {
+	case minutes == 0:
+	case minutes <= 10:
+		difficulty = 1
+	case minutes <= 30:
+		difficulty = 2
+	case minutes <= 60:
+		difficulty = 3
+	case minutes <= 120:
+		difficulty = 5
+	default:
+		difficulty = 7
+	}
+
+	if m := distancePattern.FindStringSubmatch(text); m != nil {
+		km, _ := strconv.Atoi(m[1])
+		switch {
+		case km >= 20:
+			difficulty += 5
+		case km >= 10:
+			difficulty += 3
+		case km >= 5:
+			difficulty += 2
+		default:
+			difficulty++
+		}
+	} else if minutes == 0 {
+		// Количество повторений: "100 отжиманий"
+		for _, raw := range countPattern.FindAllString(text, -1) {
+			n, _ := strconv.Atoi(raw)
+			switch {
+			case n >= 100:
+				difficulty += 2
+			case n >= 50:
+				difficulty++
+			}
+		}
+	}
+
+	difficulty += 2 * min(countKeywordMatches(tokens, hardKeywords), 1)
+	difficulty -= min(countKeywordMatches(tokens, easyKeywords), 1)
+
+	// Подробное описание обычно у больших задач
+	if descriptionLen > 100 {
+		difficulty++
+	}
+	if descriptionLen > 300 {
+		difficulty++
+	}
+
+	return clamp(difficulty, 1, 10)
+}
+
+// words - слова текста в нижнем регистре; дефис остается частью слова ("push-up")
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+	})
+}
+
+// countKeywordMatches - сколько ключевых слов нашлось среди tokens. Ключ - начало
+// слова: "пресс" находит "прессом", но не "депрессию". В ключе из нескольких слов
+// все слова, кроме последнего, должны совпасть целиком
+func countKeywordMatches(tokens []string, keywords []string) int {
+	count := 0
+	for _, kw := range keywords {
+		if hasKeyword(tokens, strings.Fields(kw)) {
+			count++
+		}
+	}
+	return count
+}
+
+func hasKeyword(tokens, parts []string) bool {
+	last := len(parts) - 1
+	for i := 0; i+last < len(tokens); i++ {
+		matched := true
+		for j, part := range parts {
+			token := tokens[i+j]
+			if j < last && token != part || j == last && !strings.HasPrefix(token, part) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func isMostlyLatin(text string) bool {
+	latin, cyrillic := 0, 0
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Latin):
+			latin++
+		case unicode.In(r, unicode.Cyrillic):
+			cyrillic++
+		}
+	}
+	return latin > cyrillic
+}
+
+// index - стабильный выбор элемента по хешу входа
+func index(n int, parts ...string) int {
+	h := fnv.New32a()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return int(h.Sum32() % uint32(n))
+}
+
+func pick(options []string, parts ...string) string {
+	return options[index(len(options), parts...)]
+}
+
+var senseiTopicReplies = map[domain.TaskType][]string{
+	domain.TypeStrength: {
+		"Сила растет не на тренировке, а в восстановлении после нее. Тренируйся, ешь белок и спи - и так каждый день.",
+		"Начни с малого: три подхода, которые ты точно сделаешь, лучше десяти, которые ты бросишь. Прибавляй понемногу.",
+		"Техника прежде веса. Охотник, который травмирован, не выходит в подземелье.",
+	},
+	domain.TypeAgility: {
+		"Ловкость - это регулярность. Десять минут растяжки каждый день дадут больше, чем час раз в неделю.",
+		"Беги в темпе, при котором можешь говорить. Скорость придет сама, если не бросишь.",
+		"Тело любит движение. Встань, пройдись, сделай разминку - прямо сейчас.",
+	},
+	domain.TypeIntelligence: {
+		"Раздели большую задачу на куски по 25 минут. Один кусок - одна маленькая победа.",
+		"Читай с карандашом: запиши три мысли после каждой главы, и знание останется с тобой.",
+		"Учись на пределе понимания, но не за ним. Если совсем непонятно - вернись на шаг назад.",
+	},
+	domain.TypeInsight: {
+		"Пять минут тишины утром стоят часа суеты днем. Дыши и наблюдай.",
+		"Вечером запиши: что получилось, что нет, что сделаешь завтра. Так растет Проницательность.",
+		"Сон - тоже тренировка. Ложись в одно время, и разум станет острее клинка.",
+	},
+}
+
+var senseiGeneralReplies = []string{
+	"Путь охотника складывается из маленьких шагов. Выбери одно задание и сделай его сегодня.",
+	"Мотивация приходит и уходит, дисциплина остается. Не жди настроения - начни.",
+	"Не сравнивай себя с другими охотниками. Сравнивай себя с собой вчерашним.",
+	"Если устал - отдохни, но не сдавайся. Восстановление - часть пути.",
+	"Срочные вызовы проверяют готовность. Будь готов, и ни один не застанет тебя врасплох.",
+}
+
+var senseiTopicRepliesEN = map[domain.TaskType][]string{
+	domain.TypeStrength: {
+		"Strength grows during recovery, not during the workout. Train, eat protein, sleep - every day.",
+		"Form before weight. An injured hunter does not enter the dungeon.",
+	},
+	domain.TypeAgility: {
+		"Agility is consistency. Ten minutes of stretching daily beats an hour once a week.",
+		"Run at a pace where you can still talk. Speed will come if you keep going.",
+	},
+	domain.TypeIntelligence: {
+		"Split the big task into 25-minute pieces. Each piece is a small victory.",
+		"Read with a pen: write down three ideas after each chapter and the knowledge stays.",
+	},
+	domain.TypeInsight: {
+		"Five minutes of silence in the morning is worth an hour of noise. Breathe and observe.",
+		"In the evening write down what worked, what did not, and what you will do tomorrow.",
+	},
+}
+
+var senseiGeneralRepliesEN = []string{
+	"A hunter's path is made of small steps. Pick one quest and finish it today.",
+	"Motivation comes and goes, discipline stays. Do not wait for the mood - begin.",
+	"Do not compare yourself to other hunters. Compare yourself to who you were yesterday.",
+}
+
+var urgentCallTemplates = []ports.UrgentCallSuggestion{
+	{Title: "50 отжиманий", Description: "Сделай 50 отжиманий в любом количестве подходов.", TaskType: domain.TypeStrength, Duration: 30},
+	{Title: "100 приседаний", Description: "100 приседаний с контролем техники, можно разбить на подходы.", TaskType: domain.TypeStrength, Duration: 45},
+	{Title: "Планка 3 минуты", Description: "Суммарно 3 минуты в планке за несколько подходов.", TaskType: domain.TypeStrength, Duration: 20},
+	{Title: "Прогулка 20 минут", Description: "Выйди на улицу и пройдись в быстром темпе.", TaskType: domain.TypeAgility, Duration: 60},
+	{Title: "Растяжка 10 минут", Description: "Разомни шею, плечи, спину и ноги.", TaskType: domain.TypeAgility, Duration: 30},
+	{Title: "Пробежка 2 км", Description: "Пробеги 2 км в комфортном темпе.", TaskType: domain.TypeAgility, Duration: 90},
+	{Title: "Прочитай 20 страниц", Description: "Любая нехудожественная книга, без телефона рядом.", TaskType: domain.TypeIntelligence, Duration: 90},
+	{Title: "Выучи 10 новых слов", Description: "Иностранный язык на выбор, проверь себя в конце.", TaskType: domain.TypeIntelligence, Duration: 45},
+	{Title: "Разбери одну сложную тему", Description: "Выбери тему, которую откладывал, и законспектируй ее.", TaskType: domain.TypeIntelligence, Duration: 120},
+	{Title: "Медитация 10 минут", Description: "Сядь ровно, закрой глаза и следи за дыханием.", TaskType: domain.TypeInsight, Duration: 30},
+	{Title: "Запиши итоги дня", Description: "Три вещи, которые получились, и одна, которую улучшишь.", TaskType: domain.TypeInsight, Duration: 30},
+	{Title: "Час без телефона", Description: "Убери телефон и проведи час без соцсетей.", TaskType: domain.TypeInsight, Duration: 120},
+}
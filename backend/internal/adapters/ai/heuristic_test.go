@@ -0,0 +1,65 @@
+// internal/adapters/ai/heuristic_test.go
+package ai
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"dojo/internal/domain"
+)
+
+func TestWords(t *testing.T) {
+	got := words("10 Push-ups, потом — бег (5км)!")
+	want := []string{"10", "push-ups", "потом", "бег", "5км"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("words = %q, want %q", got, want)
+	}
+}
+
+func TestHeuristicTaskType(t *testing.T) {
+	for _, tc := range []struct {
+		title string
+		want  domain.TaskType
+	}{
+		{"Качать пресс", domain.TypeStrength},
+		{"10 push-ups", domain.TypeStrength},
+		{"Пробежка по парку", domain.TypeAgility},
+		{"Morning run", domain.TypeAgility},
+		{"Медитация перед сном", domain.TypeInsight},
+		{"Прочитать главу книги", domain.TypeIntelligence},
+		// Ключ внутри чужого слова не считается: "пресс" в "депрессии", "run" в "brunch"
+		{"Поговорить с психологом о депрессии", domain.TypeIntelligence},
+		{"Brunch with friends", domain.TypeIntelligence},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			analysis, err := NewHeuristicClient().AnalyzeTask(context.Background(), tc.title, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if analysis.TaskType != tc.want {
+				t.Errorf("type = %s, want %s", analysis.TaskType, tc.want)
+			}
+		})
+	}
+}
+
+func TestCountKeywordMatches(t *testing.T) {
+	for _, tc := range []struct {
+		text     string
+		keywords []string
+		want     int
+	}{
+		{"отжимания до отказа", []string{"до отказа"}, 1},
+		{"до отказов", []string{"до отказа"}, 0},
+		{"дом отказа", []string{"до отказа"}, 0},
+		{"read a bit", []string{"a bit"}, 1},
+		{"lifting weights", []string{"lift", "weight", "gym"}, 2},
+		{"deadlifts", []string{"lift"}, 0},
+		{"", []string{"run"}, 0},
+	} {
+		if got := countKeywordMatches(words(tc.text), tc.keywords); got != tc.want {
+			t.Errorf("countKeywordMatches(%q, %q) = %d, want %d", tc.text, tc.keywords, got, tc.want)
+		}
+	}
+}
@@ -1 +1,259 @@
+// internal/adapters/ai/openai_client.go
 package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+)
+
+const (
+	DefaultOpenAIBaseURL = "https://api.openai.com/v1"
+	DefaultOpenAIModel   = "gpt-4o-mini"
+)
+
+// OpenAIClient - AIService поверх OpenAI Chat Completions
+type OpenAIClient struct {
+	config Config
+	http   *http.Client
+	policy retryPolicy
+}
+
+func NewOpenAIClient(config Config) *OpenAIClient {
+	if config.BaseURL == "" {
+		config.BaseURL = DefaultOpenAIBaseURL
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+
+	if config.Model == "" {
+		config.Model = DefaultOpenAIModel
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 30 * time.Second
+	}
+	if config.RetryDelay == 0 {
+		config.RetryDelay = 500 * time.Millisecond
+	}
+
+	return &OpenAIClient{
+		config: config,
+		http:   &http.Client{},
+		policy: retryPolicy{
+			Timeout:    config.Timeout,
+			MaxRetries: config.MaxRetries,
+			BaseDelay:  config.RetryDelay,
+		},
+	}
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIResponseFormat struct {
+	Type       string            `json:"type"`
+	JSONSchema *openAIJSONSchema `json:"json_schema,omitempty"`
+}
+
+type openAIJSONSchema struct {
+	Name   string                 `json:"name"`
+	Strict bool                   `json:"strict"`
+	Schema map[string]interface{} `json:"schema"`
+}
+
+type openAIRequest struct {
+	Model          string                `json:"model"`
+	Messages       []openAIMessage       `json:"messages"`
+	Temperature    float64               `json:"temperature"`
+	MaxTokens      int                   `json:"max_tokens,omitempty"`
+	User           string                `json:"user,omitempty"`
+	ResponseFormat *openAIResponseFormat `json:"response_format,omitempty"`
+	Stream         bool                  `json:"stream,omitempty"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+			Refusal string `json:"refusal"`
+		} `json:"message"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+}
+
+type openAIStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+}
+
+// AnalyzeTask - тип и сложность задания через структурированный вывод
+func (c *OpenAIClient) AnalyzeTask(ctx context.Context, title, description string) (*ports.TaskAnalysis, error) {
+	req := openAIRequest{
+		Model: c.config.Model,
+		Messages: []openAIMessage{
+			{Role: "system", Content: analyzePrompt},
+			{Role: "user", Content: analyzeUserMessage(title, description)},
+		},
+		Temperature:    0.2,
+		ResponseFormat: jsonSchemaFormat("task_analysis", taskAnalysisSchema),
+	}
+
+	content, err := c.complete(ctx, req, domain.ErrAIAnalysisFailed)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeAnalysis([]byte(content))
+}
+
+// Chat - ответ Сенсея с учетом истории
+func (c *OpenAIClient) Chat(ctx context.Context, userID int64, message string, history []ports.ChatMessage) (string, error) {
+	return c.complete(ctx, c.chatRequest(userID, message, history), domain.ErrAIServiceUnavailable)
+}
+
+// ChatStream - ответ Сенсея потоком (stream: true)
+func (c *OpenAIClient) ChatStream(ctx context.Context, userID int64, message string, history []ports.ChatMessage) (<-chan ports.ChatDelta, error) {
+	req := c.chatRequest(userID, message, history)
+	req.Stream = true
+
+	body, err := openStream(ctx, c.http, c.policy, c.config.BaseURL+"/chat/completions", c.headers(), req)
+	if err != nil {
+		log.Println("OpenAI: запрос не удался:", err)
+		return nil, domain.ErrAIServiceUnavailable
+	}
+
+	return relayStream(ctx, "OpenAI", body, parseOpenAIStreamEvent), nil
+}
+
+func (c *OpenAIClient) chatRequest(userID int64, message string, history []ports.ChatMessage) openAIRequest {
+	messages := []openAIMessage{{Role: "system", Content: senseiPrompt}}
+	for _, m := range history {
+		messages = append(messages, openAIMessage{Role: m.Role, Content: m.Content})
+	}
+	messages = append(messages, openAIMessage{Role: "user", Content: message})
+
+	return openAIRequest{
+		Model:       c.config.Model,
+		Messages:    messages,
+		Temperature: 0.7,
+		MaxTokens:   600,
+		User:        userTag(userID),
+	}
+}
+
+// parseOpenAIStreamEvent - чанк chat.completion.chunk; поток завершается строкой [DONE]
+func parseOpenAIStreamEvent(event sseEvent) (string, bool, error) {
+	if event.Data == "[DONE]" {
+		return "", true, nil
+	}
+
+	var chunk openAIStreamChunk
+	if err := json.Unmarshal([]byte(event.Data), &chunk); err != nil {
+		return "", false, fmt.Errorf("неожиданный чанк: %s", truncate(event.Data, 200))
+	}
+	if len(chunk.Choices) == 0 {
+		return "", false, nil
+	}
+
+	choice := chunk.Choices[0]
+	if choice.FinishReason == "content_filter" {
+		return "", false, fmt.Errorf("finish_reason=%s", choice.FinishReason)
+	}
+	return choice.Delta.Content, false, nil
+}
+
+// Summarize - сжатие старых реплик диалога
+func (c *OpenAIClient) Summarize(ctx context.Context, previousSummary string, messages []ports.ChatMessage) (string, error) {
+	req := openAIRequest{
+		Model: c.config.Model,
+		Messages: []openAIMessage{
+			{Role: "system", Content: summarizePrompt},
+			{Role: "user", Content: summaryUserMessage(previousSummary, messages)},
+		},
+		Temperature: 0.2,
+		MaxTokens:   400,
+	}
+
+	return c.complete(ctx, req, domain.ErrAIServiceUnavailable)
+}
+
+// GenerateUrgentCall - предложение срочного вызова
+func (c *OpenAIClient) GenerateUrgentCall(ctx context.Context, userID int64) (*ports.UrgentCallSuggestion, error) {
+	req := openAIRequest{
+		Model: c.config.Model,
+		Messages: []openAIMessage{
+			{Role: "system", Content: urgentCallPrompt},
+			{Role: "user", Content: "Сгенерируй срочный вызов."},
+		},
+		Temperature:    0.9,
+		User:           userTag(userID),
+		ResponseFormat: jsonSchemaFormat("urgent_call", urgentCallSchema),
+	}
+
+	content, err := c.complete(ctx, req, domain.ErrAIAnalysisFailed)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeUrgentCall([]byte(content))
+}
+
+// complete - запрос к /chat/completions; badAnswer возвращается, если
+// провайдер ответил, но ответ непригоден (отказ, обрезан, пустой)
+func (c *OpenAIClient) complete(ctx context.Context, req openAIRequest, badAnswer error) (string, error) {
+	body, err := postJSON(ctx, c.http, c.policy, c.config.BaseURL+"/chat/completions", c.headers(), req)
+	if err != nil {
+		log.Println("OpenAI: запрос не удался:", err)
+		return "", domain.ErrAIServiceUnavailable
+	}
+
+	var resp openAIResponse
+	if err := json.Unmarshal(body, &resp); err != nil || len(resp.Choices) == 0 {
+		log.Println("OpenAI: неожиданный ответ:", truncate(string(body), 500))
+		return "", badAnswer
+	}
+
+	choice := resp.Choices[0]
+	if choice.Message.Refusal != "" || choice.FinishReason == "length" || choice.FinishReason == "content_filter" {
+		log.Printf("OpenAI: ответ непригоден (finish_reason=%s, refusal=%q)", choice.FinishReason, choice.Message.Refusal)
+		return "", badAnswer
+	}
+
+	content := strings.TrimSpace(choice.Message.Content)
+	if content == "" {
+		return "", badAnswer
+	}
+
+	return content, nil
+}
+
+func (c *OpenAIClient) headers() map[string]string {
+	headers := map[string]string{}
+	if c.config.APIKey != "" {
+		headers["Authorization"] = "Bearer " + c.config.APIKey
+	}
+	return headers
+}
+
+func jsonSchemaFormat(name string, schema map[string]interface{}) *openAIResponseFormat {
+	return &openAIResponseFormat{
+		Type: "json_schema",
+		JSONSchema: &openAIJSONSchema{
+			Name:   name,
+			Strict: true,
+			Schema: schema,
+		},
+	}
+}
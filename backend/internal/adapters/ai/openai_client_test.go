@@ -0,0 +1,125 @@
+// internal/adapters/ai/openai_client_test.go
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"dojo/internal/domain"
+)
+
+// openAIContent - ответ Chat Completions с одним вариантом
+func openAIContent(content, finishReason string) fakeResponse {
+	body, _ := json.Marshal(map[string]interface{}{
+		"choices": []map[string]interface{}{{
+			"message":       map[string]string{"content": content},
+			"finish_reason": finishReason,
+		}},
+	})
+	return ok(string(body))
+}
+
+func TestOpenAIAnalyzeTaskJSONSchema(t *testing.T) {
+	p := newFakeProvider(t, openAIContent(`{"task_type":"insight","difficulty":3,"explanation":"10 минут"}`, "stop"))
+
+	analysis, err := NewOpenAIClient(testConfig(p.URL)).AnalyzeTask(context.Background(), "Медитация", "утром")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if analysis.TaskType != domain.TypeInsight || analysis.Difficulty != 3 || analysis.GoldReward != 15 || analysis.EnergyCost != 11 {
+		t.Errorf("analysis = %+v", analysis)
+	}
+
+	r := <-p.requests
+	if r.URL.Path != "/chat/completions" || r.Header.Get("Authorization") != "Bearer test-key" {
+		t.Errorf("request %s with headers %v", r.URL.Path, r.Header)
+	}
+
+	var req openAIRequest
+	if err := json.Unmarshal(<-p.bodies, &req); err != nil {
+		t.Fatal(err)
+	}
+	format := req.ResponseFormat
+	if format == nil || format.Type != "json_schema" || format.JSONSchema == nil ||
+		format.JSONSchema.Name != "task_analysis" || !format.JSONSchema.Strict {
+		t.Fatalf("response_format = %+v", format)
+	}
+	if len(req.Messages) != 2 || req.Messages[1].Content != "Задание: Медитация\nОписание: утром" {
+		t.Errorf("messages = %+v", req.Messages)
+	}
+}
+
+func TestOpenAIGenerateUrgentCall(t *testing.T) {
+	p := newFakeProvider(t, openAIContent(`{"title":" Планка ","description":"3 подхода","task_type":"strength","duration_minutes":5}`, "stop"))
+
+	call, err := NewOpenAIClient(testConfig(p.URL)).GenerateUrgentCall(context.Background(), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if call.Title != "Планка" || call.TaskType != domain.TypeStrength || call.Duration != 15 {
+		t.Errorf("call = %+v, want trimmed title and duration clamped to 15", call)
+	}
+}
+
+func TestOpenAIErrors(t *testing.T) {
+	refusal, _ := json.Marshal(map[string]interface{}{
+		"choices": []map[string]interface{}{{
+			"message":       map[string]string{"refusal": "не могу"},
+			"finish_reason": "stop",
+		}},
+	})
+
+	for _, tc := range []struct {
+		name     string
+		response fakeResponse
+		want     error
+		calls    int32
+	}{
+		{"refusal", ok(string(refusal)), domain.ErrAIAnalysisFailed, 1},
+		{"truncated", openAIContent(`{"task_type":"str`, "length"), domain.ErrAIAnalysisFailed, 1},
+		{"content filter", openAIContent(`{}`, "content_filter"), domain.ErrAIAnalysisFailed, 1},
+		{"zero difficulty", openAIContent(`{"task_type":"agility","difficulty":0,"explanation":""}`, "stop"), domain.ErrAIAnalysisFailed, 1},
+		{"no choices", ok(`{"choices":[]}`), domain.ErrAIAnalysisFailed, 1},
+		{"unauthorized is not retried", fakeResponse{status: http.StatusUnauthorized, body: `{}`}, domain.ErrAIServiceUnavailable, 1},
+		{"server error is retried", fakeResponse{status: http.StatusBadGateway, body: `{}`}, domain.ErrAIServiceUnavailable, 3},
+		{"rate limit is retried", fakeResponse{status: http.StatusTooManyRequests, body: `{}`}, domain.ErrAIServiceUnavailable, 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := newFakeProvider(t, tc.response)
+
+			_, err := NewOpenAIClient(testConfig(p.URL)).AnalyzeTask(context.Background(), "Задание", "")
+			if err != tc.want {
+				t.Errorf("err = %v, want %v", err, tc.want)
+			}
+			if got := p.calls.Load(); got != tc.calls {
+				t.Errorf("calls = %d, want %d", got, tc.calls)
+			}
+		})
+	}
+}
+
+func TestOpenAIRecoversAfterServerError(t *testing.T) {
+	p := newFakeProvider(t,
+		fakeResponse{status: http.StatusServiceUnavailable, body: `{}`},
+		openAIContent("Отдохни и продолжай.", "stop"),
+	)
+
+	reply, err := NewOpenAIClient(testConfig(p.URL)).Chat(context.Background(), 1, "устал", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply != "Отдохни и продолжай." || p.calls.Load() != 2 {
+		t.Errorf("reply = %q after %d calls", reply, p.calls.Load())
+	}
+}
+
+func TestOpenAIEmptyChatIsUnavailable(t *testing.T) {
+	p := newFakeProvider(t, openAIContent("  ", "stop"))
+
+	_, err := NewOpenAIClient(testConfig(p.URL)).Chat(context.Background(), 1, "привет", nil)
+	if err != domain.ErrAIServiceUnavailable {
+		t.Errorf("err = %v, want ErrAIServiceUnavailable", err)
+	}
+}
@@ -0,0 +1,191 @@
+// internal/adapters/ai/prompts.go
+package ai
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+)
+
+// Промпты и JSON-схемы, общие для всех провайдеров
+
+const senseiPrompt = `Ты - Сенсей в Додзё, игре в стиле Solo Leveling, где реальные дела игрока превращаются в задания.
+Отвечай коротко (до 5 предложений), по делу и в образе мудрого, но строгого наставника.
+Помогай с тренировками, учебой, режимом и мотивацией. Не выдумывай факты о прогрессе игрока.
+Отвечай на языке вопроса.`
+
+const analyzePrompt = `Ты оцениваешь задание игрока в Додзё.
+Определи тип задания:
+- strength: физическая сила, тренировки, спорт
+- agility: ловкость, скорость, бег, растяжка, координация
+- intelligence: учеба, чтение, работа, навыки
+- insight: медитация, рефлексия, дневник, осознанность, сон
+Оцени сложность от 1 (пара минут) до 10 (целый день усилий).
+Коротко объясни оценку на языке задания.`
+
+const urgentCallPrompt = `Придумай "срочный вызов" для игрока Додзё: короткое реальное задание,
+которое можно выполнить за 15-240 минут (отжимания, прогулка, чтение главы, уборка и т.п.).
+Название до 60 символов, описание в 1-2 предложениях, на русском языке.`
+
+const summarizePrompt = `Ты ведешь заметки Сенсея о диалоге с игроком Додзё.
+Сожми переписку в краткое содержание до 150 слов: цели и проблемы игрока,
+данные советы и договоренности. Если есть предыдущее содержание - дополни его.
+Пиши от третьего лица, без вступлений.`
+
+// taskAnalysisSchema - схема ответа для AnalyzeTask
+var taskAnalysisSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"task_type": map[string]interface{}{
+			"type": "string",
+			"enum": []string{"strength", "agility", "intelligence", "insight"},
+		},
+		// Диапазоны не через minimum/maximum: strict-режим поддерживает их не везде,
+		// границы проверяем при разборе
+		"difficulty": map[string]interface{}{
+			"type":        "integer",
+			"description": "Сложность от 1 до 10",
+		},
+		"explanation": map[string]interface{}{"type": "string"},
+	},
+	"required":             []string{"task_type", "difficulty", "explanation"},
+	"additionalProperties": false,
+}
+
+// urgentCallSchema - схема ответа для GenerateUrgentCall
+var urgentCallSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"title":       map[string]interface{}{"type": "string"},
+		"description": map[string]interface{}{"type": "string"},
+		"task_type": map[string]interface{}{
+			"type": "string",
+			"enum": []string{"strength", "agility", "intelligence", "insight"},
+		},
+		"duration_minutes": map[string]interface{}{
+			"type":        "integer",
+			"description": "Время на выполнение, от 15 до 240 минут",
+		},
+	},
+	"required":             []string{"title", "description", "task_type", "duration_minutes"},
+	"additionalProperties": false,
+}
+
+type analysisPayload struct {
+	TaskType    string `json:"task_type"`
+	Difficulty  int    `json:"difficulty"`
+	Explanation string `json:"explanation"`
+}
+
+type urgentCallPayload struct {
+	Title           string `json:"title"`
+	Description     string `json:"description"`
+	TaskType        string `json:"task_type"`
+	DurationMinutes int    `json:"duration_minutes"`
+}
+
+func analyzeUserMessage(title, description string) string {
+	if description == "" {
+		return "Задание: " + title
+	}
+	return "Задание: " + title + "\nОписание: " + description
+}
+
+// summaryUserMessage - предыдущее содержание и новые реплики одним текстом
+func summaryUserMessage(previousSummary string, messages []ports.ChatMessage) string {
+	var sb strings.Builder
+
+	if previousSummary != "" {
+		sb.WriteString("Предыдущее содержание:\n")
+		sb.WriteString(previousSummary)
+		sb.WriteString("\n\n")
+	}
+
+	sb.WriteString("Новые реплики:\n")
+	for _, m := range messages {
+		role := "Игрок"
+		if m.Role == ports.RoleAssistant {
+			role = "Сенсей"
+		}
+		sb.WriteString(role + ": " + m.Content + "\n")
+	}
+
+	return sb.String()
+}
+
+// parseTaskType - только известные типы заданий
+func parseTaskType(v string) (domain.TaskType, bool) {
+	switch t := domain.TaskType(strings.ToLower(strings.TrimSpace(v))); t {
+	case domain.TypeStrength, domain.TypeAgility, domain.TypeIntelligence, domain.TypeInsight:
+		return t, true
+	}
+	return "", false
+}
+
+// analysisFromDifficulty - награды считаем сами по сложности, модели их не доверяем
+func analysisFromDifficulty(taskType domain.TaskType, difficulty int, explanation string) *ports.TaskAnalysis {
+	difficulty = clamp(difficulty, 1, 10)
+
+	return &ports.TaskAnalysis{
+		TaskType:    taskType,
+		Difficulty:  difficulty,
+		XPReward:    10 * difficulty,
+		GoldReward:  5 * difficulty,
+		EnergyCost:  clamp(5+2*difficulty, 5, 30),
+		Explanation: explanation,
+	}
+}
+
+// decodeAnalysis - разбор и проверка структурированного ответа
+func decodeAnalysis(raw []byte) (*ports.TaskAnalysis, error) {
+	var payload analysisPayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, domain.ErrAIAnalysisFailed
+	}
+
+	taskType, ok := parseTaskType(payload.TaskType)
+	if !ok || payload.Difficulty == 0 {
+		return nil, domain.ErrAIAnalysisFailed
+	}
+
+	return analysisFromDifficulty(taskType, payload.Difficulty, payload.Explanation), nil
+}
+
+// decodeUrgentCall - разбор и проверка предложения срочного вызова
+func decodeUrgentCall(raw []byte) (*ports.UrgentCallSuggestion, error) {
+	var payload urgentCallPayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, domain.ErrAIAnalysisFailed
+	}
+
+	taskType, ok := parseTaskType(payload.TaskType)
+	title := strings.TrimSpace(payload.Title)
+	if !ok || title == "" {
+		return nil, domain.ErrAIAnalysisFailed
+	}
+
+	return &ports.UrgentCallSuggestion{
+		Title:       title,
+		Description: strings.TrimSpace(payload.Description),
+		TaskType:    taskType,
+		Duration:    clamp(payload.DurationMinutes, 15, 240),
+	}, nil
+}
+
+// userTag - идентификатор игрока для провайдера (мониторинг злоупотреблений)
+func userTag(userID int64) string {
+	return "dojo-" + strconv.FormatInt(userID, 10)
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
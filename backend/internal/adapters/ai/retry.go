@@ -0,0 +1,212 @@
+// internal/adapters/ai/retry.go
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// retryPolicy - таймауты и повторы HTTP-запросов к провайдеру
+type retryPolicy struct {
+	// Таймаут одной попытки
+	Timeout time.Duration
+	// Сколько раз повторять после первой неудачи
+	MaxRetries int
+	// Базовая пауза, удваивается с каждой попыткой
+	BaseDelay time.Duration
+}
+
+// httpStatusError - провайдер ответил не 2xx
+type httpStatusError struct {
+	Status int
+	Body   string
+	// Пауза из заголовка Retry-After, если сервер ее прислал
+	RetryAfter time.Duration
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.Status, e.Body)
+}
+
+// retryable - 429 и 5xx имеет смысл повторить, остальные 4xx нет
+func (e *httpStatusError) retryable() bool {
+	return e.Status == http.StatusTooManyRequests || e.Status >= 500
+}
+
+// postJSON - POST с JSON-телом, повторами и экспоненциальной паузой
+func postJSON(ctx context.Context, client *http.Client, policy retryPolicy, url string, headers map[string]string, payload interface{}) ([]byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var respBody []byte
+	err = withRetries(ctx, policy, func() error {
+		var err error
+		respBody, err = postOnce(ctx, client, policy.Timeout, url, headers, body)
+		return err
+	})
+	return respBody, err
+}
+
+// openStream - POST с потоковым ответом. Повторяется только установка соединения:
+// таймаут policy.Timeout действует до заголовков ответа, дальше поток ограничен лишь ctx
+func openStream(ctx context.Context, client *http.Client, policy retryPolicy, url string, headers map[string]string, payload interface{}) (io.ReadCloser, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var stream io.ReadCloser
+	err = withRetries(ctx, policy, func() error {
+		var err error
+		stream, err = streamOnce(ctx, client, policy.Timeout, url, headers, body)
+		return err
+	})
+	return stream, err
+}
+
+// withRetries - повторяет attempt, пока ошибка имеет смысл повторять
+func withRetries(ctx context.Context, policy retryPolicy, attempt func() error) error {
+	var lastErr error
+	for i := 0; i <= policy.MaxRetries; i++ {
+		if i > 0 {
+			delay := backoff(policy.BaseDelay, i, lastErr)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+
+		lastErr = attempt()
+		if lastErr == nil {
+			return nil
+		}
+
+		// Отмена вызывающим и ошибки клиента не повторяем
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var statusErr *httpStatusError
+		if errors.As(lastErr, &statusErr) && !statusErr.retryable() {
+			return lastErr
+		}
+	}
+
+	return lastErr
+}
+
+func postOnce(ctx context.Context, client *http.Client, timeout time.Duration, url string, headers map[string]string, body []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resp, err := doPost(ctx, client, url, headers, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return io.ReadAll(resp.Body)
+}
+
+func streamOnce(ctx context.Context, client *http.Client, timeout time.Duration, url string, headers map[string]string, body []byte) (io.ReadCloser, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	timer := time.AfterFunc(timeout, cancel)
+
+	resp, err := doPost(ctx, client, url, headers, body)
+	// Stop вернет false, если таймаут уже успел отменить запрос
+	if !timer.Stop() && err == nil {
+		resp.Body.Close()
+		err = context.DeadlineExceeded
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return &streamBody{ReadCloser: resp.Body, cancel: cancel}, nil
+}
+
+// streamBody - тело потокового ответа; Close освобождает и контекст запроса
+type streamBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *streamBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// doPost - один POST; ответ не 2xx превращается в httpStatusError
+func doPost(ctx context.Context, client *http.Client, url string, headers map[string]string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, &httpStatusError{
+			Status:     resp.StatusCode,
+			Body:       truncate(string(respBody), 500),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+
+	return resp, nil
+}
+
+func backoff(base time.Duration, attempt int, lastErr error) time.Duration {
+	var statusErr *httpStatusError
+	if errors.As(lastErr, &statusErr) && statusErr.RetryAfter > 0 {
+		return statusErr.RetryAfter
+	}
+
+	delay := base << (attempt - 1)
+	// Джиттер до 25%, чтобы реплики не били в провайдера синхронно
+	jitter := time.Duration(rand.Int63n(int64(delay)/4 + 1))
+	return delay + jitter
+}
+
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	seconds, err := strconv.Atoi(v)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	// Не ждем дольше минуты, даже если сервер просит
+	if seconds > 60 {
+		seconds = 60
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
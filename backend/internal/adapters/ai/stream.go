@@ -0,0 +1,107 @@
+// internal/adapters/ai/stream.go
+package ai
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log"
+	"strings"
+
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+)
+
+// sseEvent - одно событие text/event-stream
+type sseEvent struct {
+	Name string
+	Data string
+}
+
+// sseParser - разбирает событие провайдера: текст ответа, признак конца, ошибка
+type sseParser func(event sseEvent) (text string, done bool, err error)
+
+// relayStream - читает SSE-поток провайдера в фоне и отдает фрагменты в канал.
+// Поток без явного признака конца считается оборванным
+func relayStream(ctx context.Context, provider string, body io.ReadCloser, parse sseParser) <-chan ports.ChatDelta {
+	deltas := make(chan ports.ChatDelta)
+
+	go func() {
+		defer close(deltas)
+		defer body.Close()
+
+		send := func(delta ports.ChatDelta) bool {
+			select {
+			case deltas <- delta:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		err := readSSE(body, func(event sseEvent) (bool, error) {
+			text, done, err := parse(event)
+			if err != nil {
+				return false, err
+			}
+			if text != "" && !send(ports.ChatDelta{Text: text}) {
+				return false, ctx.Err()
+			}
+			return done, nil
+		})
+		if err == nil {
+			return
+		}
+
+		if ctx.Err() == nil {
+			log.Printf("%s: поток ответа оборвался: %v", provider, err)
+		}
+		send(ports.ChatDelta{Err: domain.ErrAIServiceUnavailable})
+	}()
+
+	return deltas
+}
+
+// readSSE - вызывает handle для каждого события, пока тот не сообщит о конце.
+// Если поток закончился раньше, возвращает io.ErrUnexpectedEOF
+func readSSE(r io.Reader, handle func(event sseEvent) (bool, error)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var (
+		event sseEvent
+		data  []string
+	)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		// Пустая строка завершает событие
+		if line == "" {
+			if len(data) == 0 {
+				event = sseEvent{}
+				continue
+			}
+			event.Data = strings.Join(data, "\n")
+			done, err := handle(event)
+			if err != nil || done {
+				return err
+			}
+			event, data = sseEvent{}, nil
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event.Name = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return io.ErrUnexpectedEOF
+}
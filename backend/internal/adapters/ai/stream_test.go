@@ -0,0 +1,180 @@
+// internal/adapters/ai/stream_test.go
+package ai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+)
+
+func sse(body string) fakeResponse {
+	return fakeResponse{
+		status:  http.StatusOK,
+		headers: map[string]string{"Content-Type": "text/event-stream"},
+		body:    body,
+	}
+}
+
+// collect - весь поток: склеенный текст и ошибка последнего фрагмента
+func collect(t *testing.T, deltas <-chan ports.ChatDelta) (string, error) {
+	t.Helper()
+
+	var (
+		sb  strings.Builder
+		err error
+	)
+	for delta := range deltas {
+		if delta.Err != nil {
+			err = delta.Err
+			continue
+		}
+		sb.WriteString(delta.Text)
+	}
+	return sb.String(), err
+}
+
+func TestReadSSE(t *testing.T) {
+	stream := ": keep-alive\n" +
+		"event: first\n" +
+		"data: раз\n" +
+		"data: два\n" +
+		"\n" +
+		"\n" +
+		"data:без пробела\n" +
+		"\n" +
+		"event: last\n" +
+		"data: end\n" +
+		"\n" +
+		"data: after end\n\n"
+
+	var events []sseEvent
+	err := readSSE(strings.NewReader(stream), func(event sseEvent) (bool, error) {
+		events = append(events, event)
+		return event.Name == "last", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []sseEvent{
+		{Name: "first", Data: "раз\nдва"},
+		{Data: "без пробела"},
+		{Name: "last", Data: "end"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("events = %+v", events)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("event %d = %+v, want %+v", i, events[i], want[i])
+		}
+	}
+}
+
+func TestReadSSEUnexpectedEOF(t *testing.T) {
+	err := readSSE(strings.NewReader("data: a\n\ndata: b\n"), func(sseEvent) (bool, error) {
+		return false, nil
+	})
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("err = %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestAnthropicChatStream(t *testing.T) {
+	p := newFakeProvider(t, sse(
+		"event: message_start\ndata: {\"type\":\"message_start\"}\n\n"+
+			"event: ping\ndata: {\"type\":\"ping\"}\n\n"+
+			"event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"Дыши \"}}\n\n"+
+			"event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"ровно.\"}}\n\n"+
+			"event: message_delta\ndata: {\"type\":\"message_delta\",\"delta\":{\"stop_reason\":\"end_turn\"}}\n\n"+
+			"event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n",
+	))
+
+	deltas, err := NewAnthropicClient(testConfig(p.URL)).ChatStream(context.Background(), 1, "совет", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, err := collect(t, deltas)
+	if err != nil || text != "Дыши ровно." {
+		t.Errorf("stream = %q, %v", text, err)
+	}
+
+	if !strings.Contains(string(<-p.bodies), `"stream":true`) {
+		t.Error("request is not streaming")
+	}
+}
+
+func TestAnthropicChatStreamErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name, stream, text string
+	}{
+		{"cut before message_stop", "data: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"на полу\"}}\n\n", "на полу"},
+		{"error event", "data: {\"type\":\"error\",\"error\":{\"type\":\"overloaded_error\",\"message\":\"busy\"}}\n\n", ""},
+		{"refusal", "data: {\"type\":\"message_delta\",\"delta\":{\"stop_reason\":\"refusal\"}}\n\ndata: {\"type\":\"message_stop\"}\n\n", ""},
+		{"garbage", "data: <html>\n\n", ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := newFakeProvider(t, sse(tc.stream))
+
+			deltas, err := NewAnthropicClient(testConfig(p.URL)).ChatStream(context.Background(), 1, "совет", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			text, err := collect(t, deltas)
+			if text != tc.text || err != domain.ErrAIServiceUnavailable {
+				t.Errorf("stream = %q, %v; want %q, ErrAIServiceUnavailable", text, err, tc.text)
+			}
+		})
+	}
+}
+
+func TestOpenAIChatStream(t *testing.T) {
+	p := newFakeProvider(t, sse(
+		"data: {\"choices\":[{\"delta\":{\"role\":\"assistant\"}}]}\n\n"+
+			"data: {\"choices\":[{\"delta\":{\"content\":\"Шаг \"}}]}\n\n"+
+			"data: {\"choices\":[]}\n\n"+
+			"data: {\"choices\":[{\"delta\":{\"content\":\"за шагом.\"},\"finish_reason\":\"stop\"}]}\n\n"+
+			"data: [DONE]\n\n",
+	))
+
+	deltas, err := NewOpenAIClient(testConfig(p.URL)).ChatStream(context.Background(), 1, "совет", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, err := collect(t, deltas)
+	if err != nil || text != "Шаг за шагом." {
+		t.Errorf("stream = %q, %v", text, err)
+	}
+}
+
+func TestOpenAIChatStreamWithoutDone(t *testing.T) {
+	p := newFakeProvider(t, sse("data: {\"choices\":[{\"delta\":{\"content\":\"Шаг\"}}]}\n\n"))
+
+	deltas, err := NewOpenAIClient(testConfig(p.URL)).ChatStream(context.Background(), 1, "совет", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, err := collect(t, deltas)
+	if text != "Шаг" || err != domain.ErrAIServiceUnavailable {
+		t.Errorf("stream = %q, %v; want cut stream", text, err)
+	}
+}
+
+func TestChatStreamOpenFailure(t *testing.T) {
+	p := newFakeProvider(t, fakeResponse{status: http.StatusInternalServerError, body: `{}`})
+
+	_, err := NewOpenAIClient(testConfig(p.URL)).ChatStream(context.Background(), 1, "совет", nil)
+	if err != domain.ErrAIServiceUnavailable {
+		t.Errorf("err = %v, want ErrAIServiceUnavailable", err)
+	}
+	// Установку соединения повторяем так же, как обычные запросы
+	if got := p.calls.Load(); got != 3 {
+		t.Errorf("calls = %d, want 3", got)
+	}
+}
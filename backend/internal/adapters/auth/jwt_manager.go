@@ -0,0 +1,109 @@
+// internal/adapters/auth/jwt_manager.go
+package auth
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	tokenTypeAccess  = "access"
+	tokenTypeRefresh = "refresh"
+	issuer           = "dojo"
+)
+
+// claims - содержимое JWT
+type claims struct {
+	TokenType string `json:"typ"`
+	jwt.RegisteredClaims
+}
+
+// JWTManager - выпуск и проверка JWT (HS256) на JWT_SECRET
+type JWTManager struct {
+	secret    []byte
+	accessTTL time.Duration
+}
+
+func NewJWTManager(secret string, accessTTL time.Duration) ports.TokenManager {
+	return &JWTManager{
+		secret:    []byte(secret),
+		accessTTL: accessTTL,
+	}
+}
+
+// IssueAccessToken - короткоживущий токен для запросов к API
+func (m *JWTManager) IssueAccessToken(userID int64, sessionID string) (string, time.Time, error) {
+	expiresAt := time.Now().Add(m.accessTTL)
+	token, err := m.sign(tokenTypeAccess, userID, sessionID, expiresAt)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// IssueRefreshToken - долгоживущий токен для обновления пары
+func (m *JWTManager) IssueRefreshToken(userID int64, sessionID string, expiresAt time.Time) (string, error) {
+	return m.sign(tokenTypeRefresh, userID, sessionID, expiresAt)
+}
+
+func (m *JWTManager) ParseAccessToken(token string) (*ports.TokenClaims, error) {
+	return m.parse(token, tokenTypeAccess)
+}
+
+func (m *JWTManager) ParseRefreshToken(token string) (*ports.TokenClaims, error) {
+	return m.parse(token, tokenTypeRefresh)
+}
+
+func (m *JWTManager) sign(tokenType string, userID int64, sessionID string, expiresAt time.Time) (string, error) {
+	now := time.Now()
+	c := claims{
+		TokenType: tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   strconv.FormatInt(userID, 10),
+			ID:        sessionID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, c).SignedString(m.secret)
+}
+
+func (m *JWTManager) parse(token, tokenType string) (*ports.TokenClaims, error) {
+	var c claims
+	_, err := jwt.ParseWithClaims(token, &c, func(t *jwt.Token) (interface{}, error) {
+		return m.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, domain.ErrTokenExpired
+		}
+		return nil, domain.ErrInvalidToken
+	}
+
+	if c.TokenType != tokenType {
+		return nil, domain.ErrInvalidToken
+	}
+
+	userID, err := strconv.ParseInt(c.Subject, 10, 64)
+	if err != nil || userID == 0 {
+		return nil, domain.ErrInvalidToken
+	}
+
+	return &ports.TokenClaims{
+		UserID:    userID,
+		SessionID: c.ID,
+		ExpiresAt: c.ExpiresAt.Time,
+	}, nil
+}
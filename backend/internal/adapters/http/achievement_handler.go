@@ -0,0 +1,50 @@
+// internal/adapters/http/achievement_handler.go
+package http
+
+import (
+	"dojo/internal/core"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AchievementHandler - достижения и титулы
+type AchievementHandler struct {
+	achievementService *core.AchievementService
+}
+
+func NewAchievementHandler(achievementService *core.AchievementService) *AchievementHandler {
+	return &AchievementHandler{achievementService: achievementService}
+}
+
+// List - GET /achievements
+func (h *AchievementHandler) List(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	achievements, err := h.achievementService.GetAchievements(c.Context(), userID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"achievements": achievements})
+}
+
+// SetTitle - PUT /profile/title
+func (h *AchievementHandler) SetTitle(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	var req struct {
+		// Достижение, чей титул выбрать; пусто - снять титул
+		AchievementID string `json:"achievement_id"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат"})
+	}
+
+	user, err := h.achievementService.SetTitle(c.Context(), userID, req.AchievementID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(user)
+}
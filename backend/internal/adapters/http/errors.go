@@ -0,0 +1,89 @@
+// internal/adapters/http/errors.go
+package http
+
+import (
+	"dojo/internal/domain"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// errorStatus - HTTP-статус для доменной ошибки, в том числе обернутой
+func errorStatus(err error) int {
+	switch {
+	case isAny(err,
+		domain.ErrUserNotFound,
+		domain.ErrTaskNotFound,
+		domain.ErrRaidNotFound,
+		domain.ErrQuestTemplateNotFound,
+		domain.ErrAchievementNotFound,
+		domain.ErrItemNotFound,
+		domain.ErrDeliveryNotFound,
+		domain.ErrConversationNotFound,
+		domain.ErrNotInPenaltyZone,
+	):
+		return fiber.StatusNotFound
+	case isAny(err, domain.ErrUnauthorized):
+		return fiber.StatusForbidden
+	// Запись изменили параллельно и повторы не помогли: клиент может повторить запрос
+	case isAny(err, domain.ErrConcurrentModification):
+		return fiber.StatusConflict
+	case isAny(err, domain.ErrAIServiceUnavailable):
+		return fiber.StatusServiceUnavailable
+	case isAny(err,
+		domain.ErrInsufficientGold,
+		domain.ErrInsufficientEnergy,
+		domain.ErrEnergyFull,
+		domain.ErrTooManyStreakFreezes,
+		domain.ErrNoSenseiRequests,
+		domain.ErrLicenseInactive,
+		domain.ErrTaskNotActive,
+		domain.ErrTaskNotInProgress,
+		domain.ErrTaskExpired,
+		domain.ErrTaskAlreadyStarted,
+		domain.ErrCannotRaidSelf,
+		domain.ErrPlayerNotInactive,
+		domain.ErrRaidCooldown,
+		domain.ErrTargetShielded,
+		domain.ErrTargetRecentlyRaided,
+		domain.ErrRaidDailyCapReached,
+		domain.ErrRankGapTooLarge,
+		domain.ErrNothingToLoot,
+		domain.ErrAIAnalysisFailed,
+		domain.ErrEmptyMessage,
+		domain.ErrInvalidQuietHours,
+		domain.ErrInvalidTimezone,
+		domain.ErrInvalidRecurrence,
+		domain.ErrInPenaltyZone,
+		domain.ErrTitleLocked,
+		domain.ErrNoTitle,
+		domain.ErrItemNotOwned,
+		domain.ErrItemAlreadyOwned,
+		domain.ErrItemNotUsable,
+		domain.ErrNotEquipment,
+		domain.ErrItemNotForSale,
+		domain.ErrInvalidQuantity,
+		domain.ErrXPBoostActive,
+		domain.ErrDeliveryNotDead,
+		domain.ErrUnknownResource,
+	):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// isAny - err совпадает с одной из целей или оборачивает ее
+func isAny(err error, targets ...error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// errorResponse - ответ с ошибкой и подходящим статусом
+func errorResponse(c *fiber.Ctx, err error) error {
+	return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+}
@@ -0,0 +1,31 @@
+// internal/adapters/http/errors_test.go
+package http
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"dojo/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestErrorStatus(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want int
+	}{
+		{domain.ErrTaskNotFound, fiber.StatusNotFound},
+		{domain.ErrUnauthorized, fiber.StatusForbidden},
+		{domain.ErrConcurrentModification, fiber.StatusConflict},
+		{fmt.Errorf("завершение задания 7: %w", domain.ErrConcurrentModification), fiber.StatusConflict},
+		{fmt.Errorf("обертка: %w", domain.ErrInsufficientEnergy), fiber.StatusBadRequest},
+		{domain.ErrAIServiceUnavailable, fiber.StatusServiceUnavailable},
+		{errors.New("connection reset"), fiber.StatusInternalServerError},
+	} {
+		if got := errorStatus(tc.err); got != tc.want {
+			t.Errorf("errorStatus(%v) = %d, want %d", tc.err, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// internal/adapters/http/ledger_handler.go
+package http
+
+import (
+	"dojo/internal/core"
+	"dojo/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// LedgerHandler - журнал изменений ресурсов
+type LedgerHandler struct {
+	ledgerService *core.LedgerService
+}
+
+func NewLedgerHandler(ledgerService *core.LedgerService) *LedgerHandler {
+	return &LedgerHandler{ledgerService: ledgerService}
+}
+
+// History - GET /ledger?resource=&limit=&offset=
+func (h *LedgerHandler) History(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	limit := c.QueryInt("limit", 50)
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	entries, err := h.ledgerService.History(
+		c.Context(),
+		userID,
+		domain.LedgerResource(c.Query("resource")),
+		limit,
+		c.QueryInt("offset", 0),
+	)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"entries": entries})
+}
+
+// Audit - GET /admin/ledger/audit?limit=, балансы, не сходящиеся с журналом
+func (h *LedgerHandler) Audit(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", 100)
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+
+	discrepancies, err := h.ledgerService.Audit(c.Context(), limit)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":            len(discrepancies) == 0,
+		"discrepancies": discrepancies,
+	})
+}
@@ -1 +1,256 @@
+// internal/adapters/http/middleware.go
 package http
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"dojo/internal/core"
+	"dojo/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TelegramUser - пользователь из initData Telegram Mini App
+type TelegramUser struct {
+	ID           int64  `json:"id"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name"`
+	Username     string `json:"username"`
+	PhotoURL     string `json:"photo_url"`
+	LanguageCode string `json:"language_code"`
+}
+
+// TelegramAuth - проверка подписи initData по BOT_TOKEN
+type TelegramAuth struct {
+	secretKey []byte
+	maxAge    time.Duration
+}
+
+func NewTelegramAuth(botToken string, maxAge time.Duration) *TelegramAuth {
+	// Секретный ключ: HMAC-SHA256 от токена бота с ключом "WebAppData"
+	mac := hmac.New(sha256.New, []byte("WebAppData"))
+	mac.Write([]byte(botToken))
+
+	return &TelegramAuth{
+		secretKey: mac.Sum(nil),
+		maxAge:    maxAge,
+	}
+}
+
+// Validate - проверяет подпись и свежесть initData, возвращает пользователя
+func (a *TelegramAuth) Validate(initData string) (*TelegramUser, error) {
+	values, err := url.ParseQuery(initData)
+	if err != nil {
+		return nil, domain.ErrInvalidTelegramData
+	}
+
+	// Telegram не повторяет ключи; повтор подписывался бы только первым значением
+	for _, v := range values {
+		if len(v) != 1 {
+			return nil, domain.ErrInvalidTelegramData
+		}
+	}
+
+	hash := values.Get("hash")
+	if hash == "" {
+		return nil, domain.ErrInvalidTelegramData
+	}
+
+	// Строка проверки: пары key=value без hash, отсортированные по ключу
+	pairs := make([]string, 0, len(values))
+	for key := range values {
+		if key == "hash" {
+			continue
+		}
+		pairs = append(pairs, key+"="+values.Get(key))
+	}
+	sort.Strings(pairs)
+
+	mac := hmac.New(sha256.New, a.secretKey)
+	mac.Write([]byte(strings.Join(pairs, "\n")))
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(expected), []byte(hash)) {
+		return nil, domain.ErrInvalidTelegramData
+	}
+
+	authDate, err := strconv.ParseInt(values.Get("auth_date"), 10, 64)
+	if err != nil {
+		return nil, domain.ErrInvalidTelegramData
+	}
+
+	age := time.Since(time.Unix(authDate, 0))
+	if age > a.maxAge || age < -time.Minute {
+		return nil, domain.ErrInvalidTelegramData
+	}
+
+	var user TelegramUser
+	if err := json.Unmarshal([]byte(values.Get("user")), &user); err != nil || user.ID == 0 {
+		return nil, domain.ErrInvalidTelegramData
+	}
+
+	return &user, nil
+}
+
+// bearerToken - достает токен из заголовка "Authorization: Bearer <token>"
+func bearerToken(c *fiber.Ctx) (string, error) {
+	header := c.Get(fiber.HeaderAuthorization)
+	if header == "" {
+		return "", domain.ErrUnauthorized
+	}
+
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "bearer") || token == "" {
+		return "", domain.ErrUnauthorized
+	}
+
+	return token, nil
+}
+
+// AuthMiddleware - авторизация запроса по access-токену
+func AuthMiddleware(authService *core.AuthService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, err := bearerToken(c)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		userID, err := authService.Authenticate(c.Context(), token)
+		if err != nil {
+			return c.Status(authErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		c.Locals("user_id", userID)
+		return c.Next()
+	}
+}
+
+// AdminMiddleware - доступ по общему токену в заголовке X-Admin-Token.
+// Пустой token закрывает админ-роуты целиком
+func AdminMiddleware(token string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if token == "" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Не найдено"})
+		}
+
+		got := c.Get("X-Admin-Token")
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": domain.ErrUnauthorized.Error()})
+		}
+
+		return c.Next()
+	}
+}
+
+// AuthHandler - вход через Telegram Mini App и работа с сессиями
+type AuthHandler struct {
+	auth        *TelegramAuth
+	authService *core.AuthService
+}
+
+func NewAuthHandler(auth *TelegramAuth, authService *core.AuthService) *AuthHandler {
+	return &AuthHandler{
+		auth:        auth,
+		authService: authService,
+	}
+}
+
+// Login - POST /auth/telegram, принимает init_data из Telegram.WebApp.initData
+func (h *AuthHandler) Login(c *fiber.Ctx) error {
+	var req struct {
+		InitData string `json:"init_data"`
+	}
+
+	if err := c.BodyParser(&req); err != nil || req.InitData == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": domain.ErrInvalidTelegramData.Error()})
+	}
+
+	tgUser, err := h.auth.Validate(req.InitData)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	user, tokens, err := h.authService.Login(
+		c.Context(),
+		tgUser.ID,
+		tgUser.Username,
+		tgUser.FirstName,
+		tgUser.PhotoURL,
+	)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"user":   user,
+		"tokens": tokens,
+	})
+}
+
+// Refresh - POST /auth/refresh, меняет refresh-токен на новую пару
+func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	if err := c.BodyParser(&req); err != nil || req.RefreshToken == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": domain.ErrInvalidToken.Error()})
+	}
+
+	tokens, err := h.authService.Refresh(c.Context(), req.RefreshToken)
+	if err != nil {
+		return c.Status(authErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"tokens": tokens})
+}
+
+// Logout - POST /auth/logout, отзывает refresh-токен
+func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	if err := c.BodyParser(&req); err != nil || req.RefreshToken == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": domain.ErrInvalidToken.Error()})
+	}
+
+	if err := h.authService.Logout(c.Context(), req.RefreshToken); err != nil {
+		return c.Status(authErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"success": true})
+}
+
+// LogoutAll - POST /auth/logout-all, закрывает все сессии текущего пользователя
+func (h *AuthHandler) LogoutAll(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	if err := h.authService.LogoutAll(c.Context(), userID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"success": true})
+}
+
+// authErrorStatus - ошибки токенов и сессий отдаем как 401
+func authErrorStatus(err error) int {
+	switch err {
+	case domain.ErrInvalidToken,
+		domain.ErrTokenExpired,
+		domain.ErrSessionRevoked,
+		domain.ErrUnauthorized:
+		return fiber.StatusUnauthorized
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
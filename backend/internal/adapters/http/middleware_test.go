@@ -0,0 +1,106 @@
+// internal/adapters/http/middleware_test.go
+package http
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"dojo/internal/domain"
+)
+
+const testBotToken = "123456:TEST-TOKEN"
+
+// signInitData - initData так, как его подписывает Telegram
+func signInitData(botToken string, fields map[string]string) string {
+	secret := hmac.New(sha256.New, []byte("WebAppData"))
+	secret.Write([]byte(botToken))
+
+	pairs := make([]string, 0, len(fields))
+	values := url.Values{}
+	for key, value := range fields {
+		pairs = append(pairs, key+"="+value)
+		values.Set(key, value)
+	}
+	sort.Strings(pairs)
+
+	mac := hmac.New(sha256.New, secret.Sum(nil))
+	mac.Write([]byte(strings.Join(pairs, "\n")))
+	values.Set("hash", hex.EncodeToString(mac.Sum(nil)))
+	return values.Encode()
+}
+
+// withQuery - initData после правки параметров
+func withQuery(t *testing.T, initData string, edit func(url.Values)) string {
+	t.Helper()
+
+	values, err := url.ParseQuery(initData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edit(values)
+	return values.Encode()
+}
+
+func initFields(authDate time.Time) map[string]string {
+	return map[string]string{
+		"auth_date": strconv.FormatInt(authDate.Unix(), 10),
+		"query_id":  "AAHdF6IQAAAAAN0XohDhrOrc",
+		"user":      `{"id":42,"first_name":"Кира","username":"kira"}`,
+	}
+}
+
+func TestTelegramAuthValidate(t *testing.T) {
+	auth := NewTelegramAuth(testBotToken, time.Hour)
+	now := time.Now()
+	valid := signInitData(testBotToken, initFields(now))
+
+	for _, tc := range []struct {
+		name     string
+		initData string
+		ok       bool
+	}{
+		{name: "valid", initData: valid, ok: true},
+		{name: "signed with another bot", initData: signInitData("654321:OTHER", initFields(now))},
+		{name: "tampered field", initData: strings.Replace(valid, "kira", "mallory", 1)},
+		{name: "added field", initData: valid + "&chat_type=private"},
+		{name: "missing hash", initData: withQuery(t, valid, func(v url.Values) { v.Del("hash") })},
+		{name: "empty hash", initData: withQuery(t, valid, func(v url.Values) { v.Set("hash", "") })},
+		{name: "duplicated hash", initData: valid + "&hash=00"},
+		{name: "duplicated field", initData: valid + "&user=" + url.QueryEscape(`{"id":1}`)},
+		{name: "stale auth_date", initData: signInitData(testBotToken, initFields(now.Add(-2*time.Hour)))},
+		{name: "auth_date slightly ahead", initData: signInitData(testBotToken, initFields(now.Add(30*time.Second))), ok: true},
+		{name: "auth_date in the future", initData: signInitData(testBotToken, initFields(now.Add(5*time.Minute)))},
+		{name: "auth_date not a number", initData: signInitData(testBotToken, map[string]string{
+			"auth_date": "yesterday",
+			"user":      `{"id":42}`,
+		})},
+		{name: "no user", initData: signInitData(testBotToken, map[string]string{
+			"auth_date": strconv.FormatInt(now.Unix(), 10),
+		})},
+		{name: "malformed encoding", initData: valid + "&bad=%zz"},
+		{name: "empty", initData: ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			user, err := auth.Validate(tc.initData)
+			if !tc.ok {
+				if err != domain.ErrInvalidTelegramData {
+					t.Fatalf("err = %v, want ErrInvalidTelegramData", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if user.ID != 42 || user.Username != "kira" || user.FirstName != "Кира" {
+				t.Errorf("user = %+v", user)
+			}
+		})
+	}
+}
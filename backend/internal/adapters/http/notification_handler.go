@@ -0,0 +1,63 @@
+// internal/adapters/http/notification_handler.go
+package http
+
+import (
+	"dojo/internal/core"
+	"dojo/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// NotificationHandler - настройки уведомлений игрока
+type NotificationHandler struct {
+	notificationService *core.NotificationService
+}
+
+func NewNotificationHandler(notificationService *core.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notificationService: notificationService}
+}
+
+// GetSettings - GET /notifications/settings
+func (h *NotificationHandler) GetSettings(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	settings, err := h.notificationService.GetSettings(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(settings)
+}
+
+// UpdateSettings - PUT /notifications/settings
+func (h *NotificationHandler) UpdateSettings(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	var req struct {
+		Enabled           bool `json:"enabled"`
+		QuietHoursEnabled bool `json:"quiet_hours_enabled"`
+		QuietFrom         int  `json:"quiet_from"`
+		QuietTo           int  `json:"quiet_to"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат"})
+	}
+
+	settings := &domain.NotificationSettings{
+		UserID:            userID,
+		Enabled:           req.Enabled,
+		QuietHoursEnabled: req.QuietHoursEnabled,
+		QuietFrom:         req.QuietFrom,
+		QuietTo:           req.QuietTo,
+	}
+
+	if err := h.notificationService.UpdateSettings(c.Context(), settings); err != nil {
+		if err == domain.ErrInvalidQuietHours {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(settings)
+}
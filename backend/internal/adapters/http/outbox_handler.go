@@ -0,0 +1,60 @@
+// internal/adapters/http/outbox_handler.go
+package http
+
+import (
+	"dojo/internal/core"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// OutboxHandler - доставка доменных событий подписчикам и их статистика
+type OutboxHandler struct {
+	relay     *core.EventRelay
+	analytics *core.AnalyticsService
+}
+
+func NewOutboxHandler(relay *core.EventRelay, analytics *core.AnalyticsService) *OutboxHandler {
+	return &OutboxHandler{relay: relay, analytics: analytics}
+}
+
+// DeadLetters - GET /admin/events/dead?limit=&offset=, доставки, исчерпавшие попытки
+func (h *OutboxHandler) DeadLetters(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", 50)
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	deliveries, err := h.relay.GetDeadDeliveries(c.Context(), limit, c.QueryInt("offset", 0))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"deliveries": deliveries})
+}
+
+// Retry - POST /admin/events/deliveries/:id/retry, вернуть доставку из мертвой очереди
+func (h *OutboxHandler) Retry(c *fiber.Ctx) error {
+	deliveryID, _ := c.ParamsInt("id")
+
+	delivery, err := h.relay.Retry(c.Context(), int64(deliveryID))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(delivery)
+}
+
+// Stats - GET /admin/events/stats?days=, сколько событий каждого типа было по дням
+func (h *OutboxHandler) Stats(c *fiber.Ctx) error {
+	days := c.QueryInt("days", 7)
+	if days <= 0 || days > 90 {
+		days = 7
+	}
+
+	stats, err := h.analytics.GetDaily(c.Context(), days)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"stats": stats})
+}
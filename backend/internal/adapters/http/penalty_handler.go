@@ -0,0 +1,58 @@
+// internal/adapters/http/penalty_handler.go
+package http
+
+import (
+	"dojo/internal/core"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PenaltyHandler - зона наказания за невыполненные квесты
+type PenaltyHandler struct {
+	penaltyService *core.PenaltyService
+}
+
+func NewPenaltyHandler(penaltyService *core.PenaltyService) *PenaltyHandler {
+	return &PenaltyHandler{penaltyService: penaltyService}
+}
+
+// Status - GET /penalty
+func (h *PenaltyHandler) Status(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	status, err := h.penaltyService.GetStatus(c.Context(), userID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(status)
+}
+
+// Quest - GET /penalty/quest, штрафной квест, который нужно выполнить для выхода из зоны
+func (h *PenaltyHandler) Quest(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	quest, err := h.penaltyService.GetQuest(c.Context(), userID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(quest)
+}
+
+// History - GET /penalty/history?limit=&offset=, прошлые и текущий штрафные квесты
+func (h *PenaltyHandler) History(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	limit := c.QueryInt("limit", 20)
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	quests, err := h.penaltyService.GetHistory(c.Context(), userID, limit, c.QueryInt("offset", 0))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"quests": quests})
+}
@@ -0,0 +1,85 @@
+// internal/adapters/http/quest_handler.go
+package http
+
+import (
+	"dojo/internal/core"
+	"dojo/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// QuestHandler - повторяющиеся квесты и задания на сегодня
+type QuestHandler struct {
+	questService *core.QuestService
+}
+
+func NewQuestHandler(questService *core.QuestService) *QuestHandler {
+	return &QuestHandler{questService: questService}
+}
+
+// ListTemplates - GET /quests/templates
+func (h *QuestHandler) ListTemplates(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	templates, err := h.questService.GetTemplates(c.Context(), userID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"templates": templates})
+}
+
+// CreateTemplate - POST /quests/templates
+func (h *QuestHandler) CreateTemplate(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	var req struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		TaskType    string `json:"task_type"`
+		// Правило повтора, например "FREQ=WEEKLY;BYDAY=MO,WE,FR"
+		Rule string `json:"rule"`
+	}
+
+	if err := c.BodyParser(&req); err != nil || req.Title == "" || req.TaskType == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат"})
+	}
+
+	template, err := h.questService.CreateTemplate(
+		c.Context(),
+		userID,
+		req.Title,
+		req.Description,
+		domain.TaskType(req.TaskType),
+		req.Rule,
+	)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(template)
+}
+
+// DeleteTemplate - DELETE /quests/templates/:id
+func (h *QuestHandler) DeleteTemplate(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	templateID, _ := c.ParamsInt("id")
+
+	if err := h.questService.DeleteTemplate(c.Context(), userID, int64(templateID)); err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"success": true})
+}
+
+// Today - GET /quests/today
+func (h *QuestHandler) Today(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	tasks, err := h.questService.GetToday(c.Context(), userID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"tasks": tasks})
+}
@@ -0,0 +1,92 @@
+// internal/adapters/http/raid_handler.go
+package http
+
+import (
+	"dojo/internal/core"
+	"dojo/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RaidHandler - рейды на неактивных игроков
+type RaidHandler struct {
+	raidService *core.RaidService
+}
+
+func NewRaidHandler(raidService *core.RaidService) *RaidHandler {
+	return &RaidHandler{raidService: raidService}
+}
+
+// GetTargets - GET /raids/targets
+func (h *RaidHandler) GetTargets(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	targets, err := h.raidService.GetTargets(c.Context(), userID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"targets": targets})
+}
+
+// StartRaid - POST /raids
+func (h *RaidHandler) StartRaid(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	var req struct {
+		TargetID int64 `json:"target_id"`
+	}
+
+	if err := c.BodyParser(&req); err != nil || req.TargetID == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат"})
+	}
+
+	result, err := h.raidService.StartRaid(c.Context(), userID, req.TargetID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
+// ListRaids - GET /raids?role=attacker|victim&limit=&offset=
+func (h *RaidHandler) ListRaids(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	limit := c.QueryInt("limit", 20)
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	offset := c.QueryInt("offset", 0)
+
+	var (
+		raids []*domain.Raid
+		err   error
+	)
+	switch c.Query("role", "attacker") {
+	case "attacker":
+		raids, err = h.raidService.GetAttacks(c.Context(), userID, limit, offset)
+	case "victim":
+		raids, err = h.raidService.GetDefenses(c.Context(), userID, limit, offset)
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "role: attacker или victim"})
+	}
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"raids": raids})
+}
+
+// GetRaid - GET /raids/:id
+func (h *RaidHandler) GetRaid(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	raidID, _ := c.ParamsInt("id")
+
+	raid, err := h.raidService.GetRaid(c.Context(), userID, int64(raidID))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(raid)
+}
@@ -0,0 +1,22 @@
+// internal/adapters/http/scheduler_handler.go
+package http
+
+import (
+	"dojo/internal/core"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SchedulerHandler - состояние фоновых задач
+type SchedulerHandler struct {
+	scheduler *core.Scheduler
+}
+
+func NewSchedulerHandler(scheduler *core.Scheduler) *SchedulerHandler {
+	return &SchedulerHandler{scheduler: scheduler}
+}
+
+// Jobs - GET /admin/jobs, метрики запусков на этой реплике
+func (h *SchedulerHandler) Jobs(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"jobs": h.scheduler.Stats()})
+}
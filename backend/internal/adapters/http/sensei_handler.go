@@ -0,0 +1,179 @@
+// internal/adapters/http/sensei_handler.go
+package http
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+
+	"dojo/internal/core"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SenseiHandler - диалоги с Сенсеем
+type SenseiHandler struct {
+	senseiService *core.SenseiService
+}
+
+func NewSenseiHandler(senseiService *core.SenseiService) *SenseiHandler {
+	return &SenseiHandler{senseiService: senseiService}
+}
+
+// ListConversations - GET /sensei/conversations?limit=&offset=
+func (h *SenseiHandler) ListConversations(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	limit := c.QueryInt("limit", 20)
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	conversations, err := h.senseiService.ListConversations(c.Context(), userID, limit, c.QueryInt("offset", 0))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"conversations": conversations})
+}
+
+// GetConversation - GET /sensei/conversations/:id
+func (h *SenseiHandler) GetConversation(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	conversationID, _ := c.ParamsInt("id")
+
+	conversation, messages, err := h.senseiService.GetConversation(c.Context(), userID, int64(conversationID))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"conversation": conversation,
+		"messages":     messages,
+	})
+}
+
+// StartConversation - POST /sensei/conversations, первый вопрос нового диалога
+func (h *SenseiHandler) StartConversation(c *fiber.Ctx) error {
+	return h.chat(c, 0)
+}
+
+// SendMessage - POST /sensei/conversations/:id/messages
+func (h *SenseiHandler) SendMessage(c *fiber.Ctx) error {
+	conversationID, err := c.ParamsInt("id")
+	if err != nil || conversationID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный ID диалога"})
+	}
+	return h.chat(c, int64(conversationID))
+}
+
+// StartConversationStream - POST /sensei/conversations/stream, новый диалог с ответом через SSE
+func (h *SenseiHandler) StartConversationStream(c *fiber.Ctx) error {
+	return h.chatStream(c, 0)
+}
+
+// SendMessageStream - POST /sensei/conversations/:id/messages/stream
+func (h *SenseiHandler) SendMessageStream(c *fiber.Ctx) error {
+	conversationID, err := c.ParamsInt("id")
+	if err != nil || conversationID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный ID диалога"})
+	}
+	return h.chatStream(c, int64(conversationID))
+}
+
+// DeleteConversation - DELETE /sensei/conversations/:id
+func (h *SenseiHandler) DeleteConversation(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	conversationID, _ := c.ParamsInt("id")
+
+	if err := h.senseiService.DeleteConversation(c.Context(), userID, int64(conversationID)); err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"success": true})
+}
+
+// ClearConversations - DELETE /sensei/conversations
+func (h *SenseiHandler) ClearConversations(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	if err := h.senseiService.ClearConversations(c.Context(), userID); err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"success": true})
+}
+
+func (h *SenseiHandler) chat(c *fiber.Ctx, conversationID int64) error {
+	userID := c.Locals("user_id").(int64)
+
+	var req struct {
+		Message string `json:"message"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат"})
+	}
+
+	reply, err := h.senseiService.Chat(c.Context(), userID, conversationID, req.Message)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(reply)
+}
+
+// chatStream - ответ Сенсея событиями text/event-stream:
+// "delta" с фрагментом текста, затем "done" с итогом или "error".
+// Ошибки до начала потока (нет запросов, чужой диалог) отдаются обычным JSON
+func (h *SenseiHandler) chatStream(c *fiber.Ctx, conversationID int64) error {
+	userID := c.Locals("user_id").(int64)
+
+	var req struct {
+		Message string `json:"message"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат"})
+	}
+
+	ctx := c.Context()
+	stream, err := h.senseiService.ChatStream(ctx, userID, conversationID, req.Message)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	// Отключает буферизацию ответа в nginx
+	c.Set("X-Accel-Buffering", "no")
+
+	ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		reply, err := stream.Relay(ctx, func(delta string) error {
+			if err := writeEvent(w, "delta", fiber.Map{"text": delta}); err != nil {
+				return err
+			}
+			// Ошибка Flush означает, что клиент отключился
+			return w.Flush()
+		})
+
+		if err != nil {
+			writeEvent(w, "error", fiber.Map{"error": err.Error()})
+		} else {
+			writeEvent(w, "done", reply)
+		}
+		w.Flush()
+	})
+
+	return nil
+}
+
+func writeEvent(w *bufio.Writer, event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+	return err
+}
@@ -0,0 +1,107 @@
+// internal/adapters/http/shop_handler.go
+package http
+
+import (
+	"dojo/internal/core"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ShopHandler - магазин, инвентарь и снаряжение
+type ShopHandler struct {
+	shopService *core.ShopService
+}
+
+func NewShopHandler(shopService *core.ShopService) *ShopHandler {
+	return &ShopHandler{shopService: shopService}
+}
+
+// Catalog - GET /shop
+func (h *ShopHandler) Catalog(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"items": h.shopService.GetCatalog()})
+}
+
+// Buy - POST /shop/buy
+func (h *ShopHandler) Buy(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	var req struct {
+		ItemID string `json:"item_id"`
+		// По умолчанию один предмет
+		Quantity int `json:"quantity"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат"})
+	}
+	if req.Quantity == 0 {
+		req.Quantity = 1
+	}
+
+	result, err := h.shopService.Buy(c.Context(), userID, req.ItemID, req.Quantity)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(result)
+}
+
+// Inventory - GET /inventory
+func (h *ShopHandler) Inventory(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	inventory, err := h.shopService.GetInventory(c.Context(), userID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"items": inventory})
+}
+
+// Use - POST /inventory/:item/use
+func (h *ShopHandler) Use(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	result, err := h.shopService.Use(c.Context(), userID, c.Params("item"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(result)
+}
+
+// Equip - POST /inventory/:item/equip
+func (h *ShopHandler) Equip(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	result, err := h.shopService.Equip(c.Context(), userID, c.Params("item"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(result)
+}
+
+// Unequip - POST /inventory/:item/unequip
+func (h *ShopHandler) Unequip(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	result, err := h.shopService.Unequip(c.Context(), userID, c.Params("item"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(result)
+}
+
+// Equipment - GET /equipment
+func (h *ShopHandler) Equipment(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	equipment, err := h.shopService.GetEquipment(c.Context(), userID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(equipment)
+}
@@ -1 +1,95 @@
-package http
\ No newline at end of file
+// internal/adapters/http/task_handler.go
+package http
+
+import (
+	"dojo/internal/core"
+	"dojo/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TaskHandler - задания игрока: создание, старт, завершение и отказ от срочного вызова
+type TaskHandler struct {
+	taskService *core.TaskService
+}
+
+func NewTaskHandler(taskService *core.TaskService) *TaskHandler {
+	return &TaskHandler{taskService: taskService}
+}
+
+// List - GET /tasks, активные задания
+func (h *TaskHandler) List(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	tasks, err := h.taskService.GetActiveTasks(c.Context(), userID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"tasks": tasks})
+}
+
+// Create - POST /tasks
+func (h *TaskHandler) Create(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	var req struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		TaskType    string `json:"task_type"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат"})
+	}
+
+	task, err := h.taskService.CreateCustomTask(
+		c.Context(),
+		userID,
+		req.Title,
+		req.Description,
+		domain.TaskType(req.TaskType),
+	)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(task)
+}
+
+// Start - POST /tasks/:id/start
+func (h *TaskHandler) Start(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	taskID, _ := c.ParamsInt("id")
+
+	if err := h.taskService.StartTask(c.Context(), int64(taskID), userID); err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"success": true})
+}
+
+// Complete - POST /tasks/:id/complete
+func (h *TaskHandler) Complete(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	taskID, _ := c.ParamsInt("id")
+
+	result, err := h.taskService.CompleteTask(c.Context(), int64(taskID), userID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(result)
+}
+
+// Decline - POST /tasks/:id/decline, отказ от срочного вызова со штрафом
+func (h *TaskHandler) Decline(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+	taskID, _ := c.ParamsInt("id")
+
+	if err := h.taskService.DeclineUrgentCall(c.Context(), int64(taskID), userID); err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"success": true})
+}
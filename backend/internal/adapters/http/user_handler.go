@@ -1 +1,73 @@
+// internal/adapters/http/user_handler.go
 package http
+
+import (
+	"dojo/internal/core"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// UserHandler - профиль игрока и покупки за золото
+type UserHandler struct {
+	userService *core.UserService
+}
+
+func NewUserHandler(userService *core.UserService) *UserHandler {
+	return &UserHandler{userService: userService}
+}
+
+// Profile - GET /profile
+func (h *UserHandler) Profile(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	profile, err := h.userService.GetProfile(c.Context(), userID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(profile)
+}
+
+// SetTimezone - PUT /profile/timezone, по поясу наступает новый день квестов
+func (h *UserHandler) SetTimezone(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	var req struct {
+		Timezone string `json:"timezone"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Неверный формат"})
+	}
+
+	user, err := h.userService.SetTimezone(c.Context(), userID, req.Timezone)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(user)
+}
+
+// BuyStreakFreeze - POST /streak/freeze, заморозка серии за золото
+func (h *UserHandler) BuyStreakFreeze(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	user, err := h.userService.BuyStreakFreeze(c.Context(), userID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(user)
+}
+
+// BuyEnergyRefill - POST /energy/refill, полное восстановление энергии за золото
+func (h *UserHandler) BuyEnergyRefill(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(int64)
+
+	user, err := h.userService.BuyEnergyRefill(c.Context(), userID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(user)
+}
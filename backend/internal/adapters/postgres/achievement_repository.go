@@ -0,0 +1,60 @@
+// internal/adapters/postgres/achievement_repository.go
+package postgres
+
+import (
+	"context"
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AchievementRepository struct {
+	db *gorm.DB
+}
+
+func NewAchievementRepository(db *gorm.DB) ports.AchievementRepository {
+	return &AchievementRepository{db: db}
+}
+
+func (r *AchievementRepository) Get(ctx context.Context, userID int64, achievementID string) (*domain.UserAchievement, error) {
+	var achievement domain.UserAchievement
+	err := conn(ctx, r.db).
+		Where("user_id = ? AND achievement_id = ?", userID, achievementID).
+		First(&achievement).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrAchievementNotFound
+		}
+		return nil, err
+	}
+	return &achievement, nil
+}
+
+func (r *AchievementRepository) GetByUserID(ctx context.Context, userID int64) ([]*domain.UserAchievement, error) {
+	var achievements []*domain.UserAchievement
+	err := conn(ctx, r.db).
+		Where("user_id = ?", userID).
+		Order("id ASC").
+		Find(&achievements).Error
+
+	return achievements, err
+}
+
+// Create - первая запись прогресса; уникальный индекс отсекает параллельную
+func (r *AchievementRepository) Create(ctx context.Context, achievement *domain.UserAchievement) (bool, error) {
+	result := conn(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(achievement)
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Update - сохраняет прогресс, если его версия не изменилась с момента чтения
+func (r *AchievementRepository) Update(ctx context.Context, achievement *domain.UserAchievement) error {
+	return updateVersioned(conn(ctx, r.db), achievement, &achievement.Version)
+}
@@ -0,0 +1,38 @@
+// internal/adapters/postgres/event_stats_repository.go
+package postgres
+
+import (
+	"context"
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EventStatsRepository struct {
+	db *gorm.DB
+}
+
+func NewEventStatsRepository(db *gorm.DB) ports.EventStatsRepository {
+	return &EventStatsRepository{db: db}
+}
+
+func (r *EventStatsRepository) Increment(ctx context.Context, day string, eventType domain.EventType) error {
+	return conn(ctx, r.db).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "day"}, {Name: "event_type"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"count": gorm.Expr("event_stats.count + 1")}),
+		}).
+		Create(&domain.EventStat{Day: day, EventType: eventType, Count: 1}).Error
+}
+
+func (r *EventStatsRepository) GetSince(ctx context.Context, from string) ([]*domain.EventStat, error) {
+	var stats []*domain.EventStat
+	err := conn(ctx, r.db).
+		Where("day >= ?", from).
+		Order("day ASC, event_type ASC").
+		Find(&stats).Error
+
+	return stats, err
+}
@@ -0,0 +1,60 @@
+// internal/adapters/postgres/inventory_repository.go
+package postgres
+
+import (
+	"context"
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type InventoryRepository struct {
+	db *gorm.DB
+}
+
+func NewInventoryRepository(db *gorm.DB) ports.InventoryRepository {
+	return &InventoryRepository{db: db}
+}
+
+func (r *InventoryRepository) Get(ctx context.Context, userID int64, itemID string) (*domain.InventoryItem, error) {
+	var item domain.InventoryItem
+	err := conn(ctx, r.db).
+		Where("user_id = ? AND item_id = ?", userID, itemID).
+		First(&item).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrItemNotOwned
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *InventoryRepository) GetByUserID(ctx context.Context, userID int64) ([]*domain.InventoryItem, error) {
+	var items []*domain.InventoryItem
+	err := conn(ctx, r.db).
+		Where("user_id = ? AND quantity > 0", userID).
+		Order("id ASC").
+		Find(&items).Error
+
+	return items, err
+}
+
+// Create - первая покупка предмета; уникальный индекс отсекает параллельную
+func (r *InventoryRepository) Create(ctx context.Context, item *domain.InventoryItem) (bool, error) {
+	result := conn(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(item)
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Update - сохраняет ячейку, если ее версия не изменилась с момента чтения
+func (r *InventoryRepository) Update(ctx context.Context, item *domain.InventoryItem) error {
+	return updateVersioned(conn(ctx, r.db), item, &item.Version)
+}
@@ -0,0 +1,76 @@
+// internal/adapters/postgres/leader.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"dojo/internal/ports"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// LeaderLock - лидерство через сессионный pg_advisory_lock.
+// Блокировка живет, пока открыто соединение, поэтому лидер держит его отдельно от пула
+type LeaderLock struct {
+	db  *sql.DB
+	key int64
+
+	mu   sync.Mutex
+	conn *sql.Conn
+}
+
+func NewLeaderLock(db *gorm.DB, key int64) (ports.LeaderElector, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	return &LeaderLock{db: sqlDB, key: key}, nil
+}
+
+// TryLead - лидер проверяет, что соединение живо; остальные пробуют захватить блокировку
+func (l *LeaderLock) TryLead(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn != nil {
+		// Соединение оборвалось - база уже сняла блокировку, и лидером мог стать другой
+		if err := l.conn.PingContext(ctx); err == nil {
+			return true, nil
+		}
+		l.conn.Close()
+		l.conn = nil
+	}
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", l.key).Scan(&locked); err != nil {
+		conn.Close()
+		return false, err
+	}
+	if !locked {
+		conn.Close()
+		return false, nil
+	}
+
+	l.conn = conn
+	return true, nil
+}
+
+func (l *LeaderLock) Resign(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+
+	_, err := l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.key)
+	l.conn.Close()
+	l.conn = nil
+	return err
+}
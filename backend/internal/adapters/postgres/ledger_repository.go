@@ -0,0 +1,69 @@
+// internal/adapters/postgres/ledger_repository.go
+package postgres
+
+import (
+	"context"
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+
+	"gorm.io/gorm"
+)
+
+type LedgerRepository struct {
+	db *gorm.DB
+}
+
+func NewLedgerRepository(db *gorm.DB) ports.LedgerRepository {
+	return &LedgerRepository{db: db}
+}
+
+func (r *LedgerRepository) ListByUserID(ctx context.Context, userID int64, resource domain.LedgerResource, limit, offset int) ([]*domain.LedgerEntry, error) {
+	var entries []*domain.LedgerEntry
+
+	query := conn(ctx, r.db).Where("user_id = ?", userID)
+	if resource != "" {
+		query = query.Where("resource = ?", resource)
+	}
+
+	err := query.
+		Order("id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&entries).Error
+
+	return entries, err
+}
+
+// Audit - сверка текущих балансов с суммой дельт по каждому ресурсу
+func (r *LedgerRepository) Audit(ctx context.Context, limit int) ([]*domain.LedgerDiscrepancy, error) {
+	var discrepancies []*domain.LedgerDiscrepancy
+	err := conn(ctx, r.db).Raw(`
+		WITH balances AS (
+			SELECT id AS user_id, 'xp' AS resource, xp AS balance FROM users
+			UNION ALL SELECT id, 'gold', gold FROM users
+			UNION ALL SELECT id, 'energy', energy FROM users
+			UNION ALL SELECT id, 'sensei_requests', sensei_requests FROM users
+		), sums AS (
+			SELECT user_id, resource, SUM(delta) AS ledger_sum
+			FROM ledger_entries
+			GROUP BY user_id, resource
+		)
+		SELECT b.user_id, b.resource, b.balance, COALESCE(s.ledger_sum, 0) AS ledger_sum
+		FROM balances b
+		LEFT JOIN sums s ON s.user_id = b.user_id AND s.resource = b.resource
+		WHERE b.balance <> COALESCE(s.ledger_sum, 0)
+		ORDER BY b.user_id, b.resource
+		LIMIT ?`, limit).
+		Scan(&discrepancies).Error
+
+	return discrepancies, err
+}
+
+// appendLedger - дописывает несохраненные записи журнала игрока
+func appendLedger(db *gorm.DB, user *domain.User) error {
+	entries := user.TakeLedger()
+	if len(entries) == 0 {
+		return nil
+	}
+	return db.Create(&entries).Error
+}
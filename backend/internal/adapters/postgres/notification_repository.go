@@ -0,0 +1,67 @@
+// internal/adapters/postgres/notification_repository.go
+package postgres
+
+import (
+	"context"
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) ports.NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+func (r *NotificationRepository) Enqueue(ctx context.Context, n *domain.Notification) (bool, error) {
+	result := conn(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(n)
+	
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *NotificationRepository) GetPending(ctx context.Context, now time.Time, limit int) ([]*domain.Notification, error) {
+	var notifications []*domain.Notification
+	err := conn(ctx, r.db).
+		Where("status = ?", domain.NotificationPending).
+		Where("next_attempt_at <= ?", now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&notifications).Error
+	
+	return notifications, err
+}
+
+func (r *NotificationRepository) Update(ctx context.Context, n *domain.Notification) error {
+	return conn(ctx, r.db).Save(n).Error
+}
+
+func (r *NotificationRepository) GetSettings(ctx context.Context, userID int64) (*domain.NotificationSettings, error) {
+	var settings domain.NotificationSettings
+	err := conn(ctx, r.db).
+		Where("user_id = ?", userID).
+		First(&settings).Error
+	
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return domain.DefaultNotificationSettings(userID), nil
+		}
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (r *NotificationRepository) SaveSettings(ctx context.Context, settings *domain.NotificationSettings) error {
+	// Save с ненулевым первичным ключом делает upsert
+	return conn(ctx, r.db).Save(settings).Error
+}
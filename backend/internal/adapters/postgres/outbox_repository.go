@@ -0,0 +1,106 @@
+// internal/adapters/postgres/outbox_repository.go
+package postgres
+
+import (
+	"context"
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) ports.OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+func (r *OutboxRepository) Append(ctx context.Context, events []*domain.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return conn(ctx, r.db).Create(&events).Error
+}
+
+func (r *OutboxRepository) GetUndispatched(ctx context.Context, limit int) ([]*domain.OutboxEvent, error) {
+	var events []*domain.OutboxEvent
+	err := conn(ctx, r.db).
+		Where("dispatched_at IS NULL").
+		Order("id ASC").
+		Limit(limit).
+		Find(&events).Error
+
+	return events, err
+}
+
+// Dispatch - повторный вызов для того же события доставки не дублирует
+func (r *OutboxRepository) Dispatch(ctx context.Context, event *domain.OutboxEvent, deliveries []*domain.EventDelivery) error {
+	db := conn(ctx, r.db)
+	if len(deliveries) > 0 {
+		err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	event.DispatchedAt = &now
+	return db.Model(event).Update("dispatched_at", now).Error
+}
+
+func (r *OutboxRepository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*domain.EventDelivery, error) {
+	var deliveries []*domain.EventDelivery
+	err := conn(ctx, r.db).
+		Preload("Event").
+		Where("status = ?", domain.DeliveryPending).
+		Where("next_attempt_at <= ?", now).
+		Order("next_attempt_at ASC, id ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+
+	return deliveries, err
+}
+
+func (r *OutboxRepository) GetDelivery(ctx context.Context, id int64) (*domain.EventDelivery, error) {
+	var delivery domain.EventDelivery
+	err := conn(ctx, r.db).Preload("Event").First(&delivery, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// UpdateDelivery - событие доставки не трогаем: оно неизменно
+func (r *OutboxRepository) UpdateDelivery(ctx context.Context, delivery *domain.EventDelivery) error {
+	return conn(ctx, r.db).Omit(clause.Associations).Save(delivery).Error
+}
+
+func (r *OutboxRepository) GetDeadDeliveries(ctx context.Context, limit, offset int) ([]*domain.EventDelivery, error) {
+	var deliveries []*domain.EventDelivery
+	err := conn(ctx, r.db).
+		Preload("Event").
+		Where("status = ?", domain.DeliveryDead).
+		Order("updated_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&deliveries).Error
+
+	return deliveries, err
+}
+
+// PurgeDelivered - доставки удаляются каскадом вместе с событием
+func (r *OutboxRepository) PurgeDelivered(ctx context.Context, before time.Time) (int, error) {
+	result := conn(ctx, r.db).
+		Where("dispatched_at < ?", before).
+		Where("NOT EXISTS (SELECT 1 FROM event_deliveries d WHERE d.event_id = outbox_events.id AND d.status <> ?)", domain.DeliveryDelivered).
+		Delete(&domain.OutboxEvent{})
+
+	return int(result.RowsAffected), result.Error
+}
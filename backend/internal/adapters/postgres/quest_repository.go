@@ -0,0 +1,67 @@
+// internal/adapters/postgres/quest_repository.go
+package postgres
+
+import (
+	"context"
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type QuestTemplateRepository struct {
+	db *gorm.DB
+}
+
+func NewQuestTemplateRepository(db *gorm.DB) ports.QuestTemplateRepository {
+	return &QuestTemplateRepository{db: db}
+}
+
+func (r *QuestTemplateRepository) Create(ctx context.Context, template *domain.QuestTemplate) error {
+	return conn(ctx, r.db).Create(template).Error
+}
+
+func (r *QuestTemplateRepository) GetByID(ctx context.Context, id int64) (*domain.QuestTemplate, error) {
+	var template domain.QuestTemplate
+	err := conn(ctx, r.db).First(&template, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrQuestTemplateNotFound
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *QuestTemplateRepository) GetByUserID(ctx context.Context, userID int64) ([]*domain.QuestTemplate, error) {
+	var templates []*domain.QuestTemplate
+	err := conn(ctx, r.db).
+		Where("user_id = ?", userID).
+		Where("active = ?", true).
+		Order("id ASC").
+		Find(&templates).Error
+
+	return templates, err
+}
+
+// Update - сохраняет шаблон, если его версия не изменилась с момента чтения
+func (r *QuestTemplateRepository) Update(ctx context.Context, template *domain.QuestTemplate) error {
+	return updateVersioned(conn(ctx, r.db), template, &template.Version)
+}
+
+// GetDue - сегодняшний день владельца считает сама база по users.timezone,
+// так что смена пояса подхватывается без пересчета шаблонов
+func (r *QuestTemplateRepository) GetDue(ctx context.Context, now time.Time, limit int) ([]*domain.QuestTemplate, error) {
+	var templates []*domain.QuestTemplate
+	err := conn(ctx, r.db).
+		Joins("JOIN users ON users.id = quest_templates.user_id").
+		Where("quest_templates.active = ?", true).
+		Where(`COALESCE(quest_templates.last_run_on, '') <
+			to_char(?::timestamptz AT TIME ZONE COALESCE(NULLIF(users.timezone, ''), 'UTC'), 'YYYY-MM-DD')`, now).
+		Order("quest_templates.id ASC").
+		Limit(limit).
+		Find(&templates).Error
+
+	return templates, err
+}
@@ -0,0 +1,74 @@
+// internal/adapters/postgres/raid_repository.go
+package postgres
+
+import (
+	"context"
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RaidRepository struct {
+	db *gorm.DB
+}
+
+func NewRaidRepository(db *gorm.DB) ports.RaidRepository {
+	return &RaidRepository{db: db}
+}
+
+func (r *RaidRepository) Create(ctx context.Context, raid *domain.Raid) error {
+	return conn(ctx, r.db).Create(raid).Error
+}
+
+func (r *RaidRepository) GetByID(ctx context.Context, id int64) (*domain.Raid, error) {
+	var raid domain.Raid
+	err := conn(ctx, r.db).First(&raid, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrRaidNotFound
+		}
+		return nil, err
+	}
+	return &raid, nil
+}
+
+func (r *RaidRepository) GetByAttackerID(ctx context.Context, attackerID int64, limit, offset int) ([]*domain.Raid, error) {
+	var raids []*domain.Raid
+	err := conn(ctx, r.db).
+		Where("attacker_id = ?", attackerID).
+		Order("id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&raids).Error
+
+	return raids, err
+}
+
+func (r *RaidRepository) GetByTargetID(ctx context.Context, targetID int64, limit, offset int) ([]*domain.Raid, error) {
+	var raids []*domain.Raid
+	err := conn(ctx, r.db).
+		Where("target_id = ?", targetID).
+		Order("id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&raids).Error
+
+	return raids, err
+}
+
+func (r *RaidRepository) GetByTargetsSince(ctx context.Context, targetIDs []int64, since time.Time) ([]*domain.Raid, error) {
+	var raids []*domain.Raid
+	if len(targetIDs) == 0 {
+		return raids, nil
+	}
+
+	err := conn(ctx, r.db).
+		Where("target_id IN ?", targetIDs).
+		Where("started_at >= ?", since).
+		Order("id DESC").
+		Find(&raids).Error
+
+	return raids, err
+}
@@ -0,0 +1,103 @@
+// internal/adapters/postgres/sensei_repository.go
+package postgres
+
+import (
+	"context"
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+
+	"gorm.io/gorm"
+)
+
+type SenseiRepository struct {
+	db *gorm.DB
+}
+
+func NewSenseiRepository(db *gorm.DB) ports.SenseiRepository {
+	return &SenseiRepository{db: db}
+}
+
+func (r *SenseiRepository) CreateConversation(ctx context.Context, conversation *domain.SenseiConversation) error {
+	return conn(ctx, r.db).Create(conversation).Error
+}
+
+func (r *SenseiRepository) GetConversation(ctx context.Context, id int64) (*domain.SenseiConversation, error) {
+	var conversation domain.SenseiConversation
+	err := conn(ctx, r.db).First(&conversation, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrConversationNotFound
+		}
+		return nil, err
+	}
+	return &conversation, nil
+}
+
+func (r *SenseiRepository) GetLatestConversation(ctx context.Context, userID int64) (*domain.SenseiConversation, error) {
+	var conversation domain.SenseiConversation
+	err := conn(ctx, r.db).
+		Where("user_id = ?", userID).
+		Order("updated_at DESC").
+		First(&conversation).Error
+	
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrConversationNotFound
+		}
+		return nil, err
+	}
+	return &conversation, nil
+}
+
+func (r *SenseiRepository) ListConversations(ctx context.Context, userID int64, limit, offset int) ([]*domain.SenseiConversation, error) {
+	var conversations []*domain.SenseiConversation
+	err := conn(ctx, r.db).
+		Where("user_id = ?", userID).
+		Order("updated_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&conversations).Error
+	
+	return conversations, err
+}
+
+func (r *SenseiRepository) UpdateConversation(ctx context.Context, conversation *domain.SenseiConversation) error {
+	return conn(ctx, r.db).Save(conversation).Error
+}
+
+func (r *SenseiRepository) DeleteConversation(ctx context.Context, id int64) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("conversation_id = ?", id).Delete(&domain.SenseiMessage{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&domain.SenseiConversation{}, id).Error
+	})
+}
+
+func (r *SenseiRepository) DeleteAllByUserID(ctx context.Context, userID int64) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		conversations := tx.Model(&domain.SenseiConversation{}).
+			Select("id").
+			Where("user_id = ?", userID)
+		
+		if err := tx.Where("conversation_id IN (?)", conversations).Delete(&domain.SenseiMessage{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&domain.SenseiConversation{}).Error
+	})
+}
+
+func (r *SenseiRepository) AddMessage(ctx context.Context, message *domain.SenseiMessage) error {
+	return conn(ctx, r.db).Create(message).Error
+}
+
+func (r *SenseiRepository) GetMessages(ctx context.Context, conversationID, afterID int64) ([]*domain.SenseiMessage, error) {
+	var messages []*domain.SenseiMessage
+	err := conn(ctx, r.db).
+		Where("conversation_id = ?", conversationID).
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Find(&messages).Error
+	
+	return messages, err
+}
@@ -0,0 +1,63 @@
+// internal/adapters/postgres/session_repository.go
+package postgres
+
+import (
+	"context"
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) ports.SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+func (r *SessionRepository) Create(ctx context.Context, session *domain.Session) error {
+	return conn(ctx, r.db).Create(session).Error
+}
+
+func (r *SessionRepository) GetByID(ctx context.Context, id string) (*domain.Session, error) {
+	var session domain.Session
+	err := conn(ctx, r.db).
+		Where("id = ?", id).
+		First(&session).Error
+	
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrSessionNotFound
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *SessionRepository) Revoke(ctx context.Context, id string) error {
+	result := conn(ctx, r.db).
+		Model(&domain.Session{}).
+		Where("id = ?", id).
+		Where("revoked_at IS NULL").
+		Update("revoked_at", time.Now())
+	
+	if result.Error != nil {
+		return result.Error
+	}
+	// Сессию отозвал параллельный запрос
+	if result.RowsAffected == 0 {
+		return domain.ErrSessionRevoked
+	}
+	return nil
+}
+
+func (r *SessionRepository) RevokeAllByUserID(ctx context.Context, userID int64) error {
+	return conn(ctx, r.db).
+		Model(&domain.Session{}).
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL").
+		Update("revoked_at", time.Now()).Error
+}
@@ -5,9 +5,11 @@ import (
 	"context"
 	"dojo/internal/domain"
 	"dojo/internal/ports"
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TaskRepository struct {
@@ -19,12 +21,12 @@ func NewTaskRepository(db *gorm.DB) ports.TaskRepository {
 }
 
 func (r *TaskRepository) Create(ctx context.Context, task *domain.Task) error {
-	return r.db.WithContext(ctx).Create(task).Error
+	return conn(ctx, r.db).Create(task).Error
 }
 
 func (r *TaskRepository) GetByID(ctx context.Context, id int64) (*domain.Task, error) {
 	var task domain.Task
-	err := r.db.WithContext(ctx).First(&task, id).Error
+	err := conn(ctx, r.db).First(&task, id).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, domain.ErrTaskNotFound
@@ -36,7 +38,7 @@ func (r *TaskRepository) GetByID(ctx context.Context, id int64) (*domain.Task, e
 
 func (r *TaskRepository) GetByUserID(ctx context.Context, userID int64) ([]*domain.Task, error) {
 	var tasks []*domain.Task
-	err := r.db.WithContext(ctx).
+	err := conn(ctx, r.db).
 		Where("user_id = ?", userID).
 		Order("created_at DESC").
 		Find(&tasks).Error
@@ -44,9 +46,20 @@ func (r *TaskRepository) GetByUserID(ctx context.Context, userID int64) ([]*doma
 	return tasks, err
 }
 
+func (r *TaskRepository) GetRecentByUserID(ctx context.Context, userID int64, limit int) ([]*domain.Task, error) {
+	var tasks []*domain.Task
+	err := conn(ctx, r.db).
+		Where("user_id = ?", userID).
+		Order("updated_at DESC").
+		Limit(limit).
+		Find(&tasks).Error
+	
+	return tasks, err
+}
+
 func (r *TaskRepository) GetActiveByUserID(ctx context.Context, userID int64) ([]*domain.Task, error) {
 	var tasks []*domain.Task
-	err := r.db.WithContext(ctx).
+	err := conn(ctx, r.db).
 		Where("user_id = ?", userID).
 		Where("status IN ?", []domain.TaskStatus{
 			domain.TaskStatusActive,
@@ -58,31 +71,110 @@ func (r *TaskRepository) GetActiveByUserID(ctx context.Context, userID int64) ([
 	return tasks, err
 }
 
-func (r *TaskRepository) GetDailyTasks(ctx context.Context, userID int64) ([]*domain.Task, error) {
+func (r *TaskRepository) GetDailyTasks(ctx context.Context, userID int64, date string) ([]*domain.Task, error) {
 	var tasks []*domain.Task
+	err := conn(ctx, r.db).
+		Where("user_id = ?", userID).
+		Where("frequency = ?", domain.FrequencyDaily).
+		Where("quest_date = ?", date).
+		Order("id ASC").
+		Find(&tasks).Error
 	
-	today := time.Now().Truncate(24 * time.Hour)
+	return tasks, err
+}
+
+// CreateQuest - уникальный индекс (template_id, quest_date) не дает создать квест дважды
+func (r *TaskRepository) CreateQuest(ctx context.Context, task *domain.Task) (bool, error) {
+	result := conn(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(task)
 	
-	err := r.db.WithContext(ctx).
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CreatePenaltyQuest - штрафной квест. Вставляется картой: Create со структурой
+// заменил бы нулевые награды и цену значениями default из тегов gorm
+func (r *TaskRepository) CreatePenaltyQuest(ctx context.Context, task *domain.Task) error {
+	now := time.Now()
+	values := map[string]interface{}{
+		"user_id":     task.UserID,
+		"title":       task.Title,
+		"description": task.Description,
+		"task_type":   task.TaskType,
+		"frequency":   task.Frequency,
+		"status":      task.Status,
+		"xp_reward":   task.XPReward,
+		"gold_reward": task.GoldReward,
+		"stat_boost":  task.StatBoost,
+		"energy_cost": task.EnergyCost,
+		"gold_cost":   task.GoldCost,
+		"version":     task.Version,
+		"created_at":  now,
+		"updated_at":  now,
+	}
+	if err := conn(ctx, r.db).Model(&domain.Task{}).Create(values).Error; err != nil {
+		return err
+	}
+
+	// id приходит из RETURNING в ту же карту
+	id, ok := values["id"].(int64)
+	if !ok {
+		return fmt.Errorf("штрафной квест: неожиданный id %v", values["id"])
+	}
+	task.ID = id
+	task.CreatedAt = now
+	task.UpdatedAt = now
+	return nil
+}
+
+// GetPenaltyQuests - штрафные квесты игрока, от новых к старым
+func (r *TaskRepository) GetPenaltyQuests(ctx context.Context, userID int64, limit, offset int) ([]*domain.Task, error) {
+	var tasks []*domain.Task
+	err := conn(ctx, r.db).
 		Where("user_id = ?", userID).
-		Where("frequency = ?", domain.FrequencyDaily).
-		Where("created_at >= ?", today).
+		Where("frequency = ?", domain.FrequencyPenalty).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&tasks).Error
+
+	return tasks, err
+}
+
+// GetOverdueQuests - невыполненные квесты из шаблонов, чей день у владельца уже закончился
+func (r *TaskRepository) GetOverdueQuests(ctx context.Context, now time.Time, limit int) ([]*domain.Task, error) {
+	var tasks []*domain.Task
+	err := conn(ctx, r.db).
+		Joins("JOIN users ON users.id = tasks.user_id").
+		Where("tasks.template_id IS NOT NULL").
+		Where("tasks.status IN ?", []domain.TaskStatus{
+			domain.TaskStatusActive,
+			domain.TaskStatusInProgress,
+		}).
+		Where(`tasks.quest_date <
+			to_char(?::timestamptz AT TIME ZONE COALESCE(NULLIF(users.timezone, ''), 'UTC'), 'YYYY-MM-DD')`, now).
+		Order("tasks.user_id ASC, tasks.id ASC").
+		Limit(limit).
 		Find(&tasks).Error
 	
 	return tasks, err
 }
 
+// Update - сохраняет задание, если его версия не изменилась с момента чтения
 func (r *TaskRepository) Update(ctx context.Context, task *domain.Task) error {
-	return r.db.WithContext(ctx).Save(task).Error
+	return updateVersioned(conn(ctx, r.db), task, &task.Version)
 }
 
 func (r *TaskRepository) Delete(ctx context.Context, id int64) error {
-	return r.db.WithContext(ctx).Delete(&domain.Task{}, id).Error
+	return conn(ctx, r.db).Delete(&domain.Task{}, id).Error
 }
 
 func (r *TaskRepository) GetUrgentTasks(ctx context.Context, userID int64) ([]*domain.Task, error) {
 	var tasks []*domain.Task
-	err := r.db.WithContext(ctx).
+	err := conn(ctx, r.db).
 		Where("user_id = ?", userID).
 		Where("is_urgent = ?", true).
 		Where("status IN ?", []domain.TaskStatus{
@@ -95,18 +187,37 @@ func (r *TaskRepository) GetUrgentTasks(ctx context.Context, userID int64) ([]*d
 	return tasks, err
 }
 
-func (r *TaskRepository) ExpireOldTasks(ctx context.Context) error {
-	now := time.Now()
+// GetUrgentDueBefore - незавершенные срочные задания всех игроков с дедлайном до before
+func (r *TaskRepository) GetUrgentDueBefore(ctx context.Context, before time.Time, limit int) ([]*domain.Task, error) {
+	var tasks []*domain.Task
+	err := conn(ctx, r.db).
+		Where("is_urgent = ?", true).
+		Where("urgent_until <= ?", before).
+		Where("status IN ?", []domain.TaskStatus{
+			domain.TaskStatusActive,
+			domain.TaskStatusInProgress,
+		}).
+		Order("urgent_until ASC").
+		Limit(limit).
+		Find(&tasks).Error
 	
-	result := r.db.WithContext(ctx).
-		Model(&domain.Task{}).
+	return tasks, err
+}
+
+// GetUrgentDueBetween - незавершенные срочные задания с дедлайном в (after, before].
+// Нижняя граница не дает просроченным, которых еще не списал планировщик, занять весь лимит
+func (r *TaskRepository) GetUrgentDueBetween(ctx context.Context, after, before time.Time, limit int) ([]*domain.Task, error) {
+	var tasks []*domain.Task
+	err := conn(ctx, r.db).
 		Where("is_urgent = ?", true).
-		Where("urgent_until < ?", now).
+		Where("urgent_until > ? AND urgent_until <= ?", after, before).
 		Where("status IN ?", []domain.TaskStatus{
 			domain.TaskStatusActive,
 			domain.TaskStatusInProgress,
 		}).
-		Update("status", domain.TaskStatusExpired)
+		Order("urgent_until ASC").
+		Limit(limit).
+		Find(&tasks).Error
 	
-	return result.Error
-}
\ No newline at end of file
+	return tasks, err
+}
@@ -0,0 +1,39 @@
+// internal/adapters/postgres/tx_manager.go
+package postgres
+
+import (
+	"context"
+	"dojo/internal/ports"
+
+	"gorm.io/gorm"
+)
+
+// txKey - ключ открытой транзакции в context
+type txKey struct{}
+
+type TxManager struct {
+	db *gorm.DB
+}
+
+func NewTxManager(db *gorm.DB) ports.TxManager {
+	return &TxManager{db: db}
+}
+
+// WithinTx - выполняет fn в транзакции; вложенный вызов работает во внешней
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// conn - транзакция из ctx, если она открыта, иначе обычное соединение
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
@@ -18,13 +18,19 @@ func NewUserRepository(db *gorm.DB) ports.UserRepository {
 	return &UserRepository{db: db}
 }
 
+// Create - сохраняет нового игрока вместе с записями журнала
 func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
-	return r.db.WithContext(ctx).Create(user).Error
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return appendLedger(tx, user)
+	})
 }
 
 func (r *UserRepository) GetByID(ctx context.Context, id int64) (*domain.User, error) {
 	var user domain.User
-	err := r.db.WithContext(ctx).First(&user, id).Error
+	err := conn(ctx, r.db).First(&user, id).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, domain.ErrUserNotFound
@@ -36,7 +42,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id int64) (*domain.User, e
 
 func (r *UserRepository) GetByTelegramID(ctx context.Context, telegramID int64) (*domain.User, error) {
 	var user domain.User
-	err := r.db.WithContext(ctx).
+	err := conn(ctx, r.db).
 		Where("telegram_id = ?", telegramID).
 		First(&user).Error
 	
@@ -49,13 +55,20 @@ func (r *UserRepository) GetByTelegramID(ctx context.Context, telegramID int64)
 	return &user, nil
 }
 
+// Update - сохраняет игрока, если его версия не изменилась с момента чтения.
+// Записи журнала пишутся в той же транзакции, что и новые балансы
 func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
-	return r.db.WithContext(ctx).Save(user).Error
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := updateVersioned(tx, user, &user.Version); err != nil {
+			return err
+		}
+		return appendLedger(tx, user)
+	})
 }
 
 func (r *UserRepository) List(ctx context.Context, limit, offset int) ([]*domain.User, error) {
 	var users []*domain.User
-	err := r.db.WithContext(ctx).
+	err := conn(ctx, r.db).
 		Limit(limit).
 		Offset(offset).
 		Order("level DESC, xp DESC").
@@ -64,14 +77,20 @@ func (r *UserRepository) List(ctx context.Context, limit, offset int) ([]*domain
 	return users, err
 }
 
-func (r *UserRepository) GetInactivePlayers(ctx context.Context, limit int) ([]*domain.User, error) {
+func (r *UserRepository) GetInactivePlayers(ctx context.Context, minLevel, maxLevel, limit int) ([]*domain.User, error) {
 	var users []*domain.User
 	
 	inactiveThreshold := time.Now().AddDate(0, 0, -3)
 	
-	err := r.db.WithContext(ctx).
+	query := conn(ctx, r.db).
 		Where("last_active_at < ?", inactiveThreshold).
 		Where("gold > ?", 10).
+		Where("level >= ?", minLevel)
+	if maxLevel > 0 {
+		query = query.Where("level <= ?", maxLevel)
+	}
+	
+	err := query.
 		Order("gold DESC").
 		Limit(limit).
 		Find(&users).Error
@@ -79,8 +98,45 @@ func (r *UserRepository) GetInactivePlayers(ctx context.Context, limit int) ([]*
 	return users, err
 }
 
+// GetWithExpiredLicense - игроки, у которых лицензия числится активной, но срок вышел
+func (r *UserRepository) GetWithExpiredLicense(ctx context.Context, limit int) ([]*domain.User, error) {
+	var users []*domain.User
+	err := conn(ctx, r.db).
+		Where("license_active = ?", true).
+		Where("license_expires_at < ?", time.Now()).
+		Order("license_expires_at ASC").
+		Limit(limit).
+		Find(&users).Error
+	
+	return users, err
+}
+
+func (r *UserRepository) GetSenseiResetDue(ctx context.Context, before time.Time, limit int) ([]*domain.User, error) {
+	var users []*domain.User
+	err := conn(ctx, r.db).
+		Where("sensei_resets_at < ?", before).
+		Order("sensei_resets_at ASC").
+		Limit(limit).
+		Find(&users).Error
+	
+	return users, err
+}
+
+// GetPenaltyDrainDue - игроки в зоне наказания, с которых последний раз брали ресурсы до before
+func (r *UserRepository) GetPenaltyDrainDue(ctx context.Context, before time.Time, limit int) ([]*domain.User, error) {
+	var users []*domain.User
+	err := conn(ctx, r.db).
+		Where("penalty_task_id IS NOT NULL").
+		Where("penalty_drained_at <= ?", before).
+		Order("penalty_drained_at ASC").
+		Limit(limit).
+		Find(&users).Error
+	
+	return users, err
+}
+
 func (r *UserRepository) UpdateActivity(ctx context.Context, userID int64) error {
-	return r.db.WithContext(ctx).
+	return conn(ctx, r.db).
 		Model(&domain.User{}).
 		Where("id = ?", userID).
 		Update("last_active_at", time.Now()).Error
@@ -0,0 +1,30 @@
+// internal/adapters/postgres/versioned.go
+package postgres
+
+import (
+	"dojo/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// updateVersioned - UPDATE всей строки с проверкой версии вместо Save.
+// Если строку успели изменить (версия уже другая), ничего не пишет
+// и возвращает domain.ErrConcurrentModification
+func updateVersioned(db *gorm.DB, model interface{}, version *int64) error {
+	expected := *version
+	*version = expected + 1
+
+	result := db.Model(model).
+		Where("version = ?", expected).
+		Select("*").
+		Updates(model)
+
+	if result.Error != nil || result.RowsAffected == 0 {
+		*version = expected
+		if result.Error != nil {
+			return result.Error
+		}
+		return domain.ErrConcurrentModification
+	}
+	return nil
+}
@@ -0,0 +1,402 @@
+// internal/adapters/telegram/bot.go
+package telegram
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"dojo/internal/core"
+	"dojo/internal/domain"
+)
+
+// Префиксы callback_data для кнопок заданий
+const (
+	callbackStart    = "task:start:"
+	callbackComplete = "task:complete:"
+	callbackDecline  = "task:decline:"
+)
+
+// Bot - Telegram-бот Додзё поверх тех же сервисов, что и API
+type Bot struct {
+	client        Client
+	userService   *core.UserService
+	taskService   *core.TaskService
+	senseiService *core.SenseiService
+	webAppURL     string
+}
+
+func NewBot(
+	client Client,
+	userService *core.UserService,
+	taskService *core.TaskService,
+	senseiService *core.SenseiService,
+	webAppURL string,
+) *Bot {
+	return &Bot{
+		client:        client,
+		userService:   userService,
+		taskService:   taskService,
+		senseiService: senseiService,
+		webAppURL:     webAppURL,
+	}
+}
+
+// RunPolling - получение обновлений через long polling до отмены ctx
+func (b *Bot) RunPolling(ctx context.Context) error {
+	// getUpdates не работает, пока установлен вебхук
+	if err := b.client.DeleteWebhook(ctx); err != nil {
+		return err
+	}
+
+	var offset int64
+	for {
+		updates, err := b.client.GetUpdates(ctx, offset, 30)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			log.Println("Ошибка getUpdates:", err)
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(3 * time.Second):
+			}
+			continue
+		}
+
+		for _, update := range updates {
+			offset = update.UpdateID + 1
+			b.HandleUpdate(ctx, update)
+		}
+	}
+}
+
+// SetupWebhook - регистрирует вебхук в Telegram
+func (b *Bot) SetupWebhook(ctx context.Context, url, secretToken string) error {
+	return b.client.SetWebhook(ctx, url, secretToken)
+}
+
+// WebhookHandler - HTTP-обработчик входящих обновлений в режиме вебхука.
+// Без секрета любой, кто знает URL, мог бы действовать от имени любого игрока,
+// поэтому с пустым secretToken отклоняется каждый запрос
+func (b *Bot) WebhookHandler(secretToken string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		got := r.Header.Get("X-Telegram-Bot-Api-Secret-Token")
+		if secretToken == "" || subtle.ConstantTimeCompare([]byte(got), []byte(secretToken)) != 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var update Update
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		b.HandleUpdate(r.Context(), update)
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// HandleUpdate - обработка одного обновления
+func (b *Bot) HandleUpdate(ctx context.Context, update Update) {
+	switch {
+	case update.Message != nil:
+		b.handleMessage(ctx, update.Message)
+	case update.CallbackQuery != nil:
+		b.handleCallback(ctx, update.CallbackQuery)
+	}
+}
+
+func (b *Bot) handleMessage(ctx context.Context, msg *Message) {
+	if msg.From == nil || !strings.HasPrefix(msg.Text, "/") {
+		return
+	}
+
+	command, args, _ := strings.Cut(msg.Text, " ")
+	// Команды в группах приходят как /tasks@dojo_bot
+	command, _, _ = strings.Cut(command, "@")
+	args = strings.TrimSpace(args)
+
+	user, err := b.userService.GetOrCreateUser(ctx, msg.From.ID, msg.From.Username, msg.From.FirstName, "")
+	if err != nil {
+		log.Println("Ошибка получения пользователя:", err)
+		b.reply(ctx, msg.Chat.ID, "⚠️ Додзё временно недоступно, попробуй позже")
+		return
+	}
+
+	switch command {
+	case "/start":
+		b.handleStart(ctx, msg.Chat.ID, user)
+	case "/tasks":
+		b.handleTasks(ctx, msg.Chat.ID, user)
+	case "/profile":
+		b.handleProfile(ctx, msg.Chat.ID, user)
+	case "/sensei":
+		b.handleSensei(ctx, msg.Chat.ID, user, args)
+	default:
+		b.reply(ctx, msg.Chat.ID, "Не знаю такой команды. Доступно: /tasks, /profile, /sensei <вопрос>")
+	}
+}
+
+func (b *Bot) handleStart(ctx context.Context, chatID int64, user *domain.User) {
+	name := user.FirstName
+	if name == "" {
+		name = "охотник"
+	}
+
+	text := fmt.Sprintf(
+		"🥋 Добро пожаловать в Додзё, %s!\n\n"+
+			"Выполняй задания, получай опыт и золото, прокачивай Силу, Ловкость, Интеллект и Проницательность.\n\n"+
+			"/tasks - активные задания\n"+
+			"/profile - твой профиль\n"+
+			"/sensei <вопрос> - спросить Сенсея",
+		name,
+	)
+
+	req := SendMessageRequest{ChatID: chatID, Text: text}
+	if b.webAppURL != "" {
+		req.ReplyMarkup = &InlineKeyboardMarkup{
+			InlineKeyboard: [][]InlineKeyboardButton{{
+				{Text: "⛩ Открыть Додзё", WebApp: &WebAppInfo{URL: b.webAppURL}},
+			}},
+		}
+	}
+
+	b.send(ctx, req)
+}
+
+func (b *Bot) handleTasks(ctx context.Context, chatID int64, user *domain.User) {
+	tasks, err := b.taskService.GetActiveTasks(ctx, user.ID)
+	if err != nil {
+		b.reply(ctx, chatID, "⚠️ "+err.Error())
+		return
+	}
+
+	if len(tasks) == 0 {
+		b.reply(ctx, chatID, "📭 Активных заданий нет")
+		return
+	}
+
+	for _, task := range tasks {
+		b.send(ctx, SendMessageRequest{
+			ChatID:      chatID,
+			Text:        formatTask(task),
+			ReplyMarkup: taskKeyboard(task),
+		})
+	}
+}
+
+func (b *Bot) handleProfile(ctx context.Context, chatID int64, user *domain.User) {
+	profile, err := b.userService.GetProfile(ctx, user.ID)
+	if err != nil {
+		b.reply(ctx, chatID, "⚠️ "+err.Error())
+		return
+	}
+
+	energy := fmt.Sprintf("%d/%d", profile.Energy, profile.MaxEnergy)
+	if profile.EnergyFullAt != nil {
+		energy += fmt.Sprintf(" (полная через %s)", time.Until(*profile.EnergyFullAt).Round(time.Minute))
+	}
+	
+	license := "✅ активна"
+	if !profile.IsLicenseValid() {
+		license = "❌ неактивна"
+	}
+
+	text := fmt.Sprintf(
+		"👤 %s · ранг %s\n"+
+			"Уровень %d (%d/%d XP)\n"+
+			"💰 Золото: %d\n"+
+			"⚡ Энергия: %s\n\n"+
+			"💪 Сила: %s\n"+
+			"🏃 Ловкость: %s\n"+
+			"🧠 Интеллект: %s\n"+
+			"👁 Проницательность: %s\n\n"+
+			"🪪 Лицензия: %s\n"+
+			"🧘 Запросов к Сенсею: %d",
+		profile.DisplayName(), profile.GetRank(),
+		profile.Level, profile.XP, profile.CalculateXPToNextLevel(),
+		profile.Gold,
+		energy,
+		formatStat(profile.Strength, profile.Gear.Strength),
+		formatStat(profile.Agility, profile.Gear.Agility),
+		formatStat(profile.Intelligence, profile.Gear.Intelligence),
+		formatStat(profile.Insight, profile.Gear.Insight),
+		license,
+		profile.SenseiRequests,
+	)
+	if streak := profile.Streak; streak.Days > 0 {
+		text += fmt.Sprintf("\n🔥 Серия: %d дн. (x%.2f XP), заморозок: %d", streak.Days, streak.Bonus.XP, streak.Freezes)
+		if streak.AtRisk {
+			text += "\n⚠️ Сегодня еще ничего не выполнено - серия под угрозой"
+		}
+	}
+	if profile.InPenaltyZone() {
+		text += "\n\n☠️ Ты в зоне наказания: выполни штрафной квест, чтобы снова получать награды"
+	}
+
+	b.reply(ctx, chatID, text)
+}
+
+func (b *Bot) handleSensei(ctx context.Context, chatID int64, user *domain.User, question string) {
+	if question == "" {
+		b.reply(ctx, chatID, "Задай вопрос: /sensei как не бросить тренировки?")
+		return
+	}
+
+	// В боте один непрерывный диалог - продолжаем последний
+	reply, err := b.senseiService.ChatLatest(ctx, user.ID, question)
+	if err != nil {
+		b.reply(ctx, chatID, "⚠️ "+err.Error())
+		return
+	}
+
+	b.reply(ctx, chatID, "🧘 "+reply.Message.Content)
+}
+
+func (b *Bot) handleCallback(ctx context.Context, query *CallbackQuery) {
+	user, err := b.userService.GetOrCreateUser(ctx, query.From.ID, query.From.Username, query.From.FirstName, "")
+	if err != nil {
+		b.answer(ctx, query.ID, "⚠️ Додзё временно недоступно")
+		return
+	}
+
+	var (
+		action string
+		taskID int64
+	)
+	for _, prefix := range []string{callbackStart, callbackComplete, callbackDecline} {
+		if strings.HasPrefix(query.Data, prefix) {
+			action = prefix
+			taskID, err = strconv.ParseInt(strings.TrimPrefix(query.Data, prefix), 10, 64)
+			break
+		}
+	}
+	if action == "" || err != nil {
+		b.answer(ctx, query.ID, "Неизвестное действие")
+		return
+	}
+
+	var text string
+	switch action {
+	case callbackStart:
+		err = b.taskService.StartTask(ctx, taskID, user.ID)
+		text = "▶️ Задание начато"
+	case callbackComplete:
+		var result *core.TaskCompletionResult
+		result, err = b.taskService.CompleteTask(ctx, taskID, user.ID)
+		if err == nil {
+			text = fmt.Sprintf("✅ Задание выполнено: +%d XP, +%d 💰", result.Rewards.XP, result.Rewards.Gold)
+			if result.LeveledUp {
+				text += fmt.Sprintf("\n🎉 Новый уровень: %d!", result.NewLevel)
+			}
+		}
+	case callbackDecline:
+		err = b.taskService.DeclineUrgentCall(ctx, taskID, user.ID)
+		text = "❌ Срочный вызов отклонен"
+	}
+
+	if err != nil {
+		b.answer(ctx, query.ID, "⚠️ "+err.Error())
+		return
+	}
+
+	b.answer(ctx, query.ID, "")
+
+	// Обновляем исходное сообщение, чтобы кнопки не нажимали повторно
+	if query.Message != nil {
+		edit := EditMessageTextRequest{
+			ChatID:    query.Message.Chat.ID,
+			MessageID: query.Message.MessageID,
+			Text:      query.Message.Text + "\n\n" + text,
+		}
+		if err := b.client.EditMessageText(ctx, edit); err != nil {
+			log.Println("Ошибка editMessageText:", err)
+		}
+	}
+}
+
+func (b *Bot) reply(ctx context.Context, chatID int64, text string) {
+	b.send(ctx, SendMessageRequest{ChatID: chatID, Text: text})
+}
+
+func (b *Bot) send(ctx context.Context, req SendMessageRequest) {
+	if _, err := b.client.SendMessage(ctx, req); err != nil {
+		log.Println("Ошибка sendMessage:", err)
+	}
+}
+
+func (b *Bot) answer(ctx context.Context, callbackID, text string) {
+	if err := b.client.AnswerCallbackQuery(ctx, callbackID, text); err != nil {
+		log.Println("Ошибка answerCallbackQuery:", err)
+	}
+}
+
+// formatStat - характеристика с прибавкой снаряжения, если она есть
+func formatStat(base, gear int) string {
+	if gear == 0 {
+		return fmt.Sprintf("%d", base)
+	}
+	return fmt.Sprintf("%d (+%d)", base+gear, gear)
+}
+
+func formatTask(task *domain.Task) string {
+	var sb strings.Builder
+
+	if task.IsUrgent {
+		sb.WriteString("🚨 ")
+	}
+	fmt.Fprintf(&sb, "#%d %s\n", task.ID, task.Title)
+
+	if task.Description != "" {
+		sb.WriteString(task.Description + "\n")
+	}
+
+	fmt.Fprintf(&sb, "Награда: %d XP, %d 💰 · ⚡ %d", task.XPReward, task.GoldReward, task.EnergyCost)
+
+	if task.Status == domain.TaskStatusInProgress {
+		sb.WriteString("\nСтатус: в процессе")
+	}
+
+	if task.IsUrgent {
+		remaining := task.GetTimeRemaining().Round(time.Minute)
+		fmt.Fprintf(&sb, "\n⏳ Осталось: %s · штраф %d 💰", remaining, task.Penalty)
+	}
+
+	return sb.String()
+}
+
+func taskKeyboard(task *domain.Task) *InlineKeyboardMarkup {
+	id := strconv.FormatInt(task.ID, 10)
+
+	var row []InlineKeyboardButton
+	switch task.Status {
+	case domain.TaskStatusActive:
+		row = append(row, InlineKeyboardButton{Text: "▶️ Начать", CallbackData: callbackStart + id})
+	case domain.TaskStatusInProgress:
+		row = append(row, InlineKeyboardButton{Text: "✅ Завершить", CallbackData: callbackComplete + id})
+	}
+
+	if task.IsUrgent {
+		row = append(row, InlineKeyboardButton{Text: "❌ Отказаться", CallbackData: callbackDecline + id})
+	}
+
+	if len(row) == 0 {
+		return nil
+	}
+	return &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{row}}
+}
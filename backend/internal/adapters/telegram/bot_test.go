@@ -0,0 +1,291 @@
+// internal/adapters/telegram/bot_test.go
+package telegram
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"dojo/internal/core"
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+)
+
+// fakeClient - Bot API в памяти: запоминает все, что бот отправил
+type fakeClient struct {
+	mu      sync.Mutex
+	sent    []SendMessageRequest
+	edits   []EditMessageTextRequest
+	answers []string
+}
+
+func (c *fakeClient) GetUpdates(ctx context.Context, offset int64, timeout int) ([]Update, error) {
+	return nil, nil
+}
+
+func (c *fakeClient) SendMessage(ctx context.Context, req SendMessageRequest) (*Message, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent = append(c.sent, req)
+	return &Message{MessageID: int64(len(c.sent)), Chat: Chat{ID: req.ChatID}, Text: req.Text}, nil
+}
+
+func (c *fakeClient) EditMessageText(ctx context.Context, req EditMessageTextRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.edits = append(c.edits, req)
+	return nil
+}
+
+func (c *fakeClient) AnswerCallbackQuery(ctx context.Context, callbackID, text string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.answers = append(c.answers, text)
+	return nil
+}
+
+func (c *fakeClient) SetWebhook(ctx context.Context, url, secretToken string) error { return nil }
+func (c *fakeClient) DeleteWebhook(ctx context.Context) error                       { return nil }
+
+type fakeTx struct{}
+
+func (fakeTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type memUsers struct {
+	ports.UserRepository
+	users map[int64]domain.User
+}
+
+func (r *memUsers) Create(ctx context.Context, user *domain.User) error {
+	user.ID = int64(len(r.users) + 1)
+	r.users[user.ID] = *user
+	return nil
+}
+
+func (r *memUsers) GetByID(ctx context.Context, id int64) (*domain.User, error) {
+	u, ok := r.users[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	return &u, nil
+}
+
+func (r *memUsers) GetByTelegramID(ctx context.Context, telegramID int64) (*domain.User, error) {
+	for _, u := range r.users {
+		if u.TelegramID == telegramID {
+			return &u, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func (r *memUsers) Update(ctx context.Context, user *domain.User) error {
+	r.users[user.ID] = *user
+	return nil
+}
+
+func (r *memUsers) UpdateActivity(ctx context.Context, userID int64) error {
+	return nil
+}
+
+type memTasks struct {
+	ports.TaskRepository
+	tasks map[int64]domain.Task
+}
+
+func (r *memTasks) GetByID(ctx context.Context, id int64) (*domain.Task, error) {
+	t, ok := r.tasks[id]
+	if !ok {
+		return nil, domain.ErrTaskNotFound
+	}
+	return &t, nil
+}
+
+func (r *memTasks) GetActiveByUserID(ctx context.Context, userID int64) ([]*domain.Task, error) {
+	var tasks []*domain.Task
+	for id := int64(1); id <= int64(len(r.tasks)); id++ {
+		t := r.tasks[id]
+		if t.UserID == userID && (t.Status == domain.TaskStatusActive || t.Status == domain.TaskStatusInProgress) {
+			tasks = append(tasks, &t)
+		}
+	}
+	return tasks, nil
+}
+
+func (r *memTasks) Update(ctx context.Context, task *domain.Task) error {
+	r.tasks[task.ID] = *task
+	return nil
+}
+
+type botFixture struct {
+	bot    *Bot
+	client *fakeClient
+	users  *memUsers
+	tasks  *memTasks
+}
+
+func newBotFixture() *botFixture {
+	client := &fakeClient{}
+	users := &memUsers{users: map[int64]domain.User{}}
+	tasks := &memTasks{tasks: map[int64]domain.Task{}}
+
+	energy := domain.DefaultEnergyConfig()
+	streaks := domain.DefaultStreakRules()
+	userService := core.NewUserService(users, fakeTx{}, nil, nil, nil, energy, streaks)
+	taskService := core.NewTaskService(tasks, users, nil, fakeTx{}, nil, nil, nil, energy, streaks)
+
+	return &botFixture{
+		bot:    NewBot(client, userService, taskService, nil, "https://dojo.example/app"),
+		client: client,
+		users:  users,
+		tasks:  tasks,
+	}
+}
+
+func command(telegramID int64, text string) Update {
+	return Update{Message: &Message{
+		MessageID: 1,
+		From:      &User{ID: telegramID, FirstName: "Сон"},
+		Chat:      Chat{ID: telegramID, Type: "private"},
+		Text:      text,
+	}}
+}
+
+func TestBotStart(t *testing.T) {
+	f := newBotFixture()
+	f.bot.HandleUpdate(context.Background(), command(42, "/start"))
+
+	if len(f.users.users) != 1 {
+		t.Fatalf("users = %d, want 1", len(f.users.users))
+	}
+	if len(f.client.sent) != 1 {
+		t.Fatalf("sent = %d, want 1", len(f.client.sent))
+	}
+
+	msg := f.client.sent[0]
+	if msg.ChatID != 42 || !strings.Contains(msg.Text, "Добро пожаловать в Додзё, Сон") {
+		t.Errorf("unexpected greeting: %+v", msg)
+	}
+	if msg.ReplyMarkup == nil || msg.ReplyMarkup.InlineKeyboard[0][0].WebApp == nil {
+		t.Error("greeting has no Mini App button")
+	}
+}
+
+func TestBotTasks(t *testing.T) {
+	f := newBotFixture()
+	f.bot.HandleUpdate(context.Background(), command(42, "/start"))
+	f.client.sent = nil
+
+	f.bot.HandleUpdate(context.Background(), command(42, "/tasks"))
+	if len(f.client.sent) != 1 || !strings.Contains(f.client.sent[0].Text, "Активных заданий нет") {
+		t.Fatalf("empty list: %+v", f.client.sent)
+	}
+	f.client.sent = nil
+
+	f.tasks.tasks[1] = domain.Task{ID: 1, UserID: 1, Title: "Отжимания", Status: domain.TaskStatusActive}
+	f.tasks.tasks[2] = domain.Task{ID: 2, UserID: 1, Title: "Чтение", Status: domain.TaskStatusInProgress}
+	f.tasks.tasks[3] = domain.Task{ID: 3, UserID: 7, Title: "Чужое", Status: domain.TaskStatusActive}
+
+	f.bot.HandleUpdate(context.Background(), command(42, "/tasks"))
+	if len(f.client.sent) != 2 {
+		t.Fatalf("sent = %d, want 2", len(f.client.sent))
+	}
+
+	for i, want := range []string{"task:start:1", "task:complete:2"} {
+		msg := f.client.sent[i]
+		if msg.ReplyMarkup == nil {
+			t.Fatalf("task %d has no buttons", i+1)
+		}
+		if got := msg.ReplyMarkup.InlineKeyboard[0][0].CallbackData; got != want {
+			t.Errorf("callback = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestBotCompleteCallback(t *testing.T) {
+	f := newBotFixture()
+	f.bot.HandleUpdate(context.Background(), command(42, "/start"))
+
+	f.tasks.tasks[1] = domain.Task{
+		ID:         1,
+		UserID:     1,
+		Title:      "Отжимания",
+		TaskType:   domain.TypeStrength,
+		Frequency:  domain.FrequencyDaily,
+		Status:     domain.TaskStatusInProgress,
+		XPReward:   15,
+		GoldReward: 5,
+		StatBoost:  1,
+	}
+	before, _ := f.users.GetByID(context.Background(), 1)
+
+	f.bot.HandleUpdate(context.Background(), Update{CallbackQuery: &CallbackQuery{
+		ID:      "cb1",
+		From:    User{ID: 42, FirstName: "Сон"},
+		Message: &Message{MessageID: 10, Chat: Chat{ID: 42}, Text: "Отжимания"},
+		Data:    "task:complete:1",
+	}})
+
+	if len(f.client.answers) != 1 || f.client.answers[0] != "" {
+		t.Fatalf("answers = %q, want one empty answer", f.client.answers)
+	}
+	if len(f.client.edits) != 1 || !strings.Contains(f.client.edits[0].Text, "Задание выполнено") {
+		t.Fatalf("edits = %+v", f.client.edits)
+	}
+
+	if got := f.tasks.tasks[1].Status; got != domain.TaskStatusCompleted {
+		t.Errorf("task status = %s, want completed", got)
+	}
+	after, _ := f.users.GetByID(context.Background(), 1)
+	if after.Gold <= before.Gold || after.Strength != before.Strength+1 {
+		t.Errorf("rewards not applied: gold %d -> %d, strength %d -> %d", before.Gold, after.Gold, before.Strength, after.Strength)
+	}
+
+	// Повторное нажатие не дает наград второй раз
+	f.bot.HandleUpdate(context.Background(), Update{CallbackQuery: &CallbackQuery{
+		ID:   "cb2",
+		From: User{ID: 42},
+		Data: "task:complete:1",
+	}})
+	if got := f.client.answers[1]; !strings.Contains(got, domain.ErrTaskNotInProgress.Error()) {
+		t.Errorf("second answer = %q", got)
+	}
+}
+
+func TestWebhookRequiresSecret(t *testing.T) {
+	body := `{"update_id":1,"message":{"message_id":1,"from":{"id":42,"first_name":"Сон"},"chat":{"id":42},"text":"/start"}}`
+
+	for _, tc := range []struct {
+		name, secret, header string
+		want                 int
+	}{
+		{"no secret configured", "", "", http.StatusUnauthorized},
+		{"missing header", "s3cret", "", http.StatusUnauthorized},
+		{"wrong header", "s3cret", "guess", http.StatusUnauthorized},
+		{"valid", "s3cret", "s3cret", http.StatusOK},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newBotFixture()
+			req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+			if tc.header != "" {
+				req.Header.Set("X-Telegram-Bot-Api-Secret-Token", tc.header)
+			}
+			rec := httptest.NewRecorder()
+
+			f.bot.WebhookHandler(tc.secret).ServeHTTP(rec, req)
+			if rec.Code != tc.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tc.want)
+			}
+
+			handled := len(f.client.sent) > 0
+			if handled != (tc.want == http.StatusOK) {
+				t.Errorf("update handled = %v", handled)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+// internal/adapters/telegram/client.go
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const DefaultAPIURL = "https://api.telegram.org"
+
+// Client - Telegram Bot API. Интерфейс, чтобы в тестах подменить
+// реальный API локальным фейковым сервером
+type Client interface {
+	GetUpdates(ctx context.Context, offset int64, timeout int) ([]Update, error)
+	SendMessage(ctx context.Context, req SendMessageRequest) (*Message, error)
+	EditMessageText(ctx context.Context, req EditMessageTextRequest) error
+	AnswerCallbackQuery(ctx context.Context, callbackID, text string) error
+	SetWebhook(ctx context.Context, url, secretToken string) error
+	DeleteWebhook(ctx context.Context) error
+}
+
+// APIError - ошибка, которую вернул Bot API
+type APIError struct {
+	Method      string
+	Code        int
+	Description string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram %s: %d %s", e.Method, e.Code, e.Description)
+}
+
+// HTTPClient - реализация Client поверх HTTP
+type HTTPClient struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// NewHTTPClient - baseURL можно указать свой (фейковый сервер), пустой = api.telegram.org
+func NewHTTPClient(baseURL, token string) *HTTPClient {
+	if baseURL == "" {
+		baseURL = DefaultAPIURL
+	}
+
+	return &HTTPClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		// Таймаут больше, чем long polling в getUpdates
+		http: &http.Client{Timeout: 90 * time.Second},
+	}
+}
+
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	ErrorCode   int             `json:"error_code"`
+	Description string          `json:"description"`
+}
+
+func (c *HTTPClient) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/bot%s/%s", c.baseURL, c.token, method)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var apiResp apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return fmt.Errorf("telegram %s: %w", method, err)
+	}
+
+	if !apiResp.OK {
+		return &APIError{
+			Method:      method,
+			Code:        apiResp.ErrorCode,
+			Description: apiResp.Description,
+		}
+	}
+
+	if result != nil {
+		return json.Unmarshal(apiResp.Result, result)
+	}
+	return nil
+}
+
+func (c *HTTPClient) GetUpdates(ctx context.Context, offset int64, timeout int) ([]Update, error) {
+	params := map[string]interface{}{
+		"offset":          offset,
+		"timeout":         timeout,
+		"allowed_updates": []string{"message", "callback_query"},
+	}
+
+	var updates []Update
+	if err := c.call(ctx, "getUpdates", params, &updates); err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
+
+func (c *HTTPClient) SendMessage(ctx context.Context, req SendMessageRequest) (*Message, error) {
+	var msg Message
+	if err := c.call(ctx, "sendMessage", req, &msg); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+func (c *HTTPClient) EditMessageText(ctx context.Context, req EditMessageTextRequest) error {
+	return c.call(ctx, "editMessageText", req, nil)
+}
+
+func (c *HTTPClient) AnswerCallbackQuery(ctx context.Context, callbackID, text string) error {
+	params := map[string]interface{}{
+		"callback_query_id": callbackID,
+		"text":              text,
+	}
+	return c.call(ctx, "answerCallbackQuery", params, nil)
+}
+
+func (c *HTTPClient) SetWebhook(ctx context.Context, url, secretToken string) error {
+	params := map[string]interface{}{
+		"url":             url,
+		"allowed_updates": []string{"message", "callback_query"},
+	}
+	if secretToken != "" {
+		params["secret_token"] = secretToken
+	}
+	return c.call(ctx, "setWebhook", params, nil)
+}
+
+func (c *HTTPClient) DeleteWebhook(ctx context.Context) error {
+	return c.call(ctx, "deleteWebhook", map[string]interface{}{}, nil)
+}
@@ -0,0 +1,48 @@
+// internal/adapters/telegram/notifier.go
+package telegram
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+)
+
+// Notifier - доставка уведомлений личным сообщением от бота
+type Notifier struct {
+	client    Client
+	webAppURL string
+}
+
+func NewNotifier(client Client, webAppURL string) ports.Notifier {
+	return &Notifier{
+		client:    client,
+		webAppURL: webAppURL,
+	}
+}
+
+func (n *Notifier) Send(ctx context.Context, user *domain.User, notification *domain.Notification) error {
+	req := SendMessageRequest{
+		ChatID: user.TelegramID,
+		Text:   notification.Text,
+	}
+
+	if n.webAppURL != "" {
+		req.ReplyMarkup = &InlineKeyboardMarkup{
+			InlineKeyboard: [][]InlineKeyboardButton{{
+				{Text: "⛩ Открыть Додзё", WebApp: &WebAppInfo{URL: n.webAppURL}},
+			}},
+		}
+	}
+
+	_, err := n.client.SendMessage(ctx, req)
+
+	// 403 - бот заблокирован или чат не начат, повторять бессмысленно
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusForbidden {
+		return domain.ErrRecipientUnavailable
+	}
+	return err
+}
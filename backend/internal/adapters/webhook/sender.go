@@ -0,0 +1,71 @@
+// internal/adapters/webhook/sender.go
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+)
+
+// Sender - POST события JSON-ом на внешний адрес. Получатель может проверить
+// подпись X-Dojo-Signature (HMAC-SHA256 тела) и отсеять повторы по X-Dojo-Event-Id
+type Sender struct {
+	url    string
+	secret string
+	http   *http.Client
+}
+
+func NewSender(url, secret string, timeout time.Duration) ports.WebhookSender {
+	return &Sender{
+		url:    url,
+		secret: secret,
+		http:   &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *Sender) Send(ctx context.Context, event domain.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Dojo-Event", string(event.Type))
+	req.Header.Set("X-Dojo-Event-Id", strconv.FormatInt(event.ID, 10))
+	if s.secret != "" {
+		req.Header.Set("X-Dojo-Signature", "sha256="+sign(s.secret, body))
+	}
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("вебхук ответил %d: %s", resp.StatusCode, bytes.TrimSpace(snippet))
+	}
+	return nil
+}
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
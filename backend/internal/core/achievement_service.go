@@ -0,0 +1,233 @@
+// internal/core/achievement_service.go
+package core
+
+import (
+	"context"
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+	"time"
+)
+
+// AchievementService - достижения и титулы. Подписчик шины: продвигает
+// подходящие достижения каталога и выдает разовые награды
+type AchievementService struct {
+	repo     ports.AchievementRepository
+	userRepo ports.UserRepository
+	tx       ports.TxManager
+	events   ports.EventPublisher
+	rules    []*domain.AchievementRule
+	byEvent  map[domain.EventType][]*domain.AchievementRule
+}
+
+func NewAchievementService(
+	repo ports.AchievementRepository,
+	userRepo ports.UserRepository,
+	tx ports.TxManager,
+	events ports.EventPublisher,
+	rules []*domain.AchievementRule,
+) *AchievementService {
+	byEvent := make(map[domain.EventType][]*domain.AchievementRule)
+	for _, rule := range rules {
+		byEvent[rule.Event] = append(byEvent[rule.Event], rule)
+	}
+
+	return &AchievementService{
+		repo:     repo,
+		userRepo: userRepo,
+		tx:       tx,
+		events:   events,
+		rules:    rules,
+		byEvent:  byEvent,
+	}
+}
+
+// AchievementView - достижение каталога вместе с прогрессом игрока
+type AchievementView struct {
+	ID          string                   `json:"id"`
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Badge       string                   `json:"badge"`
+	Title       string                   `json:"title,omitempty"`
+	Hidden      bool                     `json:"hidden"`
+	Reward      domain.AchievementReward `json:"reward"`
+	Progress    int                      `json:"progress"`
+	Target      int                      `json:"target"`
+	Unlocked    bool                     `json:"unlocked"`
+	UnlockedAt  *time.Time               `json:"unlocked_at,omitempty"`
+}
+
+// EventTypes - события, которые продвигают хоть одно достижение каталога
+func (s *AchievementService) EventTypes() []domain.EventType {
+	var types []domain.EventType
+	for _, eventType := range domain.EventTypes {
+		if len(s.byEvent[eventType]) > 0 {
+			types = append(types, eventType)
+		}
+	}
+	return types
+}
+
+// HandleEvent - подписчик шины. Вызывается в транзакции доставки, так что
+// повторная доставка того же события прогресс не удвоит
+func (s *AchievementService) HandleEvent(ctx context.Context, event domain.Event) error {
+	for _, rule := range s.byEvent[event.Type] {
+		if !rule.Matches(event) {
+			continue
+		}
+		if err := s.advance(ctx, rule, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// advance - продвигает одно достижение; при открытии начисляет награду
+func (s *AchievementService) advance(ctx context.Context, rule *domain.AchievementRule, event domain.Event) error {
+	return inTx(ctx, s.tx, func(ctx context.Context) error {
+		achievement, err := s.repo.Get(ctx, event.UserID, rule.ID)
+		isNew := err == domain.ErrAchievementNotFound
+		switch {
+		case isNew:
+			achievement = domain.NewUserAchievement(event.UserID, rule)
+		case err != nil:
+			return err
+		}
+
+		progress := achievement.Progress
+		unlocked := achievement.Advance(rule, event)
+		if !isNew && !unlocked && achievement.Progress == progress {
+			return nil
+		}
+
+		if isNew {
+			created, err := s.repo.Create(ctx, achievement)
+			if err != nil {
+				return err
+			}
+			// Первое событие пришло одновременно из двух мест: перечитаем
+			if !created {
+				return domain.ErrConcurrentModification
+			}
+		} else if err := s.repo.Update(ctx, achievement); err != nil {
+			return err
+		}
+
+		if !unlocked {
+			return nil
+		}
+
+		unlockedEvent := domain.NewEvent(domain.EventAchievementUnlocked, event.UserID, achievement.ID)
+		unlockedEvent.Achievement = rule.ID
+		events := []domain.Event{unlockedEvent}
+
+		if rule.Reward.XP > 0 || rule.Reward.Gold > 0 {
+			user, err := s.userRepo.GetByID(ctx, event.UserID)
+			if err != nil {
+				return err
+			}
+
+			user.Because(domain.ReasonAchievement, achievement.ID)
+			if user.AddXP(rule.Reward.XP) {
+				events = append(events, domain.LevelUpEvent(user))
+			}
+			user.AddGold(rule.Reward.Gold)
+			if err := s.userRepo.Update(ctx, user); err != nil {
+				return err
+			}
+		}
+
+		return publish(ctx, s.events, events...)
+	})
+}
+
+// GetAchievements - весь каталог с прогрессом игрока. Скрытые достижения
+// раскрываются только после открытия
+func (s *AchievementService) GetAchievements(ctx context.Context, userID int64) ([]*AchievementView, error) {
+	progress, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*domain.UserAchievement, len(progress))
+	for _, achievement := range progress {
+		byID[achievement.AchievementID] = achievement
+	}
+
+	views := make([]*AchievementView, 0, len(s.rules))
+	for _, rule := range s.rules {
+		view := &AchievementView{
+			ID:          rule.ID,
+			Name:        rule.Name,
+			Description: rule.Description,
+			Badge:       rule.Badge,
+			Title:       rule.Title,
+			Hidden:      rule.Hidden,
+			Reward:      rule.Reward,
+			Target:      rule.Target,
+		}
+		if achievement, ok := byID[rule.ID]; ok {
+			view.Progress = achievement.Progress
+			view.Unlocked = achievement.IsUnlocked()
+			view.UnlockedAt = achievement.UnlockedAt
+		}
+
+		if view.Hidden && !view.Unlocked {
+			view.Name = "???"
+			view.Description = ""
+			view.Title = ""
+			view.Reward = domain.AchievementReward{}
+		}
+		views = append(views, view)
+	}
+
+	return views, nil
+}
+
+// SetTitle - выбрать титул открытого достижения; пустой achievementID снимает титул
+func (s *AchievementService) SetTitle(ctx context.Context, userID int64, achievementID string) (*domain.User, error) {
+	title := ""
+	if achievementID != "" {
+		rule := s.rule(achievementID)
+		if rule == nil {
+			return nil, domain.ErrAchievementNotFound
+		}
+		if rule.Title == "" {
+			return nil, domain.ErrNoTitle
+		}
+
+		achievement, err := s.repo.Get(ctx, userID, achievementID)
+		if err == domain.ErrAchievementNotFound || (err == nil && !achievement.IsUnlocked()) {
+			return nil, domain.ErrTitleLocked
+		}
+		if err != nil {
+			return nil, err
+		}
+		title = rule.Title
+	}
+
+	var user *domain.User
+	err := retryConflicts(ctx, func() error {
+		var err error
+		user, err = s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		user.SetTitle(title)
+		return s.userRepo.Update(ctx, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *AchievementService) rule(id string) *domain.AchievementRule {
+	for _, rule := range s.rules {
+		if rule.ID == id {
+			return rule
+		}
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+// internal/core/analytics_service.go
+package core
+
+import (
+	"context"
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+	"time"
+)
+
+// AnalyticsService - дневные счетчики доменных событий для админки
+type AnalyticsService struct {
+	repo ports.EventStatsRepository
+}
+
+func NewAnalyticsService(repo ports.EventStatsRepository) *AnalyticsService {
+	return &AnalyticsService{repo: repo}
+}
+
+// HandleEvent - подписчик шины. Счетчик растет в транзакции доставки,
+// поэтому повторная доставка события его не увеличит
+func (s *AnalyticsService) HandleEvent(ctx context.Context, event domain.Event) error {
+	day := domain.QuestDate(event.OccurredAt, time.UTC)
+	return s.repo.Increment(ctx, day, event.Type)
+}
+
+// GetDaily - счетчики за последние days дней, включая сегодня (UTC)
+func (s *AnalyticsService) GetDaily(ctx context.Context, days int) ([]*domain.EventStat, error) {
+	from := domain.QuestDate(time.Now().AddDate(0, 0, -(days-1)), time.UTC)
+	return s.repo.GetSince(ctx, from)
+}
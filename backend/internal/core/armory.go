@@ -0,0 +1,55 @@
+// internal/core/armory.go
+package core
+
+import (
+	"context"
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+)
+
+// Armory - прибавка надетого снаряжения. Считается по инвентарю и текущему
+// каталогу при каждом чтении: сохраненная сумма устаревала бы при смене каталога
+type Armory struct {
+	inventoryRepo ports.InventoryRepository
+	byID          map[string]*domain.Item
+}
+
+func NewArmory(inventoryRepo ports.InventoryRepository, catalog []*domain.Item) *Armory {
+	byID := make(map[string]*domain.Item, len(catalog))
+	for _, item := range catalog {
+		byID[item.ID] = item
+	}
+
+	return &Armory{
+		inventoryRepo: inventoryRepo,
+		byID:          byID,
+	}
+}
+
+// LoadGear - заполняет user.Gear. Без арсенала (nil) снаряжение не учитывается
+func (a *Armory) LoadGear(ctx context.Context, users ...*domain.User) error {
+	if a == nil {
+		return nil
+	}
+
+	for _, user := range users {
+		inventory, err := a.inventoryRepo.GetByUserID(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		user.Gear = domain.GearOf(a.attach(inventory))
+	}
+	return nil
+}
+
+// attach - подставляет предметы каталога; убранные из каталога отбрасывает
+func (a *Armory) attach(inventory []*domain.InventoryItem) []*domain.InventoryItem {
+	known := inventory[:0]
+	for _, slot := range inventory {
+		if item, ok := a.byID[slot.ItemID]; ok {
+			slot.Item = item
+			known = append(known, slot)
+		}
+	}
+	return known
+}
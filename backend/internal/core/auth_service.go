@@ -0,0 +1,178 @@
+// internal/core/auth_service.go
+package core
+
+import (
+	"context"
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuthService struct {
+	userService *UserService
+	sessionRepo ports.SessionRepository
+	tokens      ports.TokenManager
+	refreshTTL  time.Duration
+}
+
+func NewAuthService(
+	userService *UserService,
+	sessionRepo ports.SessionRepository,
+	tokens ports.TokenManager,
+	refreshTTL time.Duration,
+) *AuthService {
+	return &AuthService{
+		userService: userService,
+		sessionRepo: sessionRepo,
+		tokens:      tokens,
+		refreshTTL:  refreshTTL,
+	}
+}
+
+// Login - вход после проверки Telegram, открывает новую сессию
+func (s *AuthService) Login(ctx context.Context, telegramID int64, username, firstName, photoURL string) (*domain.User, *TokenPair, error) {
+	user, err := s.userService.GetOrCreateUser(ctx, telegramID, username, firstName, photoURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := s.openSession(ctx, user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, tokens, nil
+}
+
+// Refresh - выдает новую пару токенов, старый refresh-токен отзывается
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	claims, err := s.tokens.ParseRefreshToken(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.sessionRepo.GetByID(ctx, claims.SessionID)
+	if err != nil {
+		if err == domain.ErrSessionNotFound {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	if session.UserID != claims.UserID {
+		return nil, domain.ErrInvalidToken
+	}
+
+	// Повторное использование отозванного токена - признак утечки,
+	// закрываем все сессии пользователя
+	if session.RevokedAt != nil {
+		return nil, s.revokeReused(ctx, session.UserID)
+	}
+
+	if !session.IsActive() {
+		return nil, domain.ErrTokenExpired
+	}
+
+	// Тот же токен одновременно предъявили дважды: отзыв выиграл другой запрос
+	if err := s.sessionRepo.Revoke(ctx, session.ID); err != nil {
+		if err == domain.ErrSessionRevoked {
+			return nil, s.revokeReused(ctx, session.UserID)
+		}
+		return nil, err
+	}
+
+	return s.openSession(ctx, session.UserID)
+}
+
+// revokeReused - закрывает все сессии пользователя, чей refresh-токен использовали повторно
+func (s *AuthService) revokeReused(ctx context.Context, userID int64) error {
+	if err := s.sessionRepo.RevokeAllByUserID(ctx, userID); err != nil {
+		return err
+	}
+	return domain.ErrSessionRevoked
+}
+
+// Logout - отзывает сессию refresh-токена
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	claims, err := s.tokens.ParseRefreshToken(refreshToken)
+	if err != nil {
+		return err
+	}
+
+	// Выход из уже закрытой сессии - не ошибка
+	if err := s.sessionRepo.Revoke(ctx, claims.SessionID); err != nil && err != domain.ErrSessionRevoked {
+		return err
+	}
+	return nil
+}
+
+// LogoutAll - отзывает все сессии пользователя
+func (s *AuthService) LogoutAll(ctx context.Context, userID int64) error {
+	return s.sessionRepo.RevokeAllByUserID(ctx, userID)
+}
+
+// Authenticate - проверяет access-токен и возвращает ID пользователя.
+// Сессия токена должна быть жива: после выхода или отзыва всех сессий
+// access-токен перестает действовать сразу, а не по истечении срока
+func (s *AuthService) Authenticate(ctx context.Context, accessToken string) (int64, error) {
+	claims, err := s.tokens.ParseAccessToken(accessToken)
+	if err != nil {
+		return 0, err
+	}
+
+	session, err := s.sessionRepo.GetByID(ctx, claims.SessionID)
+	if err != nil {
+		if err == domain.ErrSessionNotFound {
+			return 0, domain.ErrInvalidToken
+		}
+		return 0, err
+	}
+
+	switch {
+	case session.UserID != claims.UserID:
+		return 0, domain.ErrInvalidToken
+	case session.RevokedAt != nil:
+		return 0, domain.ErrSessionRevoked
+	case !session.IsActive():
+		return 0, domain.ErrTokenExpired
+	}
+	return claims.UserID, nil
+}
+
+func (s *AuthService) openSession(ctx context.Context, userID int64) (*TokenPair, error) {
+	session := &domain.Session{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(s.refreshTTL),
+	}
+
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	accessToken, accessExpiresAt, err := s.tokens.IssueAccessToken(userID, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := s.tokens.IssueRefreshToken(userID, session.ID, session.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:      accessToken,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+type TokenPair struct {
+	AccessToken      string    `json:"access_token"`
+	AccessExpiresAt  time.Time `json:"access_expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
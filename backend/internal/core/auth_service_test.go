@@ -0,0 +1,254 @@
+// internal/core/auth_service_test.go
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+)
+
+// fakeTokens - токены вида "access:<user>:<session>" без подписи
+type fakeTokens struct{}
+
+func (fakeTokens) IssueAccessToken(userID int64, sessionID string) (string, time.Time, error) {
+	return fmt.Sprintf("access:%d:%s", userID, sessionID), time.Now().Add(15 * time.Minute), nil
+}
+
+func (fakeTokens) IssueRefreshToken(userID int64, sessionID string, expiresAt time.Time) (string, error) {
+	return fmt.Sprintf("refresh:%d:%s", userID, sessionID), nil
+}
+
+func (fakeTokens) ParseAccessToken(token string) (*ports.TokenClaims, error) {
+	return parseFakeToken("access", token)
+}
+
+func (fakeTokens) ParseRefreshToken(token string) (*ports.TokenClaims, error) {
+	return parseFakeToken("refresh", token)
+}
+
+func parseFakeToken(kind, token string) (*ports.TokenClaims, error) {
+	claims := &ports.TokenClaims{}
+	if _, err := fmt.Sscanf(token, kind+":%d:%s", &claims.UserID, &claims.SessionID); err != nil {
+		return nil, domain.ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// memSessions - сессии в памяти; Revoke отзывает только живую сессию, как в postgres
+type memSessions struct {
+	mu       sync.Mutex
+	sessions map[string]domain.Session
+	// afterGet - вызывается после чтения сессии, чтобы вклинить параллельный запрос
+	afterGet func(id string)
+}
+
+func (r *memSessions) Create(ctx context.Context, session *domain.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessions[session.ID] = *session
+	return nil
+}
+
+func (r *memSessions) GetByID(ctx context.Context, id string) (*domain.Session, error) {
+	r.mu.Lock()
+	session, ok := r.sessions[id]
+	hook := r.afterGet
+	r.afterGet = nil
+	r.mu.Unlock()
+
+	if !ok {
+		return nil, domain.ErrSessionNotFound
+	}
+	if hook != nil {
+		hook(id)
+	}
+	return &session, nil
+}
+
+func (r *memSessions) Revoke(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[id]
+	if !ok || session.RevokedAt != nil {
+		return domain.ErrSessionRevoked
+	}
+	session.Revoke()
+	r.sessions[id] = session
+	return nil
+}
+
+func (r *memSessions) RevokeAllByUserID(ctx context.Context, userID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, session := range r.sessions {
+		if session.UserID == userID {
+			session.Revoke()
+			r.sessions[id] = session
+		}
+	}
+	return nil
+}
+
+func (r *memSessions) active(userID int64) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := 0
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.IsActive() {
+			n++
+		}
+	}
+	return n
+}
+
+func newAuthFixture() (*AuthService, *memSessions) {
+	sessions := &memSessions{sessions: map[string]domain.Session{}}
+	return NewAuthService(nil, sessions, fakeTokens{}, time.Hour), sessions
+}
+
+func TestRefreshRotatesSession(t *testing.T) {
+	ctx := context.Background()
+	service, sessions := newAuthFixture()
+
+	first, err := service.openSession(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := service.Refresh(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatal("refresh token was not rotated")
+	}
+	if n := sessions.active(1); n != 1 {
+		t.Errorf("active sessions = %d, want only the new one", n)
+	}
+
+	if _, err := service.Authenticate(ctx, first.AccessToken); err != domain.ErrSessionRevoked {
+		t.Errorf("old access token: err = %v, want ErrSessionRevoked", err)
+	}
+	if userID, err := service.Authenticate(ctx, second.AccessToken); err != nil || userID != 1 {
+		t.Errorf("new access token: user %d, err %v", userID, err)
+	}
+}
+
+func TestRefreshReuseRevokesAllSessions(t *testing.T) {
+	ctx := context.Background()
+	service, sessions := newAuthFixture()
+
+	stolen, _ := service.openSession(ctx, 1)
+	other, _ := service.openSession(ctx, 1)
+	stranger, _ := service.openSession(ctx, 2)
+
+	rotated, err := service.Refresh(ctx, stolen.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Отозванный refresh-токен предъявили снова
+	if _, err := service.Refresh(ctx, stolen.RefreshToken); err != domain.ErrSessionRevoked {
+		t.Fatalf("reuse: err = %v, want ErrSessionRevoked", err)
+	}
+	if n := sessions.active(1); n != 0 {
+		t.Errorf("active sessions after reuse = %d, want 0", n)
+	}
+	for _, pair := range []*TokenPair{rotated, other} {
+		if _, err := service.Authenticate(ctx, pair.AccessToken); err != domain.ErrSessionRevoked {
+			t.Errorf("access token after reuse: err = %v, want ErrSessionRevoked", err)
+		}
+	}
+	if _, err := service.Authenticate(ctx, stranger.AccessToken); err != nil {
+		t.Errorf("other user's session closed: %v", err)
+	}
+}
+
+func TestRefreshLostRevokeRaceIsReuse(t *testing.T) {
+	ctx := context.Background()
+	service, sessions := newAuthFixture()
+
+	pair, _ := service.openSession(ctx, 1)
+	other, _ := service.openSession(ctx, 1)
+
+	// Между чтением сессии и отзывом тот же токен успел обменять другой запрос
+	sessions.afterGet = func(id string) {
+		if err := sessions.Revoke(ctx, id); err != nil {
+			t.Errorf("concurrent revoke: %v", err)
+		}
+	}
+
+	if _, err := service.Refresh(ctx, pair.RefreshToken); err != domain.ErrSessionRevoked {
+		t.Fatalf("err = %v, want ErrSessionRevoked", err)
+	}
+	if n := sessions.active(1); n != 0 {
+		t.Errorf("active sessions = %d, want 0", n)
+	}
+	if _, err := service.Authenticate(ctx, other.AccessToken); err != domain.ErrSessionRevoked {
+		t.Errorf("other session access: err = %v, want ErrSessionRevoked", err)
+	}
+}
+
+func TestAuthenticateAfterLogout(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newAuthFixture()
+
+	pair, _ := service.openSession(ctx, 1)
+	kept, _ := service.openSession(ctx, 1)
+
+	if _, err := service.Authenticate(ctx, pair.AccessToken); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.Logout(ctx, pair.RefreshToken); err != nil {
+		t.Fatal(err)
+	}
+	// Повторный выход - не ошибка
+	if err := service.Logout(ctx, pair.RefreshToken); err != nil {
+		t.Errorf("second logout: %v", err)
+	}
+
+	if _, err := service.Authenticate(ctx, pair.AccessToken); err != domain.ErrSessionRevoked {
+		t.Errorf("after logout: err = %v, want ErrSessionRevoked", err)
+	}
+	if _, err := service.Authenticate(ctx, kept.AccessToken); err != nil {
+		t.Errorf("other session: %v", err)
+	}
+
+	if err := service.LogoutAll(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Authenticate(ctx, kept.AccessToken); err != domain.ErrSessionRevoked {
+		t.Errorf("after logout-all: err = %v, want ErrSessionRevoked", err)
+	}
+}
+
+func TestAuthenticateRejectsForeignAndExpiredSessions(t *testing.T) {
+	ctx := context.Background()
+	service, sessions := newAuthFixture()
+
+	pair, _ := service.openSession(ctx, 1)
+	claims, _ := parseFakeToken("access", pair.AccessToken)
+
+	forged := fmt.Sprintf("access:2:%s", claims.SessionID)
+	if _, err := service.Authenticate(ctx, forged); err != domain.ErrInvalidToken {
+		t.Errorf("foreign session: err = %v, want ErrInvalidToken", err)
+	}
+	if _, err := service.Authenticate(ctx, "access:1:missing"); err != domain.ErrInvalidToken {
+		t.Errorf("unknown session: err = %v, want ErrInvalidToken", err)
+	}
+
+	session := sessions.sessions[claims.SessionID]
+	session.ExpiresAt = time.Now().Add(-time.Minute)
+	sessions.sessions[claims.SessionID] = session
+	if _, err := service.Authenticate(ctx, pair.AccessToken); err != domain.ErrTokenExpired {
+		t.Errorf("expired session: err = %v, want ErrTokenExpired", err)
+	}
+}
@@ -0,0 +1,95 @@
+// internal/core/event_bus.go
+package core
+
+import (
+	"context"
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// EventHandler - подписчик на доменные события. Доставка "хотя бы раз":
+// обработчик вызывается в транзакции вместе с отметкой о доставке, но внешние
+// эффекты (сообщения, вебхуки) при сбое могут повториться
+type EventHandler func(ctx context.Context, event domain.Event) error
+
+type subscription struct {
+	handler EventHandler
+	// Пусто - все события
+	types map[domain.EventType]bool
+}
+
+// EventBus - реестр подписчиков внутри процесса. Событиям, сохраненным в outbox,
+// релей создает по доставке на каждого подписчика и вызывает его по имени
+type EventBus struct {
+	mu            sync.RWMutex
+	subscriptions map[string]*subscription
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{subscriptions: make(map[string]*subscription)}
+}
+
+// Subscribe - подписка под именем name на события указанных типов; без типов - на все.
+// Имя хранится в доставках, поэтому его нельзя менять между релизами
+func (b *EventBus) Subscribe(name string, handler EventHandler, types ...domain.EventType) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscriptions[name]; ok {
+		panic(fmt.Sprintf("подписчик событий %q уже зарегистрирован", name))
+	}
+
+	sub := &subscription{handler: handler}
+	if len(types) > 0 {
+		sub.types = make(map[domain.EventType]bool, len(types))
+		for _, eventType := range types {
+			sub.types[eventType] = true
+		}
+	}
+	b.subscriptions[name] = sub
+}
+
+// Subscribers - имена подписчиков события, по алфавиту
+func (b *EventBus) Subscribers(eventType domain.EventType) []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var names []string
+	for name, sub := range b.subscriptions {
+		if sub.types == nil || sub.types[eventType] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Deliver - передает событие подписчику name. Паника подписчика становится
+// ошибкой доставки, иначе доставка повторялась бы без счета попыток
+func (b *EventBus) Deliver(ctx context.Context, name string, event domain.Event) (err error) {
+	b.mu.RLock()
+	sub, ok := b.subscriptions[name]
+	b.mu.RUnlock()
+
+	if !ok {
+		return domain.ErrUnknownSubscriber
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("паника в подписчике %s: %v", name, r)
+		}
+	}()
+	return sub.handler(ctx, event)
+}
+
+// publish - сохраняет события, если сервису дали издателя
+func publish(ctx context.Context, events ports.EventPublisher, batch ...domain.Event) error {
+	if events == nil || len(batch) == 0 {
+		return nil
+	}
+	return events.Publish(ctx, batch...)
+}
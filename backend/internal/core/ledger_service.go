@@ -0,0 +1,39 @@
+// internal/core/ledger_service.go
+package core
+
+import (
+	"context"
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+)
+
+// LedgerService - история изменений ресурсов и сверка балансов
+type LedgerService struct {
+	ledgerRepo ports.LedgerRepository
+}
+
+func NewLedgerService(ledgerRepo ports.LedgerRepository) *LedgerService {
+	return &LedgerService{ledgerRepo: ledgerRepo}
+}
+
+// History - записи журнала игрока, от новых к старым
+func (s *LedgerService) History(ctx context.Context, userID int64, resource domain.LedgerResource, limit, offset int) ([]*domain.LedgerEntry, error) {
+	if resource != "" && !isLedgerResource(resource) {
+		return nil, domain.ErrUnknownResource
+	}
+	return s.ledgerRepo.ListByUserID(ctx, userID, resource, limit, offset)
+}
+
+// Audit - игроки, у которых баланс разошелся с журналом
+func (s *LedgerService) Audit(ctx context.Context, limit int) ([]*domain.LedgerDiscrepancy, error) {
+	return s.ledgerRepo.Audit(ctx, limit)
+}
+
+func isLedgerResource(resource domain.LedgerResource) bool {
+	for _, r := range domain.LedgerResources {
+		if r == resource {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,211 @@
+// internal/core/maintenance_service.go
+package core
+
+import (
+	"context"
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+	"time"
+)
+
+// MaintenanceConfig - расписания и параметры фоновых задач
+type MaintenanceConfig struct {
+	ExpireTasksSpec    string
+	ExpireLicensesSpec string
+	ResetSenseiSpec    string
+
+	// Сколько записей читать за раз
+	BatchSize int
+}
+
+func DefaultMaintenanceConfig() MaintenanceConfig {
+	return MaintenanceConfig{
+		ExpireTasksSpec:    "@every 1m",
+		ExpireLicensesSpec: "*/5 * * * *",
+		ResetSenseiSpec:    "*/15 * * * *",
+		BatchSize:          100,
+	}
+}
+
+// MaintenanceService - периодическое обслуживание: сроки и сбросы.
+// Энергия восстанавливается лениво (User.RegenerateEnergy), задача для нее не нужна
+type MaintenanceService struct {
+	userRepo ports.UserRepository
+	taskRepo ports.TaskRepository
+	tx       ports.TxManager
+	events   ports.EventPublisher
+	config   MaintenanceConfig
+}
+
+func NewMaintenanceService(
+	userRepo ports.UserRepository,
+	taskRepo ports.TaskRepository,
+	tx ports.TxManager,
+	events ports.EventPublisher,
+	config MaintenanceConfig,
+) *MaintenanceService {
+	return &MaintenanceService{
+		userRepo: userRepo,
+		taskRepo: taskRepo,
+		tx:       tx,
+		events:   events,
+		config:   config,
+	}
+}
+
+// Jobs - задачи для планировщика
+func (s *MaintenanceService) Jobs() []Job {
+	return []Job{
+		{Name: "expire_tasks", Spec: s.config.ExpireTasksSpec, Run: s.ExpireTasks},
+		{Name: "expire_licenses", Spec: s.config.ExpireLicensesSpec, Run: s.ExpireLicenses},
+		{Name: "reset_sensei", Spec: s.config.ResetSenseiSpec, Run: s.ResetSensei},
+	}
+}
+
+// ExpireTasks - просроченные срочные вызовы истекают, игрок платит штраф
+func (s *MaintenanceService) ExpireTasks(ctx context.Context) (int, error) {
+	tasks, err := s.taskRepo.GetUrgentDueBefore(ctx, time.Now(), s.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	failures := batchErrors{job: "expire_tasks"}
+	for _, due := range tasks {
+		if ctx.Err() != nil {
+			return expired, ctx.Err()
+		}
+
+		done := false
+		err := inTx(ctx, s.tx, func(ctx context.Context) error {
+			task, err := s.taskRepo.GetByID(ctx, due.ID)
+			if err != nil {
+				return err
+			}
+
+			// Пока ждали, игрок мог успеть завершить или отказаться
+			done = task.Status == domain.TaskStatusActive || task.Status == domain.TaskStatusInProgress
+			if !done {
+				return nil
+			}
+
+			user, err := s.userRepo.GetByID(ctx, task.UserID)
+			if err != nil {
+				return err
+			}
+
+			task.Expire()
+			user.Because(domain.ReasonTaskExpired, task.ID).AddGold(-task.Penalty)
+
+			if err := s.taskRepo.Update(ctx, task); err != nil {
+				return err
+			}
+			if err := s.userRepo.Update(ctx, user); err != nil {
+				return err
+			}
+
+			event := domain.NewEvent(domain.EventTaskExpired, task.UserID, task.ID)
+			event.TaskType = task.TaskType
+			event.Frequency = task.Frequency
+			event.Urgent = task.IsUrgent
+			event.Gold = task.Penalty
+			return publish(ctx, s.events, event)
+		})
+		if err != nil {
+			failures.add(due.ID, err)
+			continue
+		}
+
+		if done {
+			expired++
+		}
+	}
+
+	return expired, failures.err()
+}
+
+// ExpireLicenses - отзывает лицензии с истекшим сроком
+func (s *MaintenanceService) ExpireLicenses(ctx context.Context) (int, error) {
+	users, err := s.userRepo.GetWithExpiredLicense(ctx, s.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	revoked := 0
+	failures := batchErrors{job: "expire_licenses"}
+	for _, candidate := range users {
+		if ctx.Err() != nil {
+			return revoked, ctx.Err()
+		}
+
+		done := false
+		err := inTx(ctx, s.tx, func(ctx context.Context) error {
+			user, err := s.userRepo.GetByID(ctx, candidate.ID)
+			if err != nil {
+				return err
+			}
+
+			// Лицензию могли продлить между выборкой и обработкой
+			done = user.LicenseActive && !user.IsLicenseValid()
+			if !done {
+				return nil
+			}
+
+			user.RevokeLicense()
+			if err := s.userRepo.Update(ctx, user); err != nil {
+				return err
+			}
+			return publish(ctx, s.events, domain.NewEvent(domain.EventLicenseExpired, user.ID, 0))
+		})
+		if err != nil {
+			failures.add(candidate.ID, err)
+			continue
+		}
+
+		if done {
+			revoked++
+		}
+	}
+
+	return revoked, failures.err()
+}
+
+// ResetSensei - недельное пополнение запросов к Сенсею
+func (s *MaintenanceService) ResetSensei(ctx context.Context) (int, error) {
+	users, err := s.userRepo.GetSenseiResetDue(ctx, time.Now(), s.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	reset := 0
+	failures := batchErrors{job: "reset_sensei"}
+	for _, candidate := range users {
+		if ctx.Err() != nil {
+			return reset, ctx.Err()
+		}
+
+		done := false
+		err := retryConflicts(ctx, func() error {
+			user, err := s.userRepo.GetByID(ctx, candidate.ID)
+			if err != nil {
+				return err
+			}
+
+			done = user.ResetSenseiIfDue(time.Now())
+			if !done {
+				return nil
+			}
+			return s.userRepo.Update(ctx, user)
+		})
+		if err != nil {
+			failures.add(candidate.ID, err)
+			continue
+		}
+
+		if done {
+			reset++
+		}
+	}
+
+	return reset, failures.err()
+}
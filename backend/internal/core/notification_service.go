@@ -0,0 +1,389 @@
+// internal/core/notification_service.go
+package core
+
+import (
+	"context"
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+	"fmt"
+	"log"
+	"time"
+)
+
+// NotificationConfig - параметры уведомлений
+type NotificationConfig struct {
+	// За сколько до UrgentUntil напоминать о срочном вызове
+	ReminderOffsets []time.Duration
+	// Сколько уведомлений доставлять за один проход
+	BatchSize int
+
+	// Расписания задач планировщика
+	RemindSpec  string
+	DeliverSpec string
+}
+
+func DefaultNotificationConfig() NotificationConfig {
+	return NotificationConfig{
+		ReminderOffsets: []time.Duration{time.Hour, 15 * time.Minute},
+		BatchSize:       100,
+		RemindSpec:      "@every 1m",
+		DeliverSpec:     "@every 10s",
+	}
+}
+
+type NotificationService struct {
+	repo     ports.NotificationRepository
+	userRepo ports.UserRepository
+	taskRepo ports.TaskRepository
+	notifier ports.Notifier
+	// Каталоги - для текстов уведомлений о добыче и достижениях
+	items  map[string]*domain.Item
+	rules  map[string]*domain.AchievementRule
+	config NotificationConfig
+}
+
+func NewNotificationService(
+	repo ports.NotificationRepository,
+	userRepo ports.UserRepository,
+	taskRepo ports.TaskRepository,
+	notifier ports.Notifier,
+	items []*domain.Item,
+	rules []*domain.AchievementRule,
+	config NotificationConfig,
+) *NotificationService {
+	itemsByID := make(map[string]*domain.Item, len(items))
+	for _, item := range items {
+		itemsByID[item.ID] = item
+	}
+	rulesByID := make(map[string]*domain.AchievementRule, len(rules))
+	for _, rule := range rules {
+		rulesByID[rule.ID] = rule
+	}
+
+	return &NotificationService{
+		repo:     repo,
+		userRepo: userRepo,
+		taskRepo: taskRepo,
+		notifier: notifier,
+		items:    itemsByID,
+		rules:    rulesByID,
+		config:   config,
+	}
+}
+
+// GetSettings - настройки уведомлений игрока
+func (s *NotificationService) GetSettings(ctx context.Context, userID int64) (*domain.NotificationSettings, error) {
+	return s.repo.GetSettings(ctx, userID)
+}
+
+// UpdateSettings - сохранить настройки уведомлений
+func (s *NotificationService) UpdateSettings(ctx context.Context, settings *domain.NotificationSettings) error {
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+	return s.repo.SaveSettings(ctx, settings)
+}
+
+// HandleEvent - подписчик шины: ставит в очередь уведомления о событиях из outbox.
+// Очередь пишется в транзакции доставки, а ключи дедупликации не дают повторной
+// доставке события поставить второе сообщение
+func (s *NotificationService) HandleEvent(ctx context.Context, event domain.Event) error {
+	switch event.Type {
+	case domain.EventLevelUp:
+		return s.NotifyLevelUp(ctx, event.UserID, event.Level, event.Rank)
+	case domain.EventPenaltyCleared:
+		return s.NotifyPenaltyCleared(ctx, event.UserID, event.RefID)
+	case domain.EventRaided, domain.EventRaidDefended:
+		return s.notifyRaided(ctx, event)
+	case domain.EventTaskExpired:
+		return s.notifyTaskExpired(ctx, event)
+	case domain.EventLicenseExpired:
+		return s.notifyLicenseExpired(ctx, event)
+	case domain.EventPenaltyEntered:
+		return s.notifyPenaltyEntered(ctx, event)
+	case domain.EventItemDropped:
+		return s.notifyItemDropped(ctx, event)
+	case domain.EventAchievementUnlocked:
+		return s.notifyAchievement(ctx, event)
+	}
+	return nil
+}
+
+// NotificationEventTypes - события, о которых игроку приходит уведомление
+func NotificationEventTypes() []domain.EventType {
+	return []domain.EventType{
+		domain.EventLevelUp,
+		domain.EventRaided,
+		domain.EventRaidDefended,
+		domain.EventTaskExpired,
+		domain.EventLicenseExpired,
+		domain.EventPenaltyEntered,
+		domain.EventPenaltyCleared,
+		domain.EventItemDropped,
+		domain.EventAchievementUnlocked,
+	}
+}
+
+// NotifyLevelUp - уведомление о новом уровне
+func (s *NotificationService) NotifyLevelUp(ctx context.Context, userID int64, level int, rank string) error {
+	text := fmt.Sprintf("🎉 Новый уровень: %d! Ранг %s. Энергия восстановлена.", level, rank)
+	_, err := s.notify(ctx, userID, domain.NotificationLevelUp, fmt.Sprintf("level:%d", level), text, nil)
+	return err
+}
+
+// notifyRaided - уведомление жертве рейда
+func (s *NotificationService) notifyRaided(ctx context.Context, event domain.Event) error {
+	attacker, err := s.userRepo.GetByID(ctx, event.OpponentID)
+	if err != nil {
+		return err
+	}
+
+	text := fmt.Sprintf("⚔️ Тебя ограбил %s: -%d 💰. Возвращайся в Додзё, пока не ограбили снова!", attacker.DisplayName(), event.Gold)
+	if event.Type == domain.EventRaidDefended {
+		text = fmt.Sprintf("🛡 %s пытался тебя ограбить, но ты отбился. Возвращайся в Додзё и стань еще сильнее!", attacker.DisplayName())
+	}
+	key := fmt.Sprintf("raided:%d", event.RefID)
+	_, err = s.notify(ctx, event.UserID, domain.NotificationRaided, key, text, nil)
+	return err
+}
+
+// Jobs - задачи для планировщика. Очередь разбирает только лидер:
+// две реплики, читающие одни и те же pending-строки, отправили бы сообщение дважды
+func (s *NotificationService) Jobs() []Job {
+	return []Job{
+		{Name: "urgent_reminders", Spec: s.config.RemindSpec, Run: s.ScheduleUrgentReminders},
+		{Name: "deliver_notifications", Spec: s.config.DeliverSpec, Run: s.DeliverPending},
+	}
+}
+
+// ScheduleUrgentReminders - ставит в очередь напоминания перед UrgentUntil
+func (s *NotificationService) ScheduleUrgentReminders(ctx context.Context) (int, error) {
+	now := time.Now()
+
+	var maxOffset time.Duration
+	for _, offset := range s.config.ReminderOffsets {
+		if offset > maxOffset {
+			maxOffset = offset
+		}
+	}
+
+	// Только еще не истекшие: просроченные, которых не успел списать
+	// планировщик, заняли бы весь лимит и вытеснили живые вызовы
+	tasks, err := s.taskRepo.GetUrgentDueBetween(ctx, now, now.Add(maxOffset), s.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	scheduled := 0
+	failures := batchErrors{job: "urgent_reminders"}
+	for _, task := range tasks {
+		if ctx.Err() != nil {
+			return scheduled, ctx.Err()
+		}
+
+		deadline := *task.UrgentUntil
+
+		// Об истечении сообщает планировщик, когда спишет штраф
+		if !deadline.After(now) {
+			continue
+		}
+
+		// Только самое близкое из наступивших напоминаний, чтобы не слать пачку разом
+		var offset time.Duration = -1
+		for _, o := range s.config.ReminderOffsets {
+			if now.After(deadline.Add(-o)) && (offset < 0 || o < offset) {
+				offset = o
+			}
+		}
+		if offset < 0 {
+			continue
+		}
+
+		text := fmt.Sprintf(
+			"🚨 Срочный вызов «%s» истекает через %s! Штраф за провал: %d 💰",
+			task.Title, time.Until(deadline).Round(time.Minute), task.Penalty,
+		)
+		key := fmt.Sprintf("reminder:%d:%d", task.ID, int(offset.Minutes()))
+		created, err := s.notify(ctx, task.UserID, domain.NotificationUrgentReminder, key, text, &deadline)
+		if err != nil {
+			failures.add(task.ID, err)
+			continue
+		}
+		if created {
+			scheduled++
+		}
+	}
+
+	return scheduled, failures.err()
+}
+
+// notifyTaskExpired - срочный вызов истек, штраф списан
+func (s *NotificationService) notifyTaskExpired(ctx context.Context, event domain.Event) error {
+	task, err := s.taskRepo.GetByID(ctx, event.RefID)
+	if err != nil {
+		return err
+	}
+
+	text := fmt.Sprintf("⌛ Срочный вызов «%s» истек. Штраф: %d 💰", task.Title, task.Penalty)
+	_, err = s.notify(ctx, task.UserID, domain.NotificationTaskExpired, fmt.Sprintf("expired:%d", task.ID), text, nil)
+	return err
+}
+
+// notifyLicenseExpired - лицензия игрока отозвана по сроку. Одно событие - одно уведомление
+func (s *NotificationService) notifyLicenseExpired(ctx context.Context, event domain.Event) error {
+	text := "🪪 Лицензия охотника истекла: награды за задания урезаны вдвое. Продли ее в Додзё."
+	key := fmt.Sprintf("license:%d", event.ID)
+	_, err := s.notify(ctx, event.UserID, domain.NotificationLicenseExpired, key, text, nil)
+	return err
+}
+
+// notifyPenaltyEntered - игрок не выполнил квесты и попал в зону наказания
+func (s *NotificationService) notifyPenaltyEntered(ctx context.Context, event domain.Event) error {
+	quest, err := s.taskRepo.GetByID(ctx, event.RefID)
+	if err != nil {
+		return err
+	}
+
+	text := fmt.Sprintf("☠️ Не выполнено ежедневных квестов: %d. Ты в зоне наказания: награды заблокированы, "+
+		"каждый час уходит энергия, а без нее - золото. Выход один - «%s».", event.Missed, quest.Title)
+	_, err = s.notify(ctx, event.UserID, domain.NotificationPenaltyZone, fmt.Sprintf("penalty:%d", quest.ID), text, nil)
+	return err
+}
+
+// NotifyPenaltyCleared - штрафной квест выполнен, зона снята
+func (s *NotificationService) NotifyPenaltyCleared(ctx context.Context, userID, questID int64) error {
+	text := "✅ Штрафной квест выполнен. Ты покинул зону наказания - награды снова твои."
+	_, err := s.notify(ctx, userID, domain.NotificationPenaltyCleared, fmt.Sprintf("penalty_cleared:%d", questID), text, nil)
+	return err
+}
+
+// notifyAchievement - игрок открыл достижение
+func (s *NotificationService) notifyAchievement(ctx context.Context, event domain.Event) error {
+	rule, ok := s.rules[event.Achievement]
+	if !ok {
+		// Достижение убрали из каталога, пока событие ждало доставки
+		return nil
+	}
+
+	text := fmt.Sprintf("%s Достижение открыто: «%s»!", rule.Badge, rule.Name)
+	if rule.Reward.XP > 0 || rule.Reward.Gold > 0 {
+		text += fmt.Sprintf(" Награда: +%d XP, +%d 💰.", rule.Reward.XP, rule.Reward.Gold)
+	}
+	if rule.Title != "" {
+		text += fmt.Sprintf(" Открыт титул «%s».", rule.Title)
+	}
+	_, err := s.notify(ctx, event.UserID, domain.NotificationAchievement, "achievement:"+rule.ID, text, nil)
+	return err
+}
+
+// notifyItemDropped - уведомление о добыче; Gold > 0 - дубликат обменян на золото
+func (s *NotificationService) notifyItemDropped(ctx context.Context, event domain.Event) error {
+	item, ok := s.items[event.Item]
+	if !ok {
+		// Предмет убрали из каталога, пока событие ждало доставки
+		return nil
+	}
+
+	text := fmt.Sprintf("%s Добыча: «%s»! Загляни в инвентарь.", item.Icon, item.Name)
+	if event.Gold > 0 {
+		text = fmt.Sprintf("%s Добыча: «%s», но такой предмет у тебя уже есть. Получено +%d 💰.", item.Icon, item.Name, event.Gold)
+	}
+	_, err := s.notify(ctx, event.UserID, domain.NotificationItemDrop, fmt.Sprintf("drop:%d", event.ID), text, nil)
+	return err
+}
+
+// DeliverPending - доставка накопленных уведомлений с учетом настроек игроков
+func (s *NotificationService) DeliverPending(ctx context.Context) (int, error) {
+	pending, err := s.repo.GetPending(ctx, time.Now(), s.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	settings := make(map[int64]*domain.NotificationSettings)
+	for _, n := range pending {
+		userSettings, ok := settings[n.UserID]
+		if !ok {
+			userSettings, err = s.repo.GetSettings(ctx, n.UserID)
+			if err != nil {
+				return sent, err
+			}
+			settings[n.UserID] = userSettings
+		}
+
+		if err := s.deliver(ctx, n, userSettings); err != nil {
+			return sent, err
+		}
+		if n.Status == domain.NotificationSent {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// notify - только ставит уведомление в очередь, отправляет DeliverPending.
+// Подписчики шины вызывают notify в транзакции доставки события: сообщение,
+// ушедшее в Telegram до отката, пришло бы повторно, а сетевой вызов держал бы
+// транзакцию открытой. true - уведомление новое. Ошибку очереди подписчик
+// возвращает шине, чтобы событие доставили повторно, а не потеряли сообщение
+func (s *NotificationService) notify(ctx context.Context, userID int64, kind domain.NotificationKind, key, text string, expiresAt *time.Time) (bool, error) {
+	n := &domain.Notification{
+		UserID:    userID,
+		Kind:      kind,
+		DedupKey:  key,
+		Text:      text,
+		Status:    domain.NotificationPending,
+		ExpiresAt: expiresAt,
+		// Первая попытка - на ближайшем проходе доставки
+		NextAttemptAt: time.Now(),
+	}
+
+	created, err := s.repo.Enqueue(ctx, n)
+	if err != nil {
+		return false, fmt.Errorf("постановка уведомления %s в очередь: %w", key, err)
+	}
+	return created, nil
+}
+
+// deliver - отправка одного уведомления; в тихие часы откладывает его до их конца
+func (s *NotificationService) deliver(ctx context.Context, n *domain.Notification, settings *domain.NotificationSettings) error {
+	now := time.Now()
+
+	if s.notifier == nil || !settings.Enabled || n.IsStale(now) {
+		n.Status = domain.NotificationSkipped
+		return s.repo.Update(ctx, n)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, n.UserID)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			n.Status = domain.NotificationSkipped
+			return s.repo.Update(ctx, n)
+		}
+		return err
+	}
+
+	// Тихие часы - по часам игрока
+	if local := now.In(user.Location()); settings.IsQuietAt(local) {
+		n.NextAttemptAt = settings.QuietUntil(local)
+		return s.repo.Update(ctx, n)
+	}
+
+	err = s.notifier.Send(ctx, user, n)
+	switch {
+	case err == nil:
+		n.Status = domain.NotificationSent
+		n.SentAt = &now
+	case err == domain.ErrRecipientUnavailable:
+		n.Status = domain.NotificationSkipped
+	default:
+		log.Printf("Не удалось отправить уведомление %d: %v", n.ID, err)
+		n.Attempts++
+		if n.Attempts >= domain.MaxNotificationAttempts {
+			n.Status = domain.NotificationFailed
+		}
+	}
+
+	return s.repo.Update(ctx, n)
+}
@@ -0,0 +1,183 @@
+// internal/core/notification_service_test.go
+package core
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+)
+
+// memNotifications - очередь в памяти; failKeys - ключи, на которых Enqueue падает
+type memNotifications struct {
+	ports.NotificationRepository
+	queued   map[string]*domain.Notification
+	failKeys map[string]bool
+}
+
+func (r *memNotifications) Enqueue(ctx context.Context, n *domain.Notification) (bool, error) {
+	if r.failKeys[n.DedupKey] {
+		return false, errors.New("connection reset")
+	}
+	if _, ok := r.queued[n.DedupKey]; ok {
+		return false, nil
+	}
+	r.queued[n.DedupKey] = n
+	return true, nil
+}
+
+// urgentTasks - срочные задания, отданные планировщику напоминаний
+type urgentTasks struct {
+	ports.TaskRepository
+	tasks []*domain.Task
+}
+
+func (r *urgentTasks) GetUrgentDueBetween(ctx context.Context, after, before time.Time, limit int) ([]*domain.Task, error) {
+	var due []*domain.Task
+	for _, task := range r.tasks {
+		if task.UrgentUntil.After(after) && !task.UrgentUntil.After(before) && len(due) < limit {
+			due = append(due, task)
+		}
+	}
+	return due, nil
+}
+
+func urgentTask(id int64, left time.Duration) *domain.Task {
+	until := time.Now().Add(left)
+	return &domain.Task{ID: id, UserID: 1, Title: "Вызов", IsUrgent: true, UrgentUntil: &until}
+}
+
+func TestScheduleUrgentRemindersContinuesPastEnqueueError(t *testing.T) {
+	repo := &memNotifications{
+		queued:   map[string]*domain.Notification{},
+		failKeys: map[string]bool{"reminder:1:15": true},
+	}
+	tasks := &urgentTasks{tasks: []*domain.Task{
+		urgentTask(1, 10*time.Minute),
+		urgentTask(2, 10*time.Minute),
+		urgentTask(3, 50*time.Minute),
+	}}
+	service := NewNotificationService(repo, nil, tasks, nil, nil, nil, DefaultNotificationConfig())
+
+	scheduled, err := service.ScheduleUrgentReminders(context.Background())
+	if err == nil {
+		t.Error("enqueue failure was swallowed")
+	}
+	if scheduled != 2 {
+		t.Errorf("scheduled = %d, want 2", scheduled)
+	}
+	for _, key := range []string{"reminder:2:15", "reminder:3:60"} {
+		if repo.queued[key] == nil {
+			t.Errorf("reminder %s lost", key)
+		}
+	}
+
+	// Следующий проход дозаписывает пропущенное и не дублирует остальное
+	delete(repo.failKeys, "reminder:1:15")
+	scheduled, err = service.ScheduleUrgentReminders(context.Background())
+	if err != nil || scheduled != 1 {
+		t.Errorf("retry: scheduled %d, err %v; want 1, nil", scheduled, err)
+	}
+}
+
+func TestHandleEventReturnsEnqueueError(t *testing.T) {
+	repo := &memNotifications{
+		queued:   map[string]*domain.Notification{},
+		failKeys: map[string]bool{"level:5": true},
+	}
+	service := NewNotificationService(repo, nil, nil, nil, nil, nil, DefaultNotificationConfig())
+
+	event := domain.NewEvent(domain.EventLevelUp, 1, 0)
+	event.Level = 5
+	if err := service.HandleEvent(context.Background(), event); err == nil {
+		t.Fatal("relay would mark the event delivered without a notification")
+	}
+
+	delete(repo.failKeys, "level:5")
+	if err := service.HandleEvent(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+	if repo.queued["level:5"] == nil {
+		t.Error("notification not queued on redelivery")
+	}
+}
+
+func TestHandleEventItemDropped(t *testing.T) {
+	repo := &memNotifications{queued: map[string]*domain.Notification{}}
+	items := []*domain.Item{{ID: "katana", Name: "Катана", Icon: "🗡"}}
+	service := NewNotificationService(repo, nil, nil, nil, items, nil, DefaultNotificationConfig())
+
+	dropped := domain.NewEvent(domain.EventItemDropped, 1, 10)
+	dropped.ID = 7
+	dropped.Item = "katana"
+	salvaged := dropped
+	salvaged.ID = 8
+	salvaged.Gold = 40
+	lost := dropped
+	lost.ID = 9
+	lost.Item = "removed"
+
+	for _, event := range []domain.Event{dropped, salvaged, lost} {
+		if err := service.HandleEvent(context.Background(), event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n := repo.queued["drop:7"]; n == nil || n.Kind != domain.NotificationItemDrop {
+		t.Errorf("drop notification = %+v", n)
+	}
+	if n := repo.queued["drop:8"]; n == nil || !strings.Contains(n.Text, "+40 💰") {
+		t.Errorf("salvage notification = %+v", n)
+	}
+	if len(repo.queued) != 2 {
+		t.Errorf("queued = %d, want 2: unknown item is skipped", len(repo.queued))
+	}
+}
+
+func TestHandleEventAchievementUnlocked(t *testing.T) {
+	repo := &memNotifications{queued: map[string]*domain.Notification{}}
+	rules := []*domain.AchievementRule{{ID: "first_blood", Name: "Первая кровь", Badge: "🩸", Title: "Охотник"}}
+	service := NewNotificationService(repo, nil, nil, nil, nil, rules, DefaultNotificationConfig())
+
+	event := domain.NewEvent(domain.EventAchievementUnlocked, 1, 3)
+	event.Achievement = "first_blood"
+	for i := 0; i < 2; i++ {
+		if err := service.HandleEvent(context.Background(), event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	n := repo.queued["achievement:first_blood"]
+	if n == nil || !strings.Contains(n.Text, "«Охотник»") {
+		t.Fatalf("achievement notification = %+v", n)
+	}
+	if len(repo.queued) != 1 {
+		t.Errorf("queued = %d, want 1", len(repo.queued))
+	}
+}
+
+func TestScheduleUrgentRemindersSkipsExpiredBacklog(t *testing.T) {
+	repo := &memNotifications{queued: map[string]*domain.Notification{}}
+	config := DefaultNotificationConfig()
+	config.BatchSize = 2
+
+	// Просроченные вызовы еще не списаны планировщиком и стоят первыми по дедлайну
+	tasks := &urgentTasks{tasks: []*domain.Task{
+		urgentTask(1, -30*time.Minute),
+		urgentTask(2, -10*time.Minute),
+		urgentTask(3, 10*time.Minute),
+	}}
+	service := NewNotificationService(repo, nil, tasks, nil, nil, nil, config)
+
+	scheduled, err := service.ScheduleUrgentReminders(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scheduled != 1 || repo.queued["reminder:3:15"] == nil {
+		t.Errorf("scheduled = %d, queued = %v; want the live call reminded", scheduled, repo.queued)
+	}
+}
@@ -0,0 +1,178 @@
+// internal/core/outbox.go
+package core
+
+import (
+	"context"
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+	"time"
+)
+
+// OutboxPublisher - ports.EventPublisher поверх outbox: события пишутся
+// в транзакции из ctx, так что без изменения они не появятся
+type OutboxPublisher struct {
+	repo ports.OutboxRepository
+}
+
+func NewOutboxPublisher(repo ports.OutboxRepository) *OutboxPublisher {
+	return &OutboxPublisher{repo: repo}
+}
+
+func (p *OutboxPublisher) Publish(ctx context.Context, events ...domain.Event) error {
+	batch := make([]*domain.OutboxEvent, 0, len(events))
+	for _, event := range events {
+		batch = append(batch, domain.NewOutboxEvent(event))
+	}
+	return p.repo.Append(ctx, batch)
+}
+
+// RelayConfig - доставка событий из outbox
+type RelayConfig struct {
+	// Как часто забирать новые события и повторять неудачные доставки
+	RelaySpec string
+	// Как часто чистить доставленные события и сколько их хранить
+	PurgeSpec string
+	Retention time.Duration
+
+	BatchSize int
+	Policy    domain.DeliveryPolicy
+}
+
+func DefaultRelayConfig() RelayConfig {
+	return RelayConfig{
+		RelaySpec: "@every 2s",
+		PurgeSpec: "17 3 * * *",
+		Retention: 7 * 24 * time.Hour,
+		BatchSize: 200,
+		Policy:    domain.DefaultDeliveryPolicy(),
+	}
+}
+
+// EventRelay - раскладывает события outbox по подписчикам шины и доставляет их.
+// Работает только у лидера планировщика, но события может публиковать любой процесс
+type EventRelay struct {
+	repo   ports.OutboxRepository
+	bus    *EventBus
+	tx     ports.TxManager
+	config RelayConfig
+}
+
+func NewEventRelay(repo ports.OutboxRepository, bus *EventBus, tx ports.TxManager, config RelayConfig) *EventRelay {
+	return &EventRelay{
+		repo:   repo,
+		bus:    bus,
+		tx:     tx,
+		config: config,
+	}
+}
+
+// Jobs - задачи для планировщика
+func (r *EventRelay) Jobs() []Job {
+	return []Job{
+		{Name: "relay_events", Spec: r.config.RelaySpec, Run: r.Relay},
+		{Name: "purge_events", Spec: r.config.PurgeSpec, Run: r.Purge},
+	}
+}
+
+// Relay - создает доставки для новых событий и выполняет те, чье время пришло.
+// Возвращает число успешных доставок
+func (r *EventRelay) Relay(ctx context.Context) (int, error) {
+	if err := r.dispatch(ctx); err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	deliveries, err := r.repo.GetDueDeliveries(ctx, now, r.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, delivery := range deliveries {
+		ok, err := r.deliver(ctx, delivery, now)
+		if err != nil {
+			return delivered, err
+		}
+		if ok {
+			delivered++
+		}
+	}
+
+	return delivered, nil
+}
+
+// dispatch - доставка на каждого подписчика, которому интересен тип события
+func (r *EventRelay) dispatch(ctx context.Context) error {
+	events, err := r.repo.GetUndispatched(ctx, r.config.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, event := range events {
+		var deliveries []*domain.EventDelivery
+		for _, name := range r.bus.Subscribers(event.Type) {
+			deliveries = append(deliveries, domain.NewEventDelivery(event.ID, name, now))
+		}
+
+		err := r.tx.WithinTx(ctx, func(ctx context.Context) error {
+			return r.repo.Dispatch(ctx, event, deliveries)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deliver - обработчик и отметка о доставке в одной транзакции: изменения
+// подписчика в базе не применятся дважды. Ошибка подписчика - повод повторить,
+// а не прерывать остальные доставки
+func (r *EventRelay) deliver(ctx context.Context, delivery *domain.EventDelivery, now time.Time) (bool, error) {
+	event := delivery.Event.Event()
+
+	var done domain.EventDelivery
+	err := inTx(ctx, r.tx, func(ctx context.Context) error {
+		if err := r.bus.Deliver(ctx, delivery.Subscriber, event); err != nil {
+			return err
+		}
+		// Копия: если транзакция откатится, отметка о доставке не должна остаться
+		done = *delivery
+		done.Delivered(time.Now())
+		return r.repo.UpdateDelivery(ctx, &done)
+	})
+	if err == nil {
+		*delivery = done
+		return true, nil
+	}
+
+	delivery.Fail(err, now, r.config.Policy)
+	return false, r.repo.UpdateDelivery(ctx, delivery)
+}
+
+// Purge - удаляет старые события, доставленные всем подписчикам
+func (r *EventRelay) Purge(ctx context.Context) (int, error) {
+	return r.repo.PurgeDelivered(ctx, time.Now().Add(-r.config.Retention))
+}
+
+// GetDeadDeliveries - мертвая очередь для разбора
+func (r *EventRelay) GetDeadDeliveries(ctx context.Context, limit, offset int) ([]*domain.EventDelivery, error) {
+	return r.repo.GetDeadDeliveries(ctx, limit, offset)
+}
+
+// Retry - вернуть доставку из мертвой очереди; релей подхватит ее при следующем запуске
+func (r *EventRelay) Retry(ctx context.Context, deliveryID int64) (*domain.EventDelivery, error) {
+	delivery, err := r.repo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := delivery.Requeue(time.Now()); err != nil {
+		return nil, err
+	}
+	if err := r.repo.UpdateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
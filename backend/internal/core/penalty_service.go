@@ -0,0 +1,265 @@
+// internal/core/penalty_service.go
+package core
+
+import (
+	"context"
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+	"time"
+)
+
+// PenaltyConfig - правила зоны наказания и расписания ее задач
+type PenaltyConfig struct {
+	Rules domain.PenaltyRules
+
+	// Проверка, у кого закончился день с невыполненными квестами
+	CheckSpec string
+	// Списание ресурсов с тех, кто в зоне
+	DrainSpec string
+
+	BatchSize int
+}
+
+func DefaultPenaltyConfig() PenaltyConfig {
+	return PenaltyConfig{
+		Rules:     domain.DefaultPenaltyRules(),
+		CheckSpec: "@every 1m",
+		DrainSpec: "*/5 * * * *",
+		BatchSize: 500,
+	}
+}
+
+// PenaltyService - зона наказания: за невыполненные к концу дня квесты игрок
+// получает штрафной квест, а пока не выполнит его - теряет ресурсы и не получает наград
+type PenaltyService struct {
+	userRepo ports.UserRepository
+	taskRepo ports.TaskRepository
+	tx       ports.TxManager
+	events   ports.EventPublisher
+	energy   domain.EnergyConfig
+	config   PenaltyConfig
+}
+
+func NewPenaltyService(
+	userRepo ports.UserRepository,
+	taskRepo ports.TaskRepository,
+	tx ports.TxManager,
+	events ports.EventPublisher,
+	config PenaltyConfig,
+	energy domain.EnergyConfig,
+) *PenaltyService {
+	return &PenaltyService{
+		userRepo: userRepo,
+		taskRepo: taskRepo,
+		tx:       tx,
+		events:   events,
+		energy:   energy,
+		config:   config,
+	}
+}
+
+// Jobs - задачи для планировщика
+func (s *PenaltyService) Jobs() []Job {
+	return []Job{
+		{Name: "penalty_check", Spec: s.config.CheckSpec, Run: s.CheckOverdue},
+		{Name: "penalty_drain", Spec: s.config.DrainSpec, Run: s.Drain},
+	}
+}
+
+// PenaltyStatus - состояние зоны наказания игрока
+type PenaltyStatus struct {
+	Active      bool         `json:"active"`
+	Since       *time.Time   `json:"since,omitempty"`
+	Quest       *domain.Task `json:"quest,omitempty"`
+	NextDrainAt *time.Time   `json:"next_drain_at,omitempty"`
+	DrainEnergy int          `json:"drain_energy"`
+	DrainGold   int          `json:"drain_gold"`
+}
+
+// GetStatus - в зоне ли игрок и что нужно сделать, чтобы выйти
+func (s *PenaltyService) GetStatus(ctx context.Context, userID int64) (*PenaltyStatus, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &PenaltyStatus{
+		Active:      user.InPenaltyZone(),
+		Since:       user.PenaltySince,
+		NextDrainAt: user.NextPenaltyDrain(s.config.Rules),
+		DrainEnergy: s.config.Rules.DrainEnergy,
+		DrainGold:   s.config.Rules.DrainGold,
+	}
+	if user.InPenaltyZone() {
+		status.Quest, err = s.taskRepo.GetByID(ctx, *user.PenaltyTaskID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return status, nil
+}
+
+// GetQuest - текущий штрафной квест; domain.ErrNotInPenaltyZone вне зоны
+func (s *PenaltyService) GetQuest(ctx context.Context, userID int64) (*domain.Task, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.InPenaltyZone() {
+		return nil, domain.ErrNotInPenaltyZone
+	}
+	return s.taskRepo.GetByID(ctx, *user.PenaltyTaskID)
+}
+
+// GetHistory - все попадания игрока в зону: штрафные квесты, от новых к старым
+func (s *PenaltyService) GetHistory(ctx context.Context, userID int64, limit, offset int) ([]*domain.Task, error) {
+	return s.taskRepo.GetPenaltyQuests(ctx, userID, limit, offset)
+}
+
+// CheckOverdue - проваливает квесты, чей день закончился, и отправляет их владельцев в зону
+func (s *PenaltyService) CheckOverdue(ctx context.Context) (int, error) {
+	now := time.Now()
+	tasks, err := s.taskRepo.GetOverdueQuests(ctx, now, s.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	// Задания отсортированы по игроку: разбираем каждого одной транзакцией
+	var (
+		userIDs []int64
+		byUser  = make(map[int64][]int64)
+	)
+	for _, task := range tasks {
+		if _, ok := byUser[task.UserID]; !ok {
+			userIDs = append(userIDs, task.UserID)
+		}
+		byUser[task.UserID] = append(byUser[task.UserID], task.ID)
+	}
+
+	entered := 0
+	failures := batchErrors{job: "penalty_check"}
+	for _, userID := range userIDs {
+		if ctx.Err() != nil {
+			return entered, ctx.Err()
+		}
+
+		done, err := s.punish(ctx, userID, byUser[userID], now)
+		if err != nil {
+			failures.add(userID, err)
+			continue
+		}
+
+		if done {
+			entered++
+		}
+	}
+
+	return entered, failures.err()
+}
+
+// punish - проваливает просроченные квесты игрока. Если он еще не в зоне,
+// назначает штрафной квест. true - игрок попал в зону
+func (s *PenaltyService) punish(ctx context.Context, userID int64, taskIDs []int64, now time.Time) (bool, error) {
+	entered := false
+
+	err := inTx(ctx, s.tx, func(ctx context.Context) error {
+		entered = false
+
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		missed := 0
+
+		today := domain.QuestDate(now, user.Location())
+		for _, id := range taskIDs {
+			task, err := s.taskRepo.GetByID(ctx, id)
+			if err != nil {
+				return err
+			}
+
+			// Пока ждали, квест могли выполнить, а игрок - сменить пояс
+			if task.Status != domain.TaskStatusActive && task.Status != domain.TaskStatusInProgress {
+				continue
+			}
+			if task.QuestDate >= today {
+				continue
+			}
+
+			task.Fail()
+			if err := s.taskRepo.Update(ctx, task); err != nil {
+				return err
+			}
+			missed++
+		}
+
+		// Уже в зоне: второй штрафной квест не назначаем
+		if missed == 0 || user.InPenaltyZone() {
+			return nil
+		}
+
+		quest := domain.NewPenaltyQuest(userID, s.config.Rules)
+		if err := s.taskRepo.CreatePenaltyQuest(ctx, quest); err != nil {
+			return err
+		}
+
+		user.EnterPenaltyZone(quest.ID, now)
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+
+		event := domain.NewEvent(domain.EventPenaltyEntered, userID, quest.ID)
+		event.Missed = missed
+		entered = true
+		return publish(ctx, s.events, event)
+	})
+	return entered, err
+}
+
+// Drain - списывает энергию, а когда ее нет - золото, со всех, кто в зоне
+func (s *PenaltyService) Drain(ctx context.Context) (int, error) {
+	now := time.Now()
+	users, err := s.userRepo.GetPenaltyDrainDue(ctx, now.Add(-s.config.Rules.DrainInterval), s.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	drained := 0
+	failures := batchErrors{job: "penalty_drain"}
+	for _, candidate := range users {
+		if ctx.Err() != nil {
+			return drained, ctx.Err()
+		}
+
+		done := false
+		err := retryConflicts(ctx, func() error {
+			user, err := s.userRepo.GetByID(ctx, candidate.ID)
+			if err != nil {
+				return err
+			}
+
+			// Штрафной квест успели выполнить
+			if !user.InPenaltyZone() {
+				return nil
+			}
+
+			// Сначала начисляем накопленное: иначе зона заберет меньше, чем есть
+			user.RegenerateEnergy(now, s.energy)
+			energy, gold := user.DrainPenalty(now, s.config.Rules)
+			done = energy > 0 || gold > 0
+			return s.userRepo.Update(ctx, user)
+		})
+		if err != nil {
+			failures.add(candidate.ID, err)
+			continue
+		}
+
+		if done {
+			drained++
+		}
+	}
+
+	return drained, failures.err()
+}
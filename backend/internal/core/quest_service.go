@@ -0,0 +1,185 @@
+// internal/core/quest_service.go
+package core
+
+import (
+	"context"
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+	"log"
+	"strings"
+	"time"
+)
+
+// QuestConfig - генерация ежедневных квестов
+type QuestConfig struct {
+	// Как часто проверять, не наступил ли у кого-то новый день
+	GenerateSpec string
+	// Сколько шаблонов обрабатывать за запуск
+	BatchSize int
+}
+
+func DefaultQuestConfig() QuestConfig {
+	return QuestConfig{
+		GenerateSpec: "@every 1m",
+		BatchSize:    500,
+	}
+}
+
+// QuestService - шаблоны повторяющихся квестов и ежедневная генерация заданий по ним
+type QuestService struct {
+	templateRepo ports.QuestTemplateRepository
+	taskRepo     ports.TaskRepository
+	userRepo     ports.UserRepository
+	tx           ports.TxManager
+	config       QuestConfig
+}
+
+func NewQuestService(
+	templateRepo ports.QuestTemplateRepository,
+	taskRepo ports.TaskRepository,
+	userRepo ports.UserRepository,
+	tx ports.TxManager,
+	config QuestConfig,
+) *QuestService {
+	return &QuestService{
+		templateRepo: templateRepo,
+		taskRepo:     taskRepo,
+		userRepo:     userRepo,
+		tx:           tx,
+		config:       config,
+	}
+}
+
+// Jobs - задачи для планировщика
+func (s *QuestService) Jobs() []Job {
+	return []Job{
+		{Name: "generate_quests", Spec: s.config.GenerateSpec, Run: s.GenerateDue},
+	}
+}
+
+// CreateTemplate - новый повторяющийся квест; если сегодня день по расписанию,
+// задание на сегодня создается сразу
+func (s *QuestService) CreateTemplate(ctx context.Context, userID int64, title, description string, taskType domain.TaskType, rule string) (*domain.QuestTemplate, error) {
+	recurrence, err := domain.ParseRecurrence(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	template := domain.NewQuestTemplate(userID, strings.TrimSpace(title), description, taskType, recurrence, domain.QuestDate(time.Now(), user.Location()))
+	if err := s.templateRepo.Create(ctx, template); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.generate(ctx, template.ID, time.Now()); err != nil {
+		// Не страшно: задание создаст следующий запуск генератора
+		log.Printf("Квест %d: не удалось создать задание на сегодня: %v", template.ID, err)
+	}
+
+	return template, nil
+}
+
+// GetTemplates - активные шаблоны игрока
+func (s *QuestService) GetTemplates(ctx context.Context, userID int64) ([]*domain.QuestTemplate, error) {
+	return s.templateRepo.GetByUserID(ctx, userID)
+}
+
+// DeleteTemplate - шаблон перестает генерировать задания; уже созданные остаются
+func (s *QuestService) DeleteTemplate(ctx context.Context, userID, templateID int64) error {
+	return retryConflicts(ctx, func() error {
+		template, err := s.templateRepo.GetByID(ctx, templateID)
+		if err != nil {
+			return err
+		}
+		if template.UserID != userID || !template.Active {
+			return domain.ErrQuestTemplateNotFound
+		}
+
+		template.Active = false
+		return s.templateRepo.Update(ctx, template)
+	})
+}
+
+// GetToday - ежедневные квесты игрока за его сегодняшний день
+func (s *QuestService) GetToday(ctx context.Context, userID int64) ([]*domain.Task, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.taskRepo.GetDailyTasks(ctx, userID, domain.QuestDate(time.Now(), user.Location()))
+}
+
+// GenerateDue - создает задания тем, у кого в их поясе наступил новый день.
+// Повторный запуск ничего не дублирует: день отмечается в шаблоне, а задание
+// защищено уникальным индексом (template_id, quest_date)
+func (s *QuestService) GenerateDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	templates, err := s.templateRepo.GetDue(ctx, now, s.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	failures := batchErrors{job: "generate_quests"}
+	for _, due := range templates {
+		if ctx.Err() != nil {
+			return created, ctx.Err()
+		}
+
+		ok, err := s.generate(ctx, due.ID, now)
+		if err != nil {
+			failures.add(due.ID, err)
+			continue
+		}
+		if ok {
+			created++
+		}
+	}
+
+	return created, failures.err()
+}
+
+// generate - задание по шаблону за текущий день его владельца; true, если создано
+func (s *QuestService) generate(ctx context.Context, templateID int64, now time.Time) (bool, error) {
+	created := false
+	err := inTx(ctx, s.tx, func(ctx context.Context) error {
+		created = false
+
+		template, err := s.templateRepo.GetByID(ctx, templateID)
+		if err != nil {
+			return err
+		}
+
+		user, err := s.userRepo.GetByID(ctx, template.UserID)
+		if err != nil {
+			return err
+		}
+
+		today := domain.QuestDate(now, user.Location())
+		// Шаблон отключили или день уже отработал другой запуск
+		if !template.Active || template.LastRunOn >= today {
+			return nil
+		}
+
+		due, err := template.IsDue(today)
+		if err != nil {
+			return err
+		}
+		if !due {
+			template.Skip(today)
+			return s.templateRepo.Update(ctx, template)
+		}
+
+		created, err = s.taskRepo.CreateQuest(ctx, template.Spawn(today))
+		if err != nil {
+			return err
+		}
+		return s.templateRepo.Update(ctx, template)
+	})
+
+	return created, err
+}
@@ -0,0 +1,194 @@
+// internal/core/quest_service_test.go
+package core
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+)
+
+type passTx struct{}
+
+func (passTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// memQuests - шаблоны и созданные по ним задания с уникальностью (template_id, quest_date)
+type memQuests struct {
+	mu        sync.Mutex
+	templates map[int64]domain.QuestTemplate
+	tasks     []domain.Task
+}
+
+type memTemplateRepo struct {
+	ports.QuestTemplateRepository
+	*memQuests
+}
+
+type memQuestTaskRepo struct {
+	ports.TaskRepository
+	*memQuests
+}
+
+func (r memTemplateRepo) GetByID(ctx context.Context, id int64) (*domain.QuestTemplate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	template, ok := r.templates[id]
+	if !ok {
+		return nil, domain.ErrQuestTemplateNotFound
+	}
+	return &template, nil
+}
+
+// GetDue - все активные шаблоны по порядку ID
+func (r memTemplateRepo) GetDue(ctx context.Context, now time.Time, limit int) ([]*domain.QuestTemplate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []*domain.QuestTemplate
+	for id := int64(1); id <= int64(len(r.templates)); id++ {
+		if template, ok := r.templates[id]; ok && template.Active {
+			due = append(due, &template)
+		}
+	}
+	return due, nil
+}
+
+func (r memTemplateRepo) Update(ctx context.Context, template *domain.QuestTemplate) error {
+	// Оба запуска успевают прочитать шаблон до записи
+	time.Sleep(time.Millisecond)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.templates[template.ID].Version != template.Version {
+		return domain.ErrConcurrentModification
+	}
+	template.Version++
+	r.templates[template.ID] = *template
+	return nil
+}
+
+func (r memQuestTaskRepo) CreateQuest(ctx context.Context, task *domain.Task) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, t := range r.tasks {
+		if *t.TemplateID == *task.TemplateID && t.QuestDate == task.QuestDate {
+			return false, nil
+		}
+	}
+	task.ID = int64(len(r.tasks) + 1)
+	r.tasks = append(r.tasks, *task)
+	return true, nil
+}
+
+func newQuestFixture(timezone, rule, startsOn string) (*QuestService, *memQuests) {
+	quests := &memQuests{
+		templates: map[int64]domain.QuestTemplate{
+			1: {ID: 1, UserID: 1, Title: "Растяжка", TaskType: domain.TypeAgility, Rule: rule, StartsOn: startsOn, Active: true},
+		},
+	}
+	users := &memUserRepo{store: &memStore{users: map[int64]domain.User{1: {ID: 1, Timezone: timezone}}}}
+
+	service := NewQuestService(memTemplateRepo{memQuests: quests}, memQuestTaskRepo{memQuests: quests}, users, passTx{}, DefaultQuestConfig())
+	return service, quests
+}
+
+func TestGenerateTwiceCreatesOneTask(t *testing.T) {
+	service, quests := newQuestFixture("Asia/Tokyo", "FREQ=DAILY", "2026-10-14")
+	// В Токио уже 17 октября
+	now := time.Date(2026, 10, 16, 23, 0, 0, 0, time.UTC)
+
+	for run := 1; run <= 2; run++ {
+		created, err := service.generate(context.Background(), 1, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if created != (run == 1) {
+			t.Errorf("run %d: created = %v", run, created)
+		}
+	}
+
+	if len(quests.tasks) != 1 {
+		t.Fatalf("tasks = %d, want 1", len(quests.tasks))
+	}
+	if got := quests.tasks[0].QuestDate; got != "2026-10-17" {
+		t.Errorf("quest date = %s, want the player's 2026-10-17", got)
+	}
+	if got := quests.templates[1].LastRunOn; got != "2026-10-17" {
+		t.Errorf("last run = %s", got)
+	}
+
+	// Новый день игрока - новое задание
+	if created, err := service.generate(context.Background(), 1, now.Add(24*time.Hour)); err != nil || !created {
+		t.Fatalf("next day: created = %v, %v", created, err)
+	}
+}
+
+func TestGenerateConcurrentRunsCreateOneTask(t *testing.T) {
+	service, quests := newQuestFixture("Europe/Berlin", "FREQ=WEEKLY;INTERVAL=2;BYDAY=SA", "2026-10-14")
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+
+	const runs = 4
+	errs := make(chan error, runs)
+
+	var wg sync.WaitGroup
+	for i := 0; i < runs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := service.generate(context.Background(), 1, now)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("generate: %v", err)
+		}
+	}
+	if len(quests.tasks) != 1 {
+		t.Errorf("tasks = %d, want 1", len(quests.tasks))
+	}
+}
+
+func TestGenerateSkipsDayOffSchedule(t *testing.T) {
+	service, quests := newQuestFixture("UTC", "FREQ=WEEKLY;INTERVAL=2;BYDAY=SA", "2026-10-14")
+
+	for _, day := range []int{17, 24, 31} {
+		if _, err := service.generate(context.Background(), 1, time.Date(2026, 10, day, 12, 0, 0, 0, time.UTC)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(quests.tasks) != 2 || quests.tasks[0].QuestDate != "2026-10-17" || quests.tasks[1].QuestDate != "2026-10-31" {
+		t.Errorf("tasks = %+v, want 17th and 31st", quests.tasks)
+	}
+	if got := quests.templates[1].LastRunOn; got != "2026-10-31" {
+		t.Errorf("last run = %s", got)
+	}
+}
+
+func TestGenerateDueContinuesPastBrokenTemplate(t *testing.T) {
+	service, quests := newQuestFixture("UTC", "FREQ=DAILY", "2026-01-01")
+	broken := quests.templates[1]
+	broken.Rule = "FREQ=NEVER"
+	quests.templates[1] = broken
+	quests.templates[2] = domain.QuestTemplate{ID: 2, UserID: 1, Title: "Планка", TaskType: domain.TypeStrength, Rule: "FREQ=DAILY", StartsOn: "2026-01-01", Active: true}
+
+	created, err := service.GenerateDue(context.Background())
+	if err == nil {
+		t.Error("broken template error was swallowed")
+	}
+	if created != 1 || len(quests.tasks) != 1 || *quests.tasks[0].TemplateID != 2 {
+		t.Errorf("created = %d, tasks = %+v; want the second template's quest", created, quests.tasks)
+	}
+}
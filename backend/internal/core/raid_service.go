@@ -1 +1,410 @@
-package core
\ No newline at end of file
+// internal/core/raid_service.go
+package core
+
+import (
+	"context"
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+	"math/rand"
+	"time"
+)
+
+// RaidConfig - правила рейдов
+type RaidConfig struct {
+	// Сколько золота стоит рейд атакующему
+	Cost int
+	// Доля золота цели, которую уносит атакующий
+	LootShare float64
+	// Добыча не меньше этого
+	MinLoot int
+	// Бонус опыта за каждый уровень цели
+	XPPerTargetLevel int
+	// Сколько целей показывать в списке
+	TargetsLimit int
+
+	// Пауза между рейдами одного атакующего
+	AttackerCooldown time.Duration
+	// Пауза перед повторным рейдом на ту же цель тем же атакующим
+	PairCooldown time.Duration
+	// Защита цели после любого рейда на нее
+	ShieldDuration time.Duration
+	// Сколько золота цель может потерять за сутки; 0 - без ограничения
+	DailyLossCap int
+	// Сколько золота у цели остается при любом рейде
+	KeepGold int
+	// На сколько рангов выше или ниже себя можно грабить
+	MaxRankGap int
+
+	// Сколько энергии теряет атакующий при поражении; стоимость рейда не возвращается
+	DefeatEnergyPenalty int
+}
+
+func DefaultRaidConfig() RaidConfig {
+	return RaidConfig{
+		Cost:             20,
+		LootShare:        0.2,
+		MinLoot:          10,
+		XPPerTargetLevel: 5,
+		TargetsLimit:     20,
+		AttackerCooldown: 30 * time.Minute,
+		PairCooldown:     24 * time.Hour,
+		ShieldDuration:   8 * time.Hour,
+		DailyLossCap:     200,
+		KeepGold:         10,
+		MaxRankGap:       1,
+
+		DefeatEnergyPenalty: 15,
+	}
+}
+
+type RaidService struct {
+	raidRepo ports.RaidRepository
+	userRepo ports.UserRepository
+	tx       ports.TxManager
+	events   ports.EventPublisher
+	armory   *Armory
+	config   RaidConfig
+	energy   domain.EnergyConfig
+	// Сид боя; сохраняется в рейде, чтобы бой можно было переиграть
+	seed func() int64
+}
+
+func NewRaidService(
+	raidRepo ports.RaidRepository,
+	userRepo ports.UserRepository,
+	tx ports.TxManager,
+	events ports.EventPublisher,
+	armory *Armory,
+	config RaidConfig,
+	energy domain.EnergyConfig,
+) *RaidService {
+	return &RaidService{
+		raidRepo: raidRepo,
+		userRepo: userRepo,
+		tx:       tx,
+		events:   events,
+		armory:   armory,
+		config:   config,
+		energy:   energy,
+		seed:     rand.Int63,
+	}
+}
+
+// RaidTarget - то, что атакующий видит о возможной цели
+type RaidTarget struct {
+	ID        int64  `json:"id"`
+	Username  string `json:"username"`
+	FirstName string `json:"first_name"`
+	Level     int    `json:"level"`
+	Rank      string `json:"rank"`
+	Gold      int    `json:"gold"`
+}
+
+type RaidResult struct {
+	Raid       *domain.Raid `json:"raid"`
+	TargetName string       `json:"target_name"`
+	TargetRank string       `json:"target_rank"`
+	LeveledUp  bool         `json:"leveled_up"`
+	NewLevel   int          `json:"new_level"`
+}
+
+// GetTargets - неактивные игроки своей лиги, которых можно ограбить прямо сейчас
+func (s *RaidService) GetTargets(ctx context.Context, attackerID int64) ([]*RaidTarget, error) {
+	attacker, err := s.userRepo.GetByID(ctx, attackerID)
+	if err != nil {
+		return nil, err
+	}
+
+	tier := attacker.RankTier()
+	minLevel, maxLevel := domain.RankLevelRange(tier-s.config.MaxRankGap, tier+s.config.MaxRankGap)
+
+	// Берем с запасом: часть игроков отсеют защита и лимиты
+	players, err := s.userRepo.GetInactivePlayers(ctx, minLevel, maxLevel, s.config.TargetsLimit*3)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(players))
+	for _, player := range players {
+		ids = append(ids, player.ID)
+	}
+
+	now := time.Now()
+	recent, err := s.raidRepo.GetByTargetsSince(ctx, ids, now.Add(-s.rulesWindow()))
+	if err != nil {
+		return nil, err
+	}
+
+	byTarget := make(map[int64][]*domain.Raid)
+	for _, raid := range recent {
+		byTarget[raid.TargetID] = append(byTarget[raid.TargetID], raid)
+	}
+
+	targets := make([]*RaidTarget, 0, s.config.TargetsLimit)
+	for _, player := range players {
+		if player.ID == attackerID || len(targets) == s.config.TargetsLimit {
+			continue
+		}
+		if err := s.checkTarget(attacker, player, byTarget[player.ID], now); err != nil {
+			continue
+		}
+		targets = append(targets, &RaidTarget{
+			ID:        player.ID,
+			Username:  player.Username,
+			FirstName: player.FirstName,
+			Level:     player.Level,
+			Rank:      player.GetRank(),
+			Gold:      player.Gold,
+		})
+	}
+
+	return targets, nil
+}
+
+// StartRaid - рейд на неактивного игрока
+func (s *RaidService) StartRaid(ctx context.Context, attackerID, targetID int64) (*RaidResult, error) {
+	if attackerID == targetID {
+		return nil, domain.ErrCannotRaidSelf
+	}
+
+	var (
+		raid             *domain.Raid
+		attacker, target *domain.User
+		leveledUp        bool
+	)
+
+	// Золото уходит у цели и приходит атакующему только вместе с записью о рейде
+	err := inTx(ctx, s.tx, func(ctx context.Context) error {
+		var err error
+		attacker, err = s.userRepo.GetByID(ctx, attackerID)
+		if err != nil {
+			return err
+		}
+
+		target, err = s.userRepo.GetByID(ctx, targetID)
+		if err != nil {
+			return err
+		}
+
+		if !target.IsInactive() {
+			return domain.ErrPlayerNotInactive
+		}
+
+		if !attacker.IsLicenseValid() {
+			return domain.ErrLicenseInactive
+		}
+
+		if attacker.Gold < s.config.Cost {
+			return domain.ErrInsufficientGold
+		}
+
+		now := time.Now()
+		if err := s.checkAttacker(ctx, attacker, now); err != nil {
+			return err
+		}
+
+		recent, err := s.raidRepo.GetByTargetsSince(ctx, []int64{target.ID}, now.Add(-s.rulesWindow()))
+		if err != nil {
+			return err
+		}
+		if err := s.checkTarget(attacker, target, recent, now); err != nil {
+			return err
+		}
+
+		attacker.DropRaidShield()
+		if err := s.armory.LoadGear(ctx, attacker, target); err != nil {
+			return err
+		}
+		battle := domain.ResolveBattle(domain.FighterOf(attacker), domain.FighterOf(target), s.seed())
+
+		var loot, bonusXP int
+		if battle.AttackerWon {
+			loot = s.loot(target, recent, now)
+			bonusXP = target.Level * s.config.XPPerTargetLevel
+		}
+
+		// Сначала сохраняем рейд: его ID попадает в журнал обоих игроков
+		raid = domain.NewRaid(attackerID, targetID, s.config.Cost)
+		raid.Finish(battle, loot, bonusXP)
+		if !battle.AttackerWon {
+			attacker.RegenerateEnergy(now, s.energy)
+			raid.EnergyLost = min(s.config.DefeatEnergyPenalty, attacker.Energy)
+		}
+		if err := s.raidRepo.Create(ctx, raid); err != nil {
+			return err
+		}
+
+		if err := attacker.Because(domain.ReasonRaidCost, raid.ID).SpendGold(s.config.Cost); err != nil {
+			return err
+		}
+
+		if !raid.Succeeded() {
+			attacker.Because(domain.ReasonRaidDefeat, raid.ID).LoseEnergy(raid.EnergyLost)
+			if err := s.userRepo.Update(ctx, attacker); err != nil {
+				return err
+			}
+			return s.publishRaid(ctx, raid, attacker, false)
+		}
+
+		target.Because(domain.ReasonRaided, raid.ID).AddGold(-loot)
+		attacker.Because(domain.ReasonRaidLoot, raid.ID).AddGold(loot)
+		leveledUp = attacker.AddXP(bonusXP)
+
+		if err := s.userRepo.Update(ctx, attacker); err != nil {
+			return err
+		}
+		if err := s.userRepo.Update(ctx, target); err != nil {
+			return err
+		}
+
+		return s.publishRaid(ctx, raid, attacker, leveledUp)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &RaidResult{
+		Raid:       raid,
+		TargetName: target.DisplayName(),
+		TargetRank: target.GetRank(),
+		LeveledUp:  leveledUp,
+		NewLevel:   attacker.Level,
+	}, nil
+}
+
+// publishRaid - события рейда для обеих сторон, в транзакции рейда
+func (s *RaidService) publishRaid(ctx context.Context, raid *domain.Raid, attacker *domain.User, leveledUp bool) error {
+	var attackerEvent, targetEvent domain.Event
+	if raid.Succeeded() {
+		attackerEvent = domain.NewEvent(domain.EventRaidWon, raid.AttackerID, raid.ID)
+		attackerEvent.Gold = raid.GoldLooted
+		attackerEvent.XP = raid.XPGained
+		targetEvent = domain.NewEvent(domain.EventRaided, raid.TargetID, raid.ID)
+		targetEvent.Gold = raid.GoldLooted
+	} else {
+		attackerEvent = domain.NewEvent(domain.EventRaidLost, raid.AttackerID, raid.ID)
+		targetEvent = domain.NewEvent(domain.EventRaidDefended, raid.TargetID, raid.ID)
+	}
+	attackerEvent.OpponentID = raid.TargetID
+	targetEvent.OpponentID = raid.AttackerID
+
+	events := []domain.Event{attackerEvent, targetEvent}
+	if leveledUp {
+		events = append(events, domain.LevelUpEvent(attacker))
+	}
+	return publish(ctx, s.events, events...)
+}
+
+// GetRaid - рейд, в котором участвовал игрок
+func (s *RaidService) GetRaid(ctx context.Context, userID, raidID int64) (*domain.Raid, error) {
+	raid, err := s.raidRepo.GetByID(ctx, raidID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !raid.Involves(userID) {
+		return nil, domain.ErrRaidNotFound
+	}
+
+	return raid, nil
+}
+
+// GetAttacks - рейды, которые провел игрок
+func (s *RaidService) GetAttacks(ctx context.Context, userID int64, limit, offset int) ([]*domain.Raid, error) {
+	return s.raidRepo.GetByAttackerID(ctx, userID, limit, offset)
+}
+
+// GetDefenses - рейды на игрока
+func (s *RaidService) GetDefenses(ctx context.Context, userID int64, limit, offset int) ([]*domain.Raid, error) {
+	return s.raidRepo.GetByTargetID(ctx, userID, limit, offset)
+}
+
+// checkAttacker - не на перезарядке ли атакующий и не в зоне наказания
+func (s *RaidService) checkAttacker(ctx context.Context, attacker *domain.User, now time.Time) error {
+	// Добыча - тоже награда, в зоне наказания ее нет
+	if attacker.InPenaltyZone() {
+		return domain.ErrInPenaltyZone
+	}
+
+	last, err := s.raidRepo.GetByAttackerID(ctx, attacker.ID, 1, 0)
+	if err != nil {
+		return err
+	}
+
+	if len(last) > 0 && now.Sub(last[0].StartedAt) < s.config.AttackerCooldown {
+		return domain.ErrRaidCooldown
+	}
+	return nil
+}
+
+// checkTarget - правила для пары атакующий-цель; recent - рейды на цель за rulesWindow
+func (s *RaidService) checkTarget(attacker, target *domain.User, recent []*domain.Raid, now time.Time) error {
+	gap := attacker.RankTier() - target.RankTier()
+	if gap > s.config.MaxRankGap || -gap > s.config.MaxRankGap {
+		return domain.ErrRankGapTooLarge
+	}
+
+	if target.HasRaidShield(now) {
+		return domain.ErrTargetShielded
+	}
+
+	for _, raid := range recent {
+		since := now.Sub(raid.StartedAt)
+		// Защиту дает только удачный рейд: отбившись, цель остается открытой для других
+		if raid.Succeeded() && since < s.config.ShieldDuration {
+			return domain.ErrTargetShielded
+		}
+		if raid.AttackerID == attacker.ID && since < s.config.PairCooldown {
+			return domain.ErrTargetRecentlyRaided
+		}
+	}
+
+	if s.config.DailyLossCap > 0 && s.lostToday(recent, now) >= s.config.DailyLossCap {
+		return domain.ErrRaidDailyCapReached
+	}
+
+	if target.Gold <= s.config.KeepGold {
+		return domain.ErrNothingToLoot
+	}
+	return nil
+}
+
+// loot - сколько золота унесет атакующий с учетом суточного лимита и неприкосновенного остатка
+func (s *RaidService) loot(target *domain.User, recent []*domain.Raid, now time.Time) int {
+	loot := int(float64(target.Gold) * s.config.LootShare)
+	if loot < s.config.MinLoot {
+		loot = s.config.MinLoot
+	}
+
+	if s.config.DailyLossCap > 0 {
+		if left := s.config.DailyLossCap - s.lostToday(recent, now); loot > left {
+			loot = left
+		}
+	}
+	if left := target.Gold - s.config.KeepGold; loot > left {
+		loot = left
+	}
+	return loot
+}
+
+// lostToday - сколько золота цель потеряла в рейдах за последние сутки
+func (s *RaidService) lostToday(recent []*domain.Raid, now time.Time) int {
+	lost := 0
+	for _, raid := range recent {
+		if now.Sub(raid.StartedAt) < 24*time.Hour {
+			lost += raid.GoldLooted
+		}
+	}
+	return lost
+}
+
+// rulesWindow - за какой срок нужны рейды на цель, чтобы проверить все правила
+func (s *RaidService) rulesWindow() time.Duration {
+	window := 24 * time.Hour
+	for _, d := range []time.Duration{s.config.PairCooldown, s.config.ShieldDuration} {
+		if d > window {
+			window = d
+		}
+	}
+	return window
+}
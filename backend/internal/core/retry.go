@@ -0,0 +1,45 @@
+// internal/core/retry.go
+package core
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+)
+
+// Сколько раз пробовать операцию, если запись изменили параллельно
+const maxConflictAttempts = 10
+
+// retryConflicts - повторяет fn при domain.ErrConcurrentModification.
+// fn должна заново читать данные на каждой попытке
+func retryConflicts(ctx context.Context, fn func() error) error {
+	var err error
+	for attempt := 0; attempt < maxConflictAttempts; attempt++ {
+		if attempt > 0 {
+			// Небольшая случайная пауза, чтобы соперники не столкнулись снова
+			delay := time.Duration(rand.Int63n(int64(attempt) * int64(5*time.Millisecond)))
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+
+		err = fn()
+		if !errors.Is(err, domain.ErrConcurrentModification) {
+			return err
+		}
+	}
+	return err
+}
+
+// inTx - fn в транзакции, целиком повторяемой при конфликте версий
+func inTx(ctx context.Context, tx ports.TxManager, fn func(ctx context.Context) error) error {
+	return retryConflicts(ctx, func() error {
+		return tx.WithinTx(ctx, fn)
+	})
+}
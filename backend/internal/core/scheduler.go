@@ -0,0 +1,184 @@
+// internal/core/scheduler.go
+package core
+
+import (
+	"context"
+	"dojo/internal/ports"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Job - фоновая задача по расписанию
+type Job struct {
+	Name string
+	// Расписание cron ("*/5 * * * *") или "@every 1m"
+	Spec string
+	// Run возвращает, сколько записей обработано
+	Run func(ctx context.Context) (int, error)
+}
+
+// JobStats - метрики запусков задачи на этой реплике
+type JobStats struct {
+	Name string `json:"name"`
+	Spec string `json:"spec"`
+	// Запуски, выполненные здесь; Skipped - пропущенные, потому что лидер другая реплика
+	Runs     int64 `json:"runs"`
+	Failures int64 `json:"failures"`
+	Skipped  int64 `json:"skipped"`
+	// Всего обработано записей за все запуски
+	Processed int64 `json:"processed"`
+
+	LastStartedAt *time.Time    `json:"last_started_at,omitempty"`
+	LastDuration  time.Duration `json:"last_duration_ns"`
+	LastProcessed int           `json:"last_processed"`
+	LastError     string        `json:"last_error,omitempty"`
+	NextRunAt     *time.Time    `json:"next_run_at,omitempty"`
+}
+
+// batchErrors - ошибки отдельных записей в задаче. Одна сбойная запись не
+// должна останавливать остальные: ошибка пишется в лог, а задача в конце
+// сообщает планировщику, сколько записей не обработано
+type batchErrors struct {
+	job    string
+	failed int
+	last   error
+}
+
+func (b *batchErrors) add(id int64, err error) {
+	log.Printf("Задача %s: запись %d: %v", b.job, id, err)
+	b.failed++
+	b.last = err
+}
+
+// err - nil, если все записи обработаны
+func (b *batchErrors) err() error {
+	if b.failed == 0 {
+		return nil
+	}
+	return fmt.Errorf("не обработано записей: %d, последняя ошибка: %w", b.failed, b.last)
+}
+
+// Scheduler - запускает задачи по расписанию, только пока эта реплика лидер
+type Scheduler struct {
+	cron   *cron.Cron
+	leader ports.LeaderElector
+
+	mu    sync.Mutex
+	stats map[string]*JobStats
+	ids   map[string]cron.EntryID
+}
+
+func NewScheduler(leader ports.LeaderElector) *Scheduler {
+	return &Scheduler{
+		// Запуск, наложившийся на еще идущий, пропускаем
+		cron:   cron.New(cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger))),
+		leader: leader,
+		stats:  make(map[string]*JobStats),
+		ids:    make(map[string]cron.EntryID),
+	}
+}
+
+// Add - регистрирует задачу; вызывать до Run
+func (s *Scheduler) Add(ctx context.Context, job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.stats[job.Name]; ok {
+		return fmt.Errorf("задача %s уже добавлена", job.Name)
+	}
+
+	id, err := s.cron.AddFunc(job.Spec, func() { s.run(ctx, job) })
+	if err != nil {
+		return fmt.Errorf("расписание задачи %s: %w", job.Name, err)
+	}
+
+	s.stats[job.Name] = &JobStats{Name: job.Name, Spec: job.Spec}
+	s.ids[job.Name] = id
+	return nil
+}
+
+// Run - работает до отмены ctx, затем дожидается идущих задач и отдает лидерство
+func (s *Scheduler) Run(ctx context.Context) {
+	s.cron.Start()
+	<-ctx.Done()
+	<-s.cron.Stop().Done()
+
+	if err := s.leader.Resign(context.Background()); err != nil {
+		log.Println("Ошибка снятия лидерства:", err)
+	}
+}
+
+// Stats - метрики всех задач по имени
+func (s *Scheduler) Stats() []JobStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]JobStats, 0, len(s.stats))
+	for name, stats := range s.stats {
+		item := *stats
+		if next := s.cron.Entry(s.ids[name]).Next; !next.IsZero() {
+			item.NextRunAt = &next
+		}
+		result = append(result, item)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+
+	return result
+}
+
+func (s *Scheduler) run(ctx context.Context, job Job) {
+	leader, err := s.leader.TryLead(ctx)
+	if err != nil || !leader {
+		if err != nil {
+			log.Printf("Задача %s: не удалось проверить лидерство: %v", job.Name, err)
+		}
+		s.record(job.Name, func(stats *JobStats) { stats.Skipped++ })
+		return
+	}
+
+	started := time.Now()
+	processed, err := s.safeRun(ctx, job)
+	duration := time.Since(started)
+
+	s.record(job.Name, func(stats *JobStats) {
+		stats.Runs++
+		stats.Processed += int64(processed)
+		stats.LastStartedAt = &started
+		stats.LastDuration = duration
+		stats.LastProcessed = processed
+		stats.LastError = ""
+		if err != nil {
+			stats.Failures++
+			stats.LastError = err.Error()
+		}
+	})
+
+	if err != nil {
+		log.Printf("Задача %s: ошибка после %d записей за %s: %v", job.Name, processed, duration, err)
+		return
+	}
+	if processed > 0 {
+		log.Printf("Задача %s: обработано %d за %s", job.Name, processed, duration)
+	}
+}
+
+// safeRun - паника в задаче не должна ронять весь процесс
+func (s *Scheduler) safeRun(ctx context.Context, job Job) (processed int, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("паника: %v", r)
+		}
+	}()
+	return job.Run(ctx)
+}
+
+func (s *Scheduler) record(name string, update func(stats *JobStats)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	update(s.stats[name])
+}
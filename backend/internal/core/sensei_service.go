@@ -0,0 +1,422 @@
+// internal/core/sensei_service.go
+package core
+
+import (
+	"context"
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+)
+
+// SenseiConfig - бюджет промпта диалога с Сенсеем
+type SenseiConfig struct {
+	// Примерный лимит токенов на содержание, историю и вопрос
+	TokenBudget int
+	// Сколько последних реплик всегда идут в промпт целиком
+	KeepRecent int
+	// Сколько последних заданий показывать Сенсею
+	RecentTasks int
+}
+
+func DefaultSenseiConfig() SenseiConfig {
+	return SenseiConfig{
+		TokenBudget: 3000,
+		KeepRecent:  6,
+		RecentTasks: 5,
+	}
+}
+
+type SenseiService struct {
+	userRepo   ports.UserRepository
+	taskRepo   ports.TaskRepository
+	senseiRepo ports.SenseiRepository
+	aiService  ports.AIService
+	config     SenseiConfig
+}
+
+func NewSenseiService(
+	userRepo ports.UserRepository,
+	taskRepo ports.TaskRepository,
+	senseiRepo ports.SenseiRepository,
+	aiService ports.AIService,
+	config SenseiConfig,
+) *SenseiService {
+	return &SenseiService{
+		userRepo:   userRepo,
+		taskRepo:   taskRepo,
+		senseiRepo: senseiRepo,
+		aiService:  aiService,
+		config:     config,
+	}
+}
+
+// ListConversations - диалоги игрока, свежие первыми
+func (s *SenseiService) ListConversations(ctx context.Context, userID int64, limit, offset int) ([]*domain.SenseiConversation, error) {
+	return s.senseiRepo.ListConversations(ctx, userID, limit, offset)
+}
+
+// GetConversation - диалог со всеми репликами
+func (s *SenseiService) GetConversation(ctx context.Context, userID, conversationID int64) (*domain.SenseiConversation, []*domain.SenseiMessage, error) {
+	conversation, err := s.ownConversation(ctx, userID, conversationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	messages, err := s.senseiRepo.GetMessages(ctx, conversation.ID, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return conversation, messages, nil
+}
+
+// DeleteConversation - удалить диалог
+func (s *SenseiService) DeleteConversation(ctx context.Context, userID, conversationID int64) error {
+	conversation, err := s.ownConversation(ctx, userID, conversationID)
+	if err != nil {
+		return err
+	}
+	return s.senseiRepo.DeleteConversation(ctx, conversation.ID)
+}
+
+// ClearConversations - удалить все диалоги игрока
+func (s *SenseiService) ClearConversations(ctx context.Context, userID int64) error {
+	return s.senseiRepo.DeleteAllByUserID(ctx, userID)
+}
+
+// Chat - вопрос Сенсею. conversationID = 0 начинает новый диалог
+func (s *SenseiService) Chat(ctx context.Context, userID, conversationID int64, message string) (*SenseiReply, error) {
+	turn, err := s.begin(ctx, userID, conversationID, message)
+	if err != nil {
+		return nil, err
+	}
+	return s.answer(ctx, turn)
+}
+
+// ChatLatest - продолжить последний диалог (или начать первый), для бота
+func (s *SenseiService) ChatLatest(ctx context.Context, userID int64, message string) (*SenseiReply, error) {
+	conversation, err := s.senseiRepo.GetLatestConversation(ctx, userID)
+	if err != nil && err != domain.ErrConversationNotFound {
+		return nil, err
+	}
+
+	var conversationID int64
+	if conversation != nil {
+		conversationID = conversation.ID
+	}
+
+	turn, err := s.begin(ctx, userID, conversationID, message)
+	if err != nil {
+		return nil, err
+	}
+	return s.answer(ctx, turn)
+}
+
+// ChatStream - вопрос Сенсею с потоковым ответом. Запрос списывается сразу,
+// ответ читается через SenseiStream.Relay
+func (s *SenseiService) ChatStream(ctx context.Context, userID, conversationID int64, message string) (*SenseiStream, error) {
+	turn, err := s.begin(ctx, userID, conversationID, message)
+	if err != nil {
+		return nil, err
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	deltas, err := s.aiService.ChatStream(streamCtx, userID, turn.message, turn.history)
+	if err != nil {
+		cancel()
+		s.refund(ctx, userID)
+		return nil, err
+	}
+
+	return &SenseiStream{
+		service: s,
+		turn:    turn,
+		deltas:  deltas,
+		cancel:  cancel,
+	}, nil
+}
+
+// senseiTurn - вопрос, за который уже списан запрос
+type senseiTurn struct {
+	user         *domain.User
+	conversation *domain.SenseiConversation
+	message      string
+	history      []ports.ChatMessage
+}
+
+// begin - проверяет вопрос, списывает запрос и собирает историю для модели
+func (s *SenseiService) begin(ctx context.Context, userID, conversationID int64, message string) (*senseiTurn, error) {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return nil, domain.ErrEmptyMessage
+	}
+
+	if s.aiService == nil {
+		return nil, domain.ErrAIServiceUnavailable
+	}
+
+	// Новый диалог сохраняем только после ответа, чтобы не плодить пустые
+	conversation := domain.NewSenseiConversation(userID, message)
+	if conversationID != 0 {
+		var err error
+		conversation, err = s.ownConversation(ctx, userID, conversationID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var user *domain.User
+	err := retryConflicts(ctx, func() error {
+		var err error
+		user, err = s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		if err := user.Because(domain.ReasonSenseiRequest, conversation.ID).UseSenseiRequest(); err != nil {
+			return err
+		}
+		return s.userRepo.Update(ctx, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.buildHistory(ctx, user, conversation, message)
+	if err != nil {
+		s.refund(ctx, userID)
+		return nil, err
+	}
+
+	return &senseiTurn{
+		user:         user,
+		conversation: conversation,
+		message:      message,
+		history:      history,
+	}, nil
+}
+
+// answer - обычный (не потоковый) ответ на вопрос
+func (s *SenseiService) answer(ctx context.Context, turn *senseiTurn) (*SenseiReply, error) {
+	answer, err := s.aiService.Chat(ctx, turn.user.ID, turn.message, turn.history)
+	if err != nil {
+		s.refund(ctx, turn.user.ID)
+		return nil, err
+	}
+
+	return s.finish(ctx, turn, answer)
+}
+
+// refund - возвращает запрос, если Сенсей так и не ответил
+func (s *SenseiService) refund(ctx context.Context, userID int64) {
+	// Перечитываем игрока: пока ждали модель, его могли изменить
+	err := retryConflicts(ctx, func() error {
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		user.Because(domain.ReasonSenseiRefund, 0).RefundSenseiRequest()
+		return s.userRepo.Update(ctx, user)
+	})
+	if err != nil {
+		log.Println("Не удалось вернуть запрос к Сенсею:", err)
+	}
+}
+
+// finish - сохраняет вопрос и ответ в диалоге
+func (s *SenseiService) finish(ctx context.Context, turn *senseiTurn, answer string) (*SenseiReply, error) {
+	conversation := turn.conversation
+	if conversation.ID == 0 {
+		if err := s.senseiRepo.CreateConversation(ctx, conversation); err != nil {
+			return nil, err
+		}
+	}
+
+	question := &domain.SenseiMessage{
+		ConversationID: conversation.ID,
+		Role:           domain.SenseiRoleUser,
+		Content:        turn.message,
+	}
+	if err := s.senseiRepo.AddMessage(ctx, question); err != nil {
+		return nil, err
+	}
+
+	reply := &domain.SenseiMessage{
+		ConversationID: conversation.ID,
+		Role:           domain.SenseiRoleAssistant,
+		Content:        answer,
+	}
+	if err := s.senseiRepo.AddMessage(ctx, reply); err != nil {
+		return nil, err
+	}
+
+	conversation.MessageCount += 2
+	if err := s.senseiRepo.UpdateConversation(ctx, conversation); err != nil {
+		return nil, err
+	}
+
+	return &SenseiReply{
+		Conversation: conversation,
+		Message:      reply,
+		RequestsLeft: turn.user.SenseiRequests,
+	}, nil
+}
+
+// buildHistory - контекст игрока, содержание старых реплик и свежие реплики в пределах бюджета.
+// Если бюджет превышен, старые реплики сжимаются через ИИ и больше не передаются целиком
+func (s *SenseiService) buildHistory(ctx context.Context, user *domain.User, conversation *domain.SenseiConversation, message string) ([]ports.ChatMessage, error) {
+	playerContext, err := s.playerContext(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	var stored []*domain.SenseiMessage
+	if conversation.ID != 0 {
+		stored, err = s.senseiRepo.GetMessages(ctx, conversation.ID, conversation.SummarizedUntilID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	recent := make([]ports.ChatMessage, 0, len(stored))
+	for _, m := range stored {
+		recent = append(recent, ports.ChatMessage{Role: m.Role, Content: m.Content})
+	}
+
+	used := estimateTokens(playerContext) + estimateTokens(conversation.Summary) + estimateTokens(message)
+	for _, m := range recent {
+		used += estimateTokens(m.Content)
+	}
+
+	if used > s.config.TokenBudget && len(recent) > s.config.KeepRecent {
+		cut := len(recent) - s.config.KeepRecent
+
+		summary, err := s.aiService.Summarize(ctx, conversation.Summary, recent[:cut])
+		if err != nil {
+			// Без содержания просто отбрасываем старые реплики из промпта
+			log.Println("Не удалось сжать историю Сенсея:", err)
+		} else {
+			conversation.Summary = summary
+			conversation.SummarizedUntilID = stored[cut-1].ID
+		}
+		recent = recent[cut:]
+	}
+
+	history := []ports.ChatMessage{{Role: ports.RoleSystem, Content: playerContext}}
+	if conversation.Summary != "" {
+		history = append(history, ports.ChatMessage{
+			Role:    ports.RoleSystem,
+			Content: "Краткое содержание ранней части диалога:\n" + conversation.Summary,
+		})
+	}
+
+	return append(history, recent...), nil
+}
+
+// playerContext - что Сенсей знает об игроке
+func (s *SenseiService) playerContext(ctx context.Context, user *domain.User) (string, error) {
+	tasks, err := s.taskRepo.GetRecentByUserID(ctx, user.ID, s.config.RecentTasks)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Игрок: %s, уровень %d (ранг %s), XP %d/%d, золото %d, энергия %d/%d.\n",
+		user.FirstName, user.Level, user.GetRank(), user.XP, user.CalculateXPToNextLevel(),
+		user.Gold, user.Energy, user.MaxEnergy)
+	fmt.Fprintf(&sb, "Характеристики: Сила %d, Ловкость %d, Интеллект %d, Проницательность %d.\n",
+		user.Strength, user.Agility, user.Intelligence, user.Insight)
+
+	if !user.IsLicenseValid() {
+		sb.WriteString("Лицензия охотника неактивна.\n")
+	}
+
+	if len(tasks) > 0 {
+		sb.WriteString("Последние задания:\n")
+		for _, t := range tasks {
+			fmt.Fprintf(&sb, "- %s (%s, %s)\n", t.Title, t.TaskType, t.Status)
+		}
+	}
+
+	return sb.String(), nil
+}
+
+func (s *SenseiService) ownConversation(ctx context.Context, userID, conversationID int64) (*domain.SenseiConversation, error) {
+	conversation, err := s.senseiRepo.GetConversation(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Чужой диалог не показываем даже как "чужой"
+	if !conversation.BelongsTo(userID) {
+		return nil, domain.ErrConversationNotFound
+	}
+	return conversation, nil
+}
+
+// estimateTokens - грубая оценка: ~3 символа на токен (кириллица дробится сильнее латиницы)
+func estimateTokens(text string) int {
+	if text == "" {
+		return 0
+	}
+	return utf8.RuneCountInString(text)/3 + 4
+}
+
+type SenseiReply struct {
+	Conversation *domain.SenseiConversation `json:"conversation"`
+	Message      *domain.SenseiMessage      `json:"message"`
+	RequestsLeft int                        `json:"requests_left"`
+}
+
+// SenseiStream - открытый потоковый ответ Сенсея
+type SenseiStream struct {
+	service *SenseiService
+	turn    *senseiTurn
+	deltas  <-chan ports.ChatDelta
+	cancel  context.CancelFunc
+}
+
+// Relay - передает фрагменты ответа в write, пока поток не кончится.
+// Если до клиента не дошло ни одного фрагмента, запрос возвращается игроку.
+// Оборванный на середине ответ сохраняется как есть, запрос при этом не возвращается
+func (st *SenseiStream) Relay(ctx context.Context, write func(delta string) error) (*SenseiReply, error) {
+	var (
+		answer    strings.Builder
+		streamErr error
+	)
+	for delta := range st.deltas {
+		if delta.Err != nil {
+			streamErr = delta.Err
+			break
+		}
+		if err := write(delta.Text); err != nil {
+			streamErr = err
+			break
+		}
+		answer.WriteString(delta.Text)
+	}
+	// Останавливаем модель, если клиент ушел раньше конца
+	st.cancel()
+
+	text := strings.TrimSpace(answer.String())
+	if text == "" {
+		if streamErr == nil {
+			streamErr = domain.ErrAIServiceUnavailable
+		}
+		st.service.refund(ctx, st.turn.user.ID)
+		return nil, streamErr
+	}
+
+	reply, err := st.service.finish(ctx, st.turn, text)
+	if err != nil {
+		return nil, err
+	}
+	if streamErr != nil {
+		return nil, streamErr
+	}
+	return reply, nil
+}
@@ -0,0 +1,449 @@
+// internal/core/shop_service.go
+package core
+
+import (
+	"context"
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"time"
+)
+
+// ShopConfig - ограничения инвентаря и добыча
+type ShopConfig struct {
+	// Сколько одинаковых расходников можно хранить
+	MaxStack int
+	// Шанс, что из источника выпадет предмет
+	DropChances map[domain.DropSource]float64
+}
+
+func DefaultShopConfig() ShopConfig {
+	return ShopConfig{
+		MaxStack: 99,
+		DropChances: map[domain.DropSource]float64{
+			domain.DropUrgent: 0.3,
+			domain.DropRaid:   0.25,
+			domain.DropBoss:   0.5,
+		},
+	}
+}
+
+// ShopService - магазин за золото, инвентарь и снаряжение игрока. Подписчик
+// шины: выдает добычу за срочные вызовы, задания-боссы и выигранные рейды
+type ShopService struct {
+	inventoryRepo ports.InventoryRepository
+	userRepo      ports.UserRepository
+	tx            ports.TxManager
+	events        ports.EventPublisher
+	catalog       []*domain.Item
+	forSale       []*domain.Item
+	byID          map[string]*domain.Item
+	armory        *Armory
+	config        ShopConfig
+	energy        domain.EnergyConfig
+	streaks       domain.StreakRules
+}
+
+func NewShopService(
+	inventoryRepo ports.InventoryRepository,
+	userRepo ports.UserRepository,
+	tx ports.TxManager,
+	events ports.EventPublisher,
+	catalog []*domain.Item,
+	config ShopConfig,
+	energy domain.EnergyConfig,
+	streaks domain.StreakRules,
+) *ShopService {
+	byID := make(map[string]*domain.Item, len(catalog))
+	var forSale []*domain.Item
+	for _, item := range catalog {
+		byID[item.ID] = item
+		if item.ForSale() {
+			forSale = append(forSale, item)
+		}
+	}
+
+	return &ShopService{
+		inventoryRepo: inventoryRepo,
+		userRepo:      userRepo,
+		tx:            tx,
+		events:        events,
+		catalog:       catalog,
+		forSale:       forSale,
+		byID:          byID,
+		armory:        NewArmory(inventoryRepo, catalog),
+		config:        config,
+		energy:        energy,
+		streaks:       streaks,
+	}
+}
+
+// InventoryResult - игрок и ячейка инвентаря после покупки или использования
+type InventoryResult struct {
+	User *domain.User          `json:"user"`
+	Item *domain.InventoryItem `json:"item"`
+}
+
+// EquipmentView - надетое снаряжение по слотам и характеристики с ним и без него
+type EquipmentView struct {
+	Slots     map[domain.EquipmentSlot]*domain.InventoryItem `json:"slots"`
+	Base      domain.Stats                                   `json:"base"`
+	Gear      domain.Stats                                   `json:"gear"`
+	Effective domain.Stats                                   `json:"effective"`
+}
+
+// GetCatalog - товары, которые продаются в магазине
+func (s *ShopService) GetCatalog() []*domain.Item {
+	return s.forSale
+}
+
+// GetInventory - предметы игрока. Предметы, убранные из каталога, не показываются
+func (s *ShopService) GetInventory(ctx context.Context, userID int64) ([]*domain.InventoryItem, error) {
+	inventory, err := s.inventoryRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.armory.attach(inventory), nil
+}
+
+// Buy - покупка quantity предметов за золото
+func (s *ShopService) Buy(ctx context.Context, userID int64, itemID string, quantity int) (*InventoryResult, error) {
+	item, ok := s.byID[itemID]
+	if !ok {
+		return nil, domain.ErrItemNotFound
+	}
+	if !item.ForSale() {
+		return nil, domain.ErrItemNotForSale
+	}
+	if quantity < 1 || quantity > s.config.MaxStack || (item.Kind == domain.ItemEquipment && quantity != 1) {
+		return nil, domain.ErrInvalidQuantity
+	}
+
+	var result *InventoryResult
+	err := inTx(ctx, s.tx, func(ctx context.Context) error {
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		slot, err := s.inventoryRepo.Get(ctx, userID, itemID)
+		isNew := err == domain.ErrItemNotOwned
+		switch {
+		case isNew:
+			slot = domain.NewInventoryItem(userID, item)
+		case err != nil:
+			return err
+		}
+
+		if item.Kind == domain.ItemEquipment && slot.Quantity > 0 {
+			return domain.ErrItemAlreadyOwned
+		}
+		if slot.Quantity+quantity > s.config.MaxStack {
+			return domain.ErrInvalidQuantity
+		}
+		slot.Quantity += quantity
+
+		// Ячейка сохраняется первой: ее ID попадает в журнал
+		if isNew {
+			created, err := s.inventoryRepo.Create(ctx, slot)
+			if err != nil {
+				return err
+			}
+			if !created {
+				return domain.ErrConcurrentModification
+			}
+		} else if err := s.inventoryRepo.Update(ctx, slot); err != nil {
+			return err
+		}
+
+		cost := item.Price * quantity
+		if err := user.Because(domain.ReasonShopPurchase, slot.ID).SpendGold(cost); err != nil {
+			return err
+		}
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+
+		purchased := domain.NewEvent(domain.EventItemPurchased, userID, slot.ID)
+		purchased.Item = item.ID
+		purchased.Gold = cost
+		if err := publish(ctx, s.events, purchased); err != nil {
+			return err
+		}
+
+		slot.Item = item
+		result = &InventoryResult{User: user, Item: slot}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Use - использует один расходник из инвентаря
+func (s *ShopService) Use(ctx context.Context, userID int64, itemID string) (*InventoryResult, error) {
+	item, ok := s.byID[itemID]
+	if !ok {
+		return nil, domain.ErrItemNotFound
+	}
+	if item.Kind != domain.ItemConsumable {
+		return nil, domain.ErrItemNotUsable
+	}
+
+	var result *InventoryResult
+	err := inTx(ctx, s.tx, func(ctx context.Context) error {
+		slot, err := s.inventoryRepo.Get(ctx, userID, itemID)
+		if err != nil {
+			return err
+		}
+		if err := slot.Take(); err != nil {
+			return err
+		}
+
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if err := user.Because(domain.ReasonItemUsed, slot.ID).UseItem(item, time.Now(), s.energy, s.streaks); err != nil {
+			return err
+		}
+
+		if err := s.inventoryRepo.Update(ctx, slot); err != nil {
+			return err
+		}
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+
+		used := domain.NewEvent(domain.EventItemUsed, userID, slot.ID)
+		used.Item = item.ID
+		if err := publish(ctx, s.events, used); err != nil {
+			return err
+		}
+
+		slot.Item = item
+		result = &InventoryResult{User: user, Item: slot}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Equip - надеть предмет снаряжения
+func (s *ShopService) Equip(ctx context.Context, userID int64, itemID string) (*InventoryResult, error) {
+	return s.setEquipped(ctx, userID, itemID, true)
+}
+
+// Unequip - снять предмет снаряжения
+func (s *ShopService) Unequip(ctx context.Context, userID int64, itemID string) (*InventoryResult, error) {
+	return s.setEquipped(ctx, userID, itemID, false)
+}
+
+// setEquipped - надевает или снимает предмет и пересчитывает прибавку снаряжения.
+// Предмет, занимавший тот же слот, снимается. Игрок сохраняется всегда, так что
+// параллельные запросы не наденут два предмета в один слот
+func (s *ShopService) setEquipped(ctx context.Context, userID int64, itemID string, equipped bool) (*InventoryResult, error) {
+	item, ok := s.byID[itemID]
+	if !ok {
+		return nil, domain.ErrItemNotFound
+	}
+	if item.Kind != domain.ItemEquipment {
+		return nil, domain.ErrNotEquipment
+	}
+
+	var result *InventoryResult
+	err := inTx(ctx, s.tx, func(ctx context.Context) error {
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		inventory, err := s.inventoryRepo.GetByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		inventory = s.armory.attach(inventory)
+
+		var slot *domain.InventoryItem
+		for _, candidate := range inventory {
+			if candidate.ItemID == itemID {
+				slot = candidate
+			}
+		}
+		if slot == nil {
+			return domain.ErrItemNotOwned
+		}
+
+		for _, candidate := range inventory {
+			wear := candidate.Equipped
+			switch {
+			case candidate == slot:
+				wear = equipped
+			case equipped && candidate.Item.Slot == item.Slot:
+				wear = false
+			}
+			if candidate.Equipped == wear {
+				continue
+			}
+			candidate.Equipped = wear
+			if err := s.inventoryRepo.Update(ctx, candidate); err != nil {
+				return err
+			}
+		}
+
+		// Сохранение игрока поднимает его версию: одновременные смены снаряжения
+		// в разных ячейках не наденут два предмета в один слот
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		user.Gear = domain.GearOf(inventory)
+
+		result = &InventoryResult{User: user, Item: slot}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetEquipment - что надето в каждом слоте
+func (s *ShopService) GetEquipment(ctx context.Context, userID int64) (*EquipmentView, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	inventory, err := s.GetInventory(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Gear = domain.GearOf(inventory)
+	view := &EquipmentView{
+		Slots:     make(map[domain.EquipmentSlot]*domain.InventoryItem, len(domain.EquipmentSlots)),
+		Base:      user.BaseStats(),
+		Gear:      user.Gear,
+		Effective: user.EffectiveStats(),
+	}
+	for _, slot := range inventory {
+		if slot.Equipped {
+			view.Slots[slot.Item.Slot] = slot
+		}
+	}
+	return view, nil
+}
+
+// LootEventTypes - события, за которые выпадает добыча
+func (s *ShopService) LootEventTypes() []domain.EventType {
+	return []domain.EventType{domain.EventTaskCompleted, domain.EventRaidWon}
+}
+
+// HandleEvent - подписчик шины: добыча за срочный вызов, задание-босс и
+// выигранный рейд. Срочный босс дает два броска
+func (s *ShopService) HandleEvent(ctx context.Context, event domain.Event) error {
+	var sources []domain.DropSource
+	switch event.Type {
+	case domain.EventTaskCompleted:
+		if event.Urgent {
+			sources = append(sources, domain.DropUrgent)
+		}
+		// Свои задания игрока боссами не бывают; проверка и здесь, потому что
+		// события, записанные до этого правила, еще могут ждать в outbox
+		if event.Boss && event.Frequency != domain.FrequencyCustom {
+			sources = append(sources, domain.DropBoss)
+		}
+	case domain.EventRaidWon:
+		sources = append(sources, domain.DropRaid)
+	}
+
+	for _, source := range sources {
+		if _, err := s.Drop(ctx, event.UserID, source, event.RefID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Drop - бросок добычи за победу refID из source; точка входа и для источников
+// вне шины. Бросок зависит только от игрока, источника и refID, поэтому повтор
+// того же события ничего нового не даст. nil - ничего не выпало
+func (s *ShopService) Drop(ctx context.Context, userID int64, source domain.DropSource, refID int64) (*domain.InventoryItem, error) {
+	rng := rand.New(rand.NewSource(dropSeed(userID, source, refID)))
+	item := domain.RollDrop(s.catalog, source, s.config.DropChances[source], rng)
+	if item == nil {
+		return nil, nil
+	}
+
+	var (
+		slot    *domain.InventoryItem
+		salvage int
+	)
+	err := inTx(ctx, s.tx, func(ctx context.Context) error {
+		var err error
+		slot, err = s.inventoryRepo.Get(ctx, userID, item.ID)
+		isNew := err == domain.ErrItemNotOwned
+		switch {
+		case isNew:
+			slot = domain.NewInventoryItem(userID, item)
+		case err != nil:
+			return err
+		}
+
+		salvage = slot.Loot(item, s.config.MaxStack)
+
+		if isNew {
+			created, err := s.inventoryRepo.Create(ctx, slot)
+			if err != nil {
+				return err
+			}
+			if !created {
+				return domain.ErrConcurrentModification
+			}
+		} else if salvage == 0 {
+			if err := s.inventoryRepo.Update(ctx, slot); err != nil {
+				return err
+			}
+		}
+
+		if salvage > 0 {
+			user, err := s.userRepo.GetByID(ctx, userID)
+			if err != nil {
+				return err
+			}
+			user.Because(domain.ReasonItemSalvage, slot.ID).AddGold(salvage)
+			if err := s.userRepo.Update(ctx, user); err != nil {
+				return err
+			}
+		}
+
+		dropped := domain.NewEvent(domain.EventItemDropped, userID, slot.ID)
+		dropped.Item = item.ID
+		dropped.Rarity = item.Rarity
+		dropped.DropSource = source
+		dropped.Gold = salvage
+		return publish(ctx, s.events, dropped)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slot.Item = item
+	return slot, nil
+}
+
+// dropSeed - зерно броска добычи для одной победы
+func dropSeed(userID int64, source domain.DropSource, refID int64) int64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d:%s:%d", userID, source, refID)
+	return int64(h.Sum64())
+}
@@ -5,23 +5,42 @@ import (
 	"context"
 	"dojo/internal/domain"
 	"dojo/internal/ports"
+	"time"
 )
 
 type TaskService struct {
-	taskRepo  ports.TaskRepository
-	userRepo  ports.UserRepository
-	aiService ports.AIService
+	taskRepo     ports.TaskRepository
+	userRepo     ports.UserRepository
+	templateRepo ports.QuestTemplateRepository
+	tx           ports.TxManager
+	aiService    ports.AIService
+	events       ports.EventPublisher
+	armory       *Armory
+	energy       domain.EnergyConfig
+	streaks      domain.StreakRules
 }
 
 func NewTaskService(
 	taskRepo ports.TaskRepository,
 	userRepo ports.UserRepository,
+	templateRepo ports.QuestTemplateRepository,
+	tx ports.TxManager,
 	aiService ports.AIService,
+	events ports.EventPublisher,
+	armory *Armory,
+	energy domain.EnergyConfig,
+	streaks domain.StreakRules,
 ) *TaskService {
 	return &TaskService{
-		taskRepo:  taskRepo,
-		userRepo:  userRepo,
-		aiService: aiService,
+		taskRepo:     taskRepo,
+		userRepo:     userRepo,
+		templateRepo: templateRepo,
+		tx:           tx,
+		aiService:    aiService,
+		events:       events,
+		armory:       armory,
+		energy:       energy,
+		streaks:      streaks,
 	}
 }
 
@@ -33,19 +52,9 @@ func (s *TaskService) GetActiveTasks(ctx context.Context, userID int64) ([]*doma
 
 // CreateCustomTask - создать пользовательское задание
 func (s *TaskService) CreateCustomTask(ctx context.Context, userID int64, title, description string, taskType domain.TaskType) (*domain.Task, error) {
-	user, err := s.userRepo.GetByID(ctx, userID)
-	if err != nil {
-		return nil, err
-	}
-	
 	task := domain.NewCustomTask(userID, title, description, taskType)
 	
-	// Списываем золото за создание
-	if err := user.SpendGold(task.GoldCost); err != nil {
-		return nil, err
-	}
-	
-	// Анализируем через ИИ
+	// Анализируем через ИИ до транзакции, чтобы не держать ее открытой
 	if s.aiService != nil {
 		analysis, err := s.aiService.AnalyzeTask(ctx, title, description)
 		if err == nil {
@@ -62,11 +71,24 @@ func (s *TaskService) CreateCustomTask(ctx context.Context, userID int64, title,
 		task.CalculateRewards()
 	}
 	
-	if err := s.taskRepo.Create(ctx, task); err != nil {
-		return nil, err
-	}
-	
-	if err := s.userRepo.Update(ctx, user); err != nil {
+	err := inTx(ctx, s.tx, func(ctx context.Context) error {
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		
+		if err := s.taskRepo.Create(ctx, task); err != nil {
+			return err
+		}
+		
+		// Списываем золото за создание; ID задания нужен для журнала
+		if err := user.Because(domain.ReasonTaskCreated, task.ID).SpendGold(task.GoldCost); err != nil {
+			return err
+		}
+		
+		return s.userRepo.Update(ctx, user)
+	})
+	if err != nil {
 		return nil, err
 	}
 	
@@ -75,111 +97,202 @@ func (s *TaskService) CreateCustomTask(ctx context.Context, userID int64, title,
 
 // StartTask - начать задание
 func (s *TaskService) StartTask(ctx context.Context, taskID, userID int64) error {
-	task, err := s.taskRepo.GetByID(ctx, taskID)
-	if err != nil {
-		return err
-	}
-	
-	if task.UserID != userID {
-		return domain.ErrUnauthorized
-	}
-	
-	user, err := s.userRepo.GetByID(ctx, userID)
-	if err != nil {
-		return err
-	}
-	
-	if err := user.SpendEnergy(task.EnergyCost); err != nil {
-		return err
-	}
-	
-	if err := task.Start(); err != nil {
-		return err
-	}
-	
-	if err := s.taskRepo.Update(ctx, task); err != nil {
-		return err
-	}
-	
-	return s.userRepo.Update(ctx, user)
+	return inTx(ctx, s.tx, func(ctx context.Context) error {
+		task, err := s.taskRepo.GetByID(ctx, taskID)
+		if err != nil {
+			return err
+		}
+		
+		if task.UserID != userID {
+			return domain.ErrUnauthorized
+		}
+		
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		
+		if err := user.CheckPenalty(task); err != nil {
+			return err
+		}
+		
+		user.RegenerateEnergy(time.Now(), s.energy)
+		if err := user.Because(domain.ReasonTaskStarted, task.ID).SpendEnergy(task.EnergyCost); err != nil {
+			return err
+		}
+		
+		if err := task.Start(); err != nil {
+			return err
+		}
+		
+		if err := s.taskRepo.Update(ctx, task); err != nil {
+			return err
+		}
+		
+		return s.userRepo.Update(ctx, user)
+	})
 }
 
 // CompleteTask - завершить задание
 func (s *TaskService) CompleteTask(ctx context.Context, taskID, userID int64) (*TaskCompletionResult, error) {
-	task, err := s.taskRepo.GetByID(ctx, taskID)
-	if err != nil {
-		return nil, err
-	}
+	var result *TaskCompletionResult
 	
-	if task.UserID != userID {
-		return nil, domain.ErrUnauthorized
-	}
-	
-	user, err := s.userRepo.GetByID(ctx, userID)
+	err := inTx(ctx, s.tx, func(ctx context.Context) error {
+		task, err := s.taskRepo.GetByID(ctx, taskID)
+		if err != nil {
+			return err
+		}
+		
+		if task.UserID != userID {
+			return domain.ErrUnauthorized
+		}
+		
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		
+		// В зоне наказания награды дает только штрафной квест, и он же из нее выводит
+		if err := user.CheckPenalty(task); err != nil {
+			return err
+		}
+		cleared := task.Frequency == domain.FrequencyPenalty && user.InPenaltyZone()
+		if cleared {
+			user.LeavePenaltyZone()
+		}
+		
+		// Штраф за отсутствие лицензии
+		if task.Frequency != domain.FrequencyDaily && !user.IsLicenseValid() {
+			task.XPReward = task.XPReward / 2
+			task.GoldReward = task.GoldReward / 2
+		}
+		
+		if err := task.Complete(); err != nil {
+			return err
+		}
+		
+		// Штрафной квест серию не продлевает и бонусов не получает
+		now := time.Now()
+		bonus := domain.StreakBonus{XP: 1, Gold: 1}
+		gearBonus, xpBoost := 0, 0
+		if task.Frequency != domain.FrequencyPenalty {
+			user.RecordStreak(domain.QuestDate(now, user.Location()), s.streaks)
+			
+			questStreak, err := s.recordQuestStreak(ctx, task)
+			if err != nil {
+				return err
+			}
+			bonus = s.streaks.Bonus(user.StreakDays, questStreak)
+			if err := s.armory.LoadGear(ctx, user); err != nil {
+				return err
+			}
+			gearBonus = user.GearBonus(task.TaskType)
+			xpBoost = user.XPBoost(now)
+		}
+		rewards := task.GetRewardsWithStreak(bonus)
+		rewards.ApplyGearBonus(gearBonus)
+		rewards.ApplyXPBoost(xpBoost)
+		
+		user.Because(domain.ReasonTaskCompleted, task.ID)
+		leveledUp := user.AddXP(rewards.XP)
+		user.AddGold(rewards.Gold)
+		user.IncreaseAttribute(string(task.TaskType), task.StatBoost)
+		
+		if err := s.taskRepo.Update(ctx, task); err != nil {
+			return err
+		}
+		
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		
+		// События сохраняются в этой же транзакции; уведомления о них разошлет релей
+		completed := domain.NewEvent(domain.EventTaskCompleted, userID, task.ID)
+		completed.TaskType = task.TaskType
+		completed.Frequency = task.Frequency
+		completed.Urgent = task.IsUrgent
+		completed.Boss = task.IsBoss()
+		completed.Streak = user.StreakDays
+		completed.XP = rewards.XP
+		completed.Gold = rewards.Gold
+		events := []domain.Event{completed}
+		if leveledUp {
+			events = append(events, domain.LevelUpEvent(user))
+		}
+		if cleared {
+			events = append(events, domain.NewEvent(domain.EventPenaltyCleared, userID, task.ID))
+		}
+		if err := publish(ctx, s.events, events...); err != nil {
+			return err
+		}
+		
+		result = &TaskCompletionResult{
+			Task:      task,
+			LeveledUp: leveledUp,
+			NewLevel:  user.Level,
+			Rewards:   rewards,
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 	
-	// Штраф за отсутствие лицензии
-	if task.Frequency != domain.FrequencyDaily && !user.IsLicenseValid() {
-		task.XPReward = task.XPReward / 2
-		task.GoldReward = task.GoldReward / 2
-	}
-	
-	if err := task.Complete(); err != nil {
-		return nil, err
-	}
-	
-	leveledUp := user.AddXP(task.XPReward)
-	user.AddGold(task.GoldReward)
-	user.IncreaseAttribute(string(task.TaskType), task.StatBoost)
-	
-	if err := s.taskRepo.Update(ctx, task); err != nil {
-		return nil, err
-	}
-	
-	if err := s.userRepo.Update(ctx, user); err != nil {
-		return nil, err
-	}
-	
-	result := &TaskCompletionResult{
-		Task:      task,
-		LeveledUp: leveledUp,
-		NewLevel:  user.Level,
-		Rewards:   task.GetRewards(),
-	}
-	
 	return result, nil
 }
 
-// DeclineUrgentCall - отказ от срочного вызова
-func (s *TaskService) DeclineUrgentCall(ctx context.Context, taskID, userID int64) error {
-	task, err := s.taskRepo.GetByID(ctx, taskID)
-	if err != nil {
-		return err
+// recordQuestStreak - продлевает серию шаблона, из которого создан квест; 0 для обычных заданий
+func (s *TaskService) recordQuestStreak(ctx context.Context, task *domain.Task) (int, error) {
+	if task.TemplateID == nil {
+		return 0, nil
 	}
 	
-	if task.UserID != userID {
-		return domain.ErrUnauthorized
-	}
-	
-	if !task.IsUrgent {
-		return domain.ErrTaskNotActive
-	}
-	
-	user, err := s.userRepo.GetByID(ctx, userID)
+	template, err := s.templateRepo.GetByID(ctx, *task.TemplateID)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	
-	user.AddGold(-task.Penalty)
-	task.Fail()
-	
-	if err := s.taskRepo.Update(ctx, task); err != nil {
-		return err
+	template.RecordCompletion(task.QuestDate)
+	if err := s.templateRepo.Update(ctx, template); err != nil {
+		return 0, err
 	}
-	
-	return s.userRepo.Update(ctx, user)
+	return template.Streak, nil
+}
+
+// DeclineUrgentCall - отказ от срочного вызова
+func (s *TaskService) DeclineUrgentCall(ctx context.Context, taskID, userID int64) error {
+	return inTx(ctx, s.tx, func(ctx context.Context) error {
+		task, err := s.taskRepo.GetByID(ctx, taskID)
+		if err != nil {
+			return err
+		}
+		
+		if task.UserID != userID {
+			return domain.ErrUnauthorized
+		}
+		
+		if !task.IsUrgent {
+			return domain.ErrTaskNotActive
+		}
+		
+		if task.Status != domain.TaskStatusActive && task.Status != domain.TaskStatusInProgress {
+			return domain.ErrTaskNotActive
+		}
+		
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		
+		user.Because(domain.ReasonUrgentDeclined, task.ID).AddGold(-task.Penalty)
+		task.Fail()
+		
+		if err := s.taskRepo.Update(ctx, task); err != nil {
+			return err
+		}
+		
+		return s.userRepo.Update(ctx, user)
+	})
 }
 
 type TaskCompletionResult struct {
@@ -0,0 +1,233 @@
+// internal/core/task_service_test.go
+package core
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"dojo/internal/domain"
+	"dojo/internal/ports"
+)
+
+// memStore - хранилище в памяти с проверкой версий, как в postgres-репозиториях
+type memStore struct {
+	mu    sync.Mutex
+	users map[int64]domain.User
+	tasks map[int64]domain.Task
+}
+
+// memTx - изменения транзакции, применяются разом при коммите
+type memTx struct {
+	users map[int64]domain.User
+	tasks map[int64]domain.Task
+}
+
+type memTxKey struct{}
+
+func txFrom(ctx context.Context) *memTx {
+	tx, _ := ctx.Value(memTxKey{}).(*memTx)
+	return tx
+}
+
+type memTxManager struct {
+	store *memStore
+}
+
+func (m *memTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx := &memTx{users: map[int64]domain.User{}, tasks: map[int64]domain.Task{}}
+	if err := fn(context.WithValue(ctx, memTxKey{}, tx)); err != nil {
+		return err
+	}
+
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+
+	// Коммит проходит, только если никто не успел изменить те же строки
+	for id, u := range tx.users {
+		if m.store.users[id].Version != u.Version-1 {
+			return domain.ErrConcurrentModification
+		}
+	}
+	for id, t := range tx.tasks {
+		if m.store.tasks[id].Version != t.Version-1 {
+			return domain.ErrConcurrentModification
+		}
+	}
+
+	for id, u := range tx.users {
+		m.store.users[id] = u
+	}
+	for id, t := range tx.tasks {
+		m.store.tasks[id] = t
+	}
+	return nil
+}
+
+type memUserRepo struct {
+	ports.UserRepository
+	store *memStore
+}
+
+func (r *memUserRepo) GetByID(ctx context.Context, id int64) (*domain.User, error) {
+	if tx := txFrom(ctx); tx != nil {
+		if u, ok := tx.users[id]; ok {
+			return &u, nil
+		}
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	u, ok := r.store.users[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	return &u, nil
+}
+
+func (r *memUserRepo) Update(ctx context.Context, user *domain.User) error {
+	// Задержка "базы" между чтением и записью, чтобы параллельные операции пересекались
+	time.Sleep(time.Millisecond)
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.users[user.ID].Version != user.Version {
+		return domain.ErrConcurrentModification
+	}
+
+	user.Version++
+	if tx := txFrom(ctx); tx != nil {
+		tx.users[user.ID] = *user
+		return nil
+	}
+	r.store.users[user.ID] = *user
+	return nil
+}
+
+type memTaskRepo struct {
+	ports.TaskRepository
+	store *memStore
+}
+
+func (r *memTaskRepo) GetByID(ctx context.Context, id int64) (*domain.Task, error) {
+	if tx := txFrom(ctx); tx != nil {
+		if t, ok := tx.tasks[id]; ok {
+			return &t, nil
+		}
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	t, ok := r.store.tasks[id]
+	if !ok {
+		return nil, domain.ErrTaskNotFound
+	}
+	return &t, nil
+}
+
+func (r *memTaskRepo) Update(ctx context.Context, task *domain.Task) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.tasks[task.ID].Version != task.Version {
+		return domain.ErrConcurrentModification
+	}
+
+	task.Version++
+	if tx := txFrom(ctx); tx != nil {
+		tx.tasks[task.ID] = *task
+		return nil
+	}
+	r.store.tasks[task.ID] = *task
+	return nil
+}
+
+func TestCompleteTaskConcurrentlyKeepsAllRewards(t *testing.T) {
+	const (
+		tasks      = 8
+		xpReward   = 5
+		goldReward = 7
+	)
+
+	store := &memStore{
+		users: map[int64]domain.User{
+			1: {
+				ID:               1,
+				Level:            3,
+				LicenseActive:    true,
+				LicenseExpiresAt: time.Now().Add(24 * time.Hour),
+			},
+		},
+		tasks: map[int64]domain.Task{},
+	}
+	for id := int64(1); id <= tasks; id++ {
+		store.tasks[id] = domain.Task{
+			ID:         id,
+			UserID:     1,
+			TaskType:   domain.TypeStrength,
+			Status:     domain.TaskStatusInProgress,
+			XPReward:   xpReward,
+			GoldReward: goldReward,
+			StatBoost:  1,
+		}
+	}
+
+	service := NewTaskService(
+		&memTaskRepo{store: store},
+		&memUserRepo{store: store},
+		nil,
+		&memTxManager{store: store},
+		nil,
+		nil,
+		nil,
+		domain.DefaultEnergyConfig(),
+		domain.DefaultStreakRules(),
+	)
+
+	start := make(chan struct{})
+	errs := make(chan error, tasks)
+
+	var wg sync.WaitGroup
+	for id := int64(1); id <= tasks; id++ {
+		wg.Add(1)
+		go func(taskID int64) {
+			defer wg.Done()
+			<-start
+			_, err := service.CompleteTask(context.Background(), taskID, 1)
+			errs <- err
+		}(id)
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("CompleteTask: %v", err)
+		}
+	}
+
+	user := store.users[1]
+	if user.Gold != tasks*goldReward {
+		t.Errorf("gold = %d, want %d", user.Gold, tasks*goldReward)
+	}
+	if user.XP != tasks*xpReward {
+		t.Errorf("xp = %d, want %d", user.XP, tasks*xpReward)
+	}
+	if user.Strength != tasks {
+		t.Errorf("strength = %d, want %d", user.Strength, tasks)
+	}
+	if user.Version != tasks {
+		t.Errorf("version = %d, want %d", user.Version, tasks)
+	}
+
+	for id, task := range store.tasks {
+		if task.Status != domain.TaskStatusCompleted {
+			t.Errorf("task %d status = %s, want completed", id, task.Status)
+		}
+	}
+}
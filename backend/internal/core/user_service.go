@@ -10,29 +10,47 @@ import (
 
 type UserService struct {
 	userRepo  ports.UserRepository
+	tx        ports.TxManager
 	aiService ports.AIService
+	events    ports.EventPublisher
+	armory    *Armory
+	energy    domain.EnergyConfig
+	streaks   domain.StreakRules
 }
 
-func NewUserService(userRepo ports.UserRepository, aiService ports.AIService) *UserService {
+func NewUserService(userRepo ports.UserRepository, tx ports.TxManager, aiService ports.AIService, events ports.EventPublisher, armory *Armory, energy domain.EnergyConfig, streaks domain.StreakRules) *UserService {
 	return &UserService{
 		userRepo:  userRepo,
+		tx:        tx,
 		aiService: aiService,
+		events:    events,
+		armory:    armory,
+		energy:    energy,
+		streaks:   streaks,
 	}
 }
 
 // GetOrCreateUser - получить или создать пользователя
 func (s *UserService) GetOrCreateUser(ctx context.Context, telegramID int64, username, firstName, photoURL string) (*domain.User, error) {
-	user, err := s.userRepo.GetByTelegramID(ctx, telegramID)
-	if err == nil {
+	var user *domain.User
+	err := retryConflicts(ctx, func() error {
+		var err error
+		user, err = s.userRepo.GetByTelegramID(ctx, telegramID)
+		if err != nil {
+			return err
+		}
+		
 		user.UpdateActivity()
 		user.Username = username
 		user.FirstName = firstName
-		user.PhotoURL = photoURL
-		
-		if err := s.userRepo.Update(ctx, user); err != nil {
-			return nil, err
+		// Бот не знает фото, не затираем то, что пришло из Mini App
+		if photoURL != "" {
+			user.PhotoURL = photoURL
 		}
 		
+		return s.userRepo.Update(ctx, user)
+	})
+	if err == nil {
 		return user, nil
 	}
 	
@@ -59,8 +77,16 @@ func (s *UserService) GetOrCreateUser(ctx context.Context, telegramID int64, use
 		user.XPToNextLvl = user.CalculateXPToNextLevel()
 		user.RenewLicense()
 		user.SenseiResetsAt = time.Now().AddDate(0, 0, 7)
+		user.LastEnergyUpdate = time.Now()
+		user.OpenLedger()
 		
-		if err := s.userRepo.Create(ctx, user); err != nil {
+		err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+			if err := s.userRepo.Create(ctx, user); err != nil {
+				return err
+			}
+			return publish(ctx, s.events, domain.NewEvent(domain.EventUserRegistered, user.ID, 0))
+		})
+		if err != nil {
 			return nil, err
 		}
 		
@@ -70,115 +96,135 @@ func (s *UserService) GetOrCreateUser(ctx context.Context, telegramID int64, use
 	return nil, err
 }
 
+// Profile - профиль игрока с расчетными полями
+type Profile struct {
+	*domain.User
+	// Когда энергия станет полной; нет, если уже полная
+	EnergyFullAt        *time.Time `json:"energy_full_at,omitempty"`
+	EnergyFullInSeconds int        `json:"energy_full_in_seconds"`
+	EnergyPerMinute     float64    `json:"energy_per_minute"`
+	EnergyRefillCost    int        `json:"energy_refill_cost"`
+	// Серия дней и не сгорит ли она сегодня
+	Streak           *domain.StreakStatus `json:"streak"`
+	StreakFreezeCost int                  `json:"streak_freeze_cost"`
+}
+
 // GetProfile - получить профиль
-func (s *UserService) GetProfile(ctx context.Context, userID int64) (*domain.User, error) {
+func (s *UserService) GetProfile(ctx context.Context, userID int64) (*Profile, error) {
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 	
+	// Только отметка активности, без перезаписи всего профиля
 	user.UpdateActivity()
-	s.userRepo.Update(ctx, user)
-	
-	return user, nil
-}
-
-// RenewLicense - продлить лицензию
-func (s *UserService) RenewLicense(ctx context.Context, userID int64) error {
-	user, err := s.userRepo.GetByID(ctx, userID)
-	if err != nil {
-		return err
-	}
+	s.userRepo.UpdateActivity(ctx, userID)
 	
-	user.RenewLicense()
-	return s.userRepo.Update(ctx, user)
-}
-
-// ChatWithSensei - чат с Сенсеем
-func (s *UserService) ChatWithSensei(ctx context.Context, userID int64, message string, history []ports.ChatMessage) (string, error) {
-	user, err := s.userRepo.GetByID(ctx, userID)
-	if err != nil {
-		return "", err
-	}
+	// Восстановленную энергию только показываем: сохранится при следующем изменении игрока
+	now := time.Now()
+	user.RegenerateEnergy(now, s.energy)
 	
-	if err := user.UseSenseiRequest(); err != nil {
-		return "", err
+	if err := s.armory.LoadGear(ctx, user); err != nil {
+		return nil, err
 	}
 	
-	response, err := s.aiService.Chat(ctx, userID, message, history)
-	if err != nil {
-		user.SenseiRequests++
-		s.userRepo.Update(ctx, user)
-		return "", err
+	profile := &Profile{
+		User:             user,
+		EnergyFullAt:     user.EnergyFullAt(s.energy),
+		EnergyPerMinute:  s.energy.Rate(user.Level),
+		EnergyRefillCost: s.energy.RefillCost,
+		Streak:           user.Streak(now, s.streaks),
+		StreakFreezeCost: s.streaks.FreezeCost,
 	}
-	
-	if err := s.userRepo.Update(ctx, user); err != nil {
-		return "", err
+	if profile.EnergyFullAt != nil {
+		profile.EnergyFullInSeconds = int(profile.EnergyFullAt.Sub(now).Seconds())
 	}
 	
-	return response, nil
+	return profile, nil
 }
 
-// RaidInactivePlayer - рейд неактивного игрока
-func (s *UserService) RaidInactivePlayer(ctx context.Context, attackerID, targetID int64, cost int) (*RaidResult, error) {
-	attacker, err := s.userRepo.GetByID(ctx, attackerID)
+// BuyEnergyRefill - полное восстановление энергии за золото
+func (s *UserService) BuyEnergyRefill(ctx context.Context, userID int64) (*domain.User, error) {
+	var user *domain.User
+	err := inTx(ctx, s.tx, func(ctx context.Context) error {
+		var err error
+		user, err = s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		
+		user.RegenerateEnergy(time.Now(), s.energy)
+		if err := user.Because(domain.ReasonEnergyRefill, 0).BuyEnergyRefill(s.energy.RefillCost); err != nil {
+			return err
+		}
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		return publish(ctx, s.events, domain.NewEvent(domain.EventEnergyRefilled, userID, 0))
+	})
 	if err != nil {
 		return nil, err
 	}
 	
-	target, err := s.userRepo.GetByID(ctx, targetID)
+	return user, nil
+}
+
+// BuyStreakFreeze - заморозка серии за золото
+func (s *UserService) BuyStreakFreeze(ctx context.Context, userID int64) (*domain.User, error) {
+	var user *domain.User
+	err := inTx(ctx, s.tx, func(ctx context.Context) error {
+		var err error
+		user, err = s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		
+		if err := user.Because(domain.ReasonStreakFreeze, 0).BuyStreakFreeze(s.streaks); err != nil {
+			return err
+		}
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		return publish(ctx, s.events, domain.NewEvent(domain.EventStreakFreezeBought, userID, 0))
+	})
 	if err != nil {
 		return nil, err
 	}
 	
-	if attackerID == targetID {
-		return nil, domain.ErrCannotRaidSelf
-	}
-	
-	if !target.IsInactive() {
-		return nil, domain.ErrPlayerNotInactive
-	}
-	
-	if !attacker.IsLicenseValid() {
-		return nil, domain.ErrLicenseInactive
-	}
-	
-	if err := attacker.SpendGold(cost); err != nil {
-		return nil, err
-	}
-	
-	loot := int(float64(target.Gold) * 0.2)
-	if loot < 10 {
-		loot = 10
-	}
-	
-	target.AddGold(-loot)
-	attacker.AddGold(loot)
-	
-	bonusXP := target.Level * 5
-	attacker.AddXP(bonusXP)
-	
-	if err := s.userRepo.Update(ctx, attacker); err != nil {
-		return nil, err
-	}
-	
-	if err := s.userRepo.Update(ctx, target); err != nil {
+	return user, nil
+}
+
+// SetTimezone - часовой пояс игрока для квестов и тихих часов
+func (s *UserService) SetTimezone(ctx context.Context, userID int64, timezone string) (*domain.User, error) {
+	var user *domain.User
+	err := retryConflicts(ctx, func() error {
+		var err error
+		user, err = s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		
+		if err := user.SetTimezone(timezone); err != nil {
+			return err
+		}
+		return s.userRepo.Update(ctx, user)
+	})
+	if err != nil {
 		return nil, err
 	}
 	
-	result := &RaidResult{
-		GoldLooted: loot,
-		XPGained:   bonusXP,
-		TargetName: target.Username,
-		TargetRank: target.GetRank(),
-	}
-	
-	return result, nil
+	return user, nil
 }
 
-type RaidResult struct {
-	GoldLooted int    `json:"gold_looted"`
-	XPGained   int    `json:"xp_gained"`
-	TargetName string `json:"target_name"`
-	TargetRank string `json:"target_rank"`
-}
\ No newline at end of file
+// RenewLicense - продлить лицензию
+func (s *UserService) RenewLicense(ctx context.Context, userID int64) error {
+	return retryConflicts(ctx, func() error {
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		
+		user.RenewLicense()
+		return s.userRepo.Update(ctx, user)
+	})
+}
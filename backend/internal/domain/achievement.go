@@ -0,0 +1,172 @@
+// internal/domain/achievement.go
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AchievementMeasure - как событие продвигает достижение
+type AchievementMeasure string
+
+const (
+	MeasureCount AchievementMeasure = "count" // +1 за каждое подходящее событие
+	MeasureSum   AchievementMeasure = "sum"   // + значение поля Field
+	MeasureMax   AchievementMeasure = "max"   // лучшее значение поля Field
+)
+
+// AchievementReward - разовая награда за открытие
+type AchievementReward struct {
+	XP   int `json:"xp,omitempty"`
+	Gold int `json:"gold,omitempty"`
+}
+
+// AchievementRule - достижение из каталога: какие события считать и сколько нужно
+type AchievementRule struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Badge       string `json:"badge"`
+	// Титул, который игрок может выбрать после открытия; "" - без титула
+	Title string `json:"title,omitempty"`
+	// Скрытое достижение не показывает название и условие, пока не открыто
+	Hidden bool `json:"hidden,omitempty"`
+
+	Event EventType `json:"event"`
+	// Фильтры по полям события, например {"task_type": "strength"}
+	Where   map[string]string  `json:"where,omitempty"`
+	Measure AchievementMeasure `json:"measure,omitempty"`
+	Field   string             `json:"field,omitempty"`
+	Target  int                `json:"target"`
+
+	Reward AchievementReward `json:"reward"`
+}
+
+// ParseAchievementRules - каталог достижений из JSON с проверкой правил
+func ParseAchievementRules(data []byte) ([]*AchievementRule, error) {
+	var rules []*AchievementRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("каталог достижений: %w", err)
+	}
+
+	seen := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		if rule.Measure == "" {
+			rule.Measure = MeasureCount
+		}
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("достижение %q: %w", rule.ID, err)
+		}
+		if seen[rule.ID] {
+			return nil, fmt.Errorf("достижение %q описано дважды", rule.ID)
+		}
+		seen[rule.ID] = true
+	}
+	return rules, nil
+}
+
+func (r *AchievementRule) validate() error {
+	switch {
+	case r.ID == "" || r.Name == "":
+		return fmt.Errorf("нужны id и name")
+	case !IsKnownEvent(r.Event):
+		return fmt.Errorf("неизвестное событие %q", r.Event)
+	case r.Target <= 0:
+		return fmt.Errorf("target должен быть больше нуля")
+	case r.Reward.XP < 0 || r.Reward.Gold < 0:
+		return fmt.Errorf("награда не может быть отрицательной")
+	}
+
+	for field := range r.Where {
+		if _, _, ok := (Event{}).Field(field); !ok {
+			return fmt.Errorf("неизвестное поле %q в where", field)
+		}
+	}
+
+	switch r.Measure {
+	case MeasureCount:
+		return nil
+	case MeasureSum, MeasureMax:
+		if _, _, ok := (Event{}).Field(r.Field); !ok {
+			return fmt.Errorf("неизвестное поле %q", r.Field)
+		}
+		return nil
+	default:
+		return fmt.Errorf("неизвестный measure %q", r.Measure)
+	}
+}
+
+// Matches - продвигает ли событие это достижение
+func (r *AchievementRule) Matches(e Event) bool {
+	if e.Type != r.Event {
+		return false
+	}
+	for field, want := range r.Where {
+		if got, _, _ := e.Field(field); got != want {
+			return false
+		}
+	}
+	return true
+}
+
+// UserAchievement - прогресс игрока по одному достижению
+type UserAchievement struct {
+	ID            int64      `json:"-" gorm:"primaryKey"`
+	UserID        int64      `json:"-" gorm:"not null;uniqueIndex:idx_user_achievements"`
+	AchievementID string     `json:"achievement_id" gorm:"not null;uniqueIndex:idx_user_achievements"`
+	Progress      int        `json:"progress" gorm:"not null"`
+	UnlockedAt    *time.Time `json:"unlocked_at,omitempty"`
+
+	// Version - оптимистическая блокировка: растет при каждом сохранении
+	Version   int64     `json:"-" gorm:"not null"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+// NewUserAchievement - еще не начатое достижение
+func NewUserAchievement(userID int64, rule *AchievementRule) *UserAchievement {
+	return &UserAchievement{
+		UserID:        userID,
+		AchievementID: rule.ID,
+	}
+}
+
+// IsUnlocked - достижение открыто
+func (a *UserAchievement) IsUnlocked() bool {
+	return a.UnlockedAt != nil
+}
+
+// Advance - учитывает событие. Возвращает true, если достижение открылось
+// именно сейчас; открытое больше не меняется
+func (a *UserAchievement) Advance(rule *AchievementRule, e Event) bool {
+	if a.IsUnlocked() || !rule.Matches(e) {
+		return false
+	}
+
+	_, value, _ := e.Field(rule.Field)
+	switch rule.Measure {
+	case MeasureSum:
+		a.Progress += max(value, 0)
+	case MeasureMax:
+		a.Progress = max(a.Progress, value)
+	default:
+		a.Progress++
+	}
+
+	if a.Progress < rule.Target {
+		return false
+	}
+	a.Progress = rule.Target
+	now := e.OccurredAt
+	if now.IsZero() {
+		now = time.Now()
+	}
+	a.UnlockedAt = &now
+	return true
+}
+
+// SetTitle - титул рядом с именем игрока; "" снимает титул
+func (u *User) SetTitle(title string) {
+	u.Title = title
+}
@@ -0,0 +1,156 @@
+// internal/domain/combat.go
+package domain
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Сколько раундов нужно выиграть, чтобы победить в бою
+const RoundsToWin = 3
+
+// Границы шанса выиграть один раунд: исход никогда не предрешен
+const (
+	minRoundChance = 0.05
+	maxRoundChance = 0.95
+)
+
+// Fighter - характеристики бойца на момент боя
+type Fighter struct {
+	Level        int `json:"level"`
+	Strength     int `json:"strength"`
+	Agility      int `json:"agility"`
+	Intelligence int `json:"intelligence"`
+	Insight      int `json:"insight"`
+}
+
+// FighterOf - снимок характеристик игрока вместе с надетым снаряжением
+func FighterOf(u *User) Fighter {
+	stats := u.EffectiveStats()
+	return Fighter{
+		Level:        u.Level,
+		Strength:     stats.Strength,
+		Agility:      stats.Agility,
+		Intelligence: stats.Intelligence,
+		Insight:      stats.Insight,
+	}
+}
+
+func (f Fighter) attribute(attr TaskType) int {
+	switch attr {
+	case TypeStrength:
+		return f.Strength
+	case TypeAgility:
+		return f.Agility
+	case TypeIntelligence:
+		return f.Intelligence
+	default:
+		return f.Insight
+	}
+}
+
+// Характеристики, которыми бойцы меряются в раундах
+var combatAttributes = []TaskType{TypeStrength, TypeAgility, TypeIntelligence, TypeInsight}
+
+// CombatRound - один раунд для лога боя
+type CombatRound struct {
+	Round int `json:"round"`
+	// Характеристика, которой мерялись в раунде
+	Attribute TaskType `json:"attribute"`
+	// Значения характеристики у атакующего и защитника
+	AttackerValue int  `json:"attacker_value"`
+	DefenderValue int  `json:"defender_value"`
+	AttackerWon   bool `json:"attacker_won"`
+	// Счет по раундам после этого
+	AttackerScore int `json:"attacker_score"`
+	DefenderScore int `json:"defender_score"`
+}
+
+// Battle - исход боя. С тем же Seed и теми же бойцами бой повторяется один в один
+type Battle struct {
+	Seed        int64         `json:"seed"`
+	Attacker    Fighter       `json:"attacker"`
+	Defender    Fighter       `json:"defender"`
+	WinChance   float64       `json:"win_chance"`
+	AttackerWon bool          `json:"attacker_won"`
+	Rounds      []CombatRound `json:"rounds"`
+}
+
+// ResolveBattle - бой до RoundsToWin выигранных раундов.
+// В каждом раунде случайно выбирается одна из четырех характеристик
+func ResolveBattle(attacker, defender Fighter, seed int64) *Battle {
+	rng := rand.New(rand.NewSource(seed))
+
+	battle := &Battle{
+		Seed:      seed,
+		Attacker:  attacker,
+		Defender:  defender,
+		WinChance: WinChance(attacker, defender),
+	}
+
+	var attackerScore, defenderScore int
+	for round := 1; attackerScore < RoundsToWin && defenderScore < RoundsToWin; round++ {
+		attr := combatAttributes[rng.Intn(len(combatAttributes))]
+		won := rng.Float64() < roundChance(attacker, defender, attr)
+		if won {
+			attackerScore++
+		} else {
+			defenderScore++
+		}
+
+		battle.Rounds = append(battle.Rounds, CombatRound{
+			Round:         round,
+			Attribute:     attr,
+			AttackerValue: attacker.attribute(attr),
+			DefenderValue: defender.attribute(attr),
+			AttackerWon:   won,
+			AttackerScore: attackerScore,
+			DefenderScore: defenderScore,
+		})
+	}
+
+	battle.AttackerWon = attackerScore == RoundsToWin
+	return battle
+}
+
+// WinChance - точная вероятность победы атакующего в ResolveBattle
+func WinChance(attacker, defender Fighter) float64 {
+	// Характеристика раунда выбирается равновероятно, поэтому шанс раунда - среднее
+	var p float64
+	for _, attr := range combatAttributes {
+		p += roundChance(attacker, defender, attr)
+	}
+	p /= float64(len(combatAttributes))
+
+	// Победа в серии до RoundsToWin: защитник успел взять j < RoundsToWin раундов
+	var chance float64
+	for j := 0; j < RoundsToWin; j++ {
+		chance += binomial(RoundsToWin-1+j, j) * math.Pow(p, RoundsToWin) * math.Pow(1-p, float64(j))
+	}
+	return chance
+}
+
+// roundChance - шанс атакующего выиграть раунд по характеристике attr.
+// Решает соотношение характеристик, разница уровней дает до ±20%
+func roundChance(attacker, defender Fighter, attr TaskType) float64 {
+	a := float64(attacker.attribute(attr))
+	d := float64(defender.attribute(attr))
+
+	p := 0.5
+	if a+d > 0 {
+		p = a / (a + d)
+	}
+
+	levelBonus := float64(attacker.Level-defender.Level) * 0.01
+	p += math.Max(-0.2, math.Min(0.2, levelBonus))
+
+	return math.Max(minRoundChance, math.Min(maxRoundChance, p))
+}
+
+func binomial(n, k int) float64 {
+	result := 1.0
+	for i := 1; i <= k; i++ {
+		result = result * float64(n-k+i) / float64(i)
+	}
+	return result
+}
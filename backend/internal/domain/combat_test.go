@@ -0,0 +1,134 @@
+// internal/domain/combat_test.go
+package domain
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+var (
+	novice  = Fighter{Level: 3, Strength: 4, Agility: 6, Intelligence: 2, Insight: 5}
+	veteran = Fighter{Level: 12, Strength: 15, Agility: 9, Intelligence: 11, Insight: 8}
+)
+
+func TestResolveBattleIsReproducible(t *testing.T) {
+	for seed := int64(0); seed < 50; seed++ {
+		first := ResolveBattle(novice, veteran, seed)
+		second := ResolveBattle(novice, veteran, seed)
+
+		if !reflect.DeepEqual(first, second) {
+			t.Fatalf("seed %d: battles differ:\n%+v\n%+v", seed, first, second)
+		}
+	}
+}
+
+func TestResolveBattleRounds(t *testing.T) {
+	for seed := int64(0); seed < 200; seed++ {
+		battle := ResolveBattle(novice, veteran, seed)
+
+		var attackerWins, defenderWins int
+		for i, round := range battle.Rounds {
+			if round.Round != i+1 {
+				t.Fatalf("seed %d: round %d numbered %d", seed, i+1, round.Round)
+			}
+			if round.AttackerWon {
+				attackerWins++
+			} else {
+				defenderWins++
+			}
+			if round.AttackerScore != attackerWins || round.DefenderScore != defenderWins {
+				t.Fatalf("seed %d: round %d score %d:%d, want %d:%d", seed, round.Round, round.AttackerScore, round.DefenderScore, attackerWins, defenderWins)
+			}
+			if round.AttackerValue != novice.attribute(round.Attribute) || round.DefenderValue != veteran.attribute(round.Attribute) {
+				t.Fatalf("seed %d: round %d values do not match %s", seed, round.Round, round.Attribute)
+			}
+		}
+
+		// Победитель взял ровно RoundsToWin раундов, проигравший - меньше
+		winner, loser := defenderWins, attackerWins
+		if battle.AttackerWon {
+			winner, loser = attackerWins, defenderWins
+		}
+		if winner != RoundsToWin || loser >= RoundsToWin {
+			t.Fatalf("seed %d: score %d:%d, attacker won = %v", seed, attackerWins, defenderWins, battle.AttackerWon)
+		}
+		if last := battle.Rounds[len(battle.Rounds)-1]; last.AttackerWon != battle.AttackerWon {
+			t.Fatalf("seed %d: last round does not decide the battle", seed)
+		}
+	}
+}
+
+func TestWinChanceIsMonotonic(t *testing.T) {
+	stronger := []func(f *Fighter){
+		func(f *Fighter) { f.Level++ },
+		func(f *Fighter) { f.Strength++ },
+		func(f *Fighter) { f.Agility++ },
+		func(f *Fighter) { f.Intelligence++ },
+		func(f *Fighter) { f.Insight++ },
+	}
+
+	for _, pair := range [][2]Fighter{{novice, veteran}, {veteran, novice}, {novice, novice}, {{}, {}}} {
+		for i, grow := range stronger {
+			attacker, defender := pair[0], pair[1]
+			base := WinChance(attacker, defender)
+
+			// Растущий атакующий не теряет шансов, растущий защитник - не дает их
+			for step := 0; step < 30; step++ {
+				grow(&attacker)
+				chance := WinChance(attacker, defender)
+				if chance < base {
+					t.Fatalf("stat %d step %d: attacker chance fell %.4f -> %.4f", i, step, base, chance)
+				}
+				base = chance
+			}
+
+			attacker = pair[0]
+			base = WinChance(attacker, defender)
+			for step := 0; step < 30; step++ {
+				grow(&defender)
+				chance := WinChance(attacker, defender)
+				if chance > base {
+					t.Fatalf("stat %d step %d: attacker chance rose %.4f -> %.4f against a stronger defender", i, step, base, chance)
+				}
+				base = chance
+			}
+		}
+	}
+}
+
+func TestWinChanceBounds(t *testing.T) {
+	if got := WinChance(novice, novice); math.Abs(got-0.5) > 1e-9 {
+		t.Errorf("even fight chance = %.4f, want 0.5", got)
+	}
+	if got, rev := WinChance(novice, veteran), WinChance(veteran, novice); math.Abs(got+rev-1) > 1e-9 {
+		t.Errorf("chances %.4f and %.4f do not sum to 1", got, rev)
+	}
+
+	// Даже безнадежный бой не предрешен
+	hopeless := WinChance(Fighter{Level: 1}, Fighter{Level: 100, Strength: 100, Agility: 100, Intelligence: 100, Insight: 100})
+	if hopeless <= 0 || hopeless > 0.01 {
+		t.Errorf("hopeless chance = %.6f", hopeless)
+	}
+}
+
+func TestWinChanceMatchesResolveBattle(t *testing.T) {
+	const battles = 20000
+
+	for _, pair := range [][2]Fighter{{novice, veteran}, {veteran, novice}, {novice, novice}} {
+		var wins int
+		for seed := int64(0); seed < battles; seed++ {
+			battle := ResolveBattle(pair[0], pair[1], seed)
+			if battle.AttackerWon {
+				wins++
+			}
+		}
+
+		want := WinChance(pair[0], pair[1])
+		got := float64(wins) / battles
+		// Стандартное отклонение на 20000 боях меньше 0.004
+		if math.Abs(got-want) > 0.015 {
+			t.Errorf("%+v vs %+v: won %.4f of battles, WinChance %.4f", pair[0], pair[1], got, want)
+		}
+	}
+}
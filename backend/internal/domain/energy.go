@@ -0,0 +1,92 @@
+// internal/domain/energy.go
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// EnergyConfig - восстановление энергии со временем и покупка за золото
+type EnergyConfig struct {
+	// Очков энергии в минуту на первом уровне
+	PerMinute float64
+	// Прибавка к скорости за каждый уровень после первого
+	PerLevel float64
+	// Цена полного восстановления
+	RefillCost int
+}
+
+func DefaultEnergyConfig() EnergyConfig {
+	return EnergyConfig{
+		PerMinute:  0.2,
+		PerLevel:   0.01,
+		RefillCost: 50,
+	}
+}
+
+// Rate - очков энергии в минуту для игрока уровня level
+func (c EnergyConfig) Rate(level int) float64 {
+	if level < 1 {
+		level = 1
+	}
+	return c.PerMinute + c.PerLevel*float64(level-1)
+}
+
+// RegenerateEnergy - начисляет энергию, накопленную с LastEnergyUpdate.
+// Отметка сдвигается только на целые начисленные очки, дробный остаток не теряется
+func (u *User) RegenerateEnergy(now time.Time, config EnergyConfig) {
+	rate := config.Rate(u.Level)
+	if u.Energy >= u.MaxEnergy || rate <= 0 || u.LastEnergyUpdate.IsZero() {
+		u.LastEnergyUpdate = now
+		return
+	}
+
+	minutes := now.Sub(u.LastEnergyUpdate).Minutes()
+	points := int(math.Floor(minutes * rate))
+	if points <= 0 {
+		return
+	}
+
+	before := u.Energy
+	if u.Energy+points >= u.MaxEnergy {
+		u.Energy = u.MaxEnergy
+		u.LastEnergyUpdate = now
+	} else {
+		u.Energy += points
+		u.LastEnergyUpdate = u.LastEnergyUpdate.Add(time.Duration(float64(points) / rate * float64(time.Minute)))
+	}
+	// Причину текущей операции не трогаем: регенерация идет попутно
+	u.journalAs(ReasonEnergyRegen, ResourceEnergy, u.Energy-before, u.Energy)
+}
+
+// EnergyFullAt - когда энергия восстановится полностью; nil, если она уже полная или не растет
+func (u *User) EnergyFullAt(config EnergyConfig) *time.Time {
+	rate := config.Rate(u.Level)
+	if u.Energy >= u.MaxEnergy || rate <= 0 {
+		return nil
+	}
+
+	missing := float64(u.MaxEnergy - u.Energy)
+	at := u.LastEnergyUpdate.Add(time.Duration(missing / rate * float64(time.Minute)))
+	return &at
+}
+
+// BuyEnergyRefill - полное восстановление энергии за золото
+func (u *User) BuyEnergyRefill(cost int) error {
+	if u.Energy >= u.MaxEnergy {
+		return ErrEnergyFull
+	}
+
+	if err := u.SpendGold(cost); err != nil {
+		return err
+	}
+	u.RestoreEnergy(u.MaxEnergy)
+	return nil
+}
+
+// touchEnergy - с полной энергии отсчет восстановления начинается заново
+func (u *User) touchEnergy(wasFull bool) {
+	if wasFull && u.Energy < u.MaxEnergy {
+		u.LastEnergyUpdate = time.Now()
+	}
+}
@@ -9,8 +9,11 @@ var (
 	ErrUserAlreadyExists = errors.New("пользователь уже существует")
 	ErrInsufficientGold = errors.New("недостаточно золота")
 	ErrInsufficientEnergy = errors.New("недостаточно энергии")
+	ErrEnergyFull = errors.New("энергия и так полная")
 	ErrNoSenseiRequests = errors.New("закончились запросы к Сенсею")
 	ErrLicenseInactive = errors.New("лицензия охотника неактивна")
+	ErrInvalidTimezone = errors.New("неизвестный часовой пояс")
+	ErrTooManyStreakFreezes = errors.New("больше заморозок серии не накопить")
 )
 
 // Ошибки заданий
@@ -22,10 +25,52 @@ var (
 	ErrTaskAlreadyStarted = errors.New("задание уже начато")
 )
 
+// Ошибки квестов
+var (
+	ErrQuestTemplateNotFound = errors.New("шаблон квеста не найден")
+	ErrInvalidRecurrence = errors.New("неверное правило повтора")
+)
+
+// Ошибки зоны наказания
+var (
+	ErrInPenaltyZone = errors.New("ты в зоне наказания: сначала выполни штрафной квест")
+	ErrNotInPenaltyZone = errors.New("ты не в зоне наказания")
+)
+
+// Ошибки достижений
+var (
+	ErrAchievementNotFound = errors.New("достижение не найдено")
+	ErrTitleLocked = errors.New("этот титул еще не открыт")
+	ErrNoTitle = errors.New("за это достижение титул не дают")
+)
+
+// Ошибки магазина
+var (
+	ErrItemNotFound = errors.New("предмет не найден")
+	ErrItemNotForSale = errors.New("этот предмет не продается, его можно только добыть")
+	ErrItemNotOwned = errors.New("этого предмета нет в инвентаре")
+	ErrItemAlreadyOwned = errors.New("этот предмет у тебя уже есть")
+	ErrItemNotUsable = errors.New("этот предмет нельзя использовать")
+	ErrNotEquipment = errors.New("этот предмет нельзя надеть")
+	ErrInvalidQuantity = errors.New("неверное количество предметов")
+	ErrXPBoostActive = errors.New("уже действует более сильный свиток опыта")
+)
+
+// Ошибки доставки событий
+var (
+	ErrDeliveryNotFound = errors.New("доставка события не найдена")
+	ErrDeliveryNotDead = errors.New("доставка не в мертвой очереди")
+	ErrUnknownSubscriber = errors.New("нет такого подписчика событий")
+)
+
 // Ошибки авторизации
 var (
 	ErrInvalidTelegramData = errors.New("невалидные данные авторизации")
 	ErrUnauthorized = errors.New("требуется авторизация")
+	ErrInvalidToken = errors.New("невалидный токен")
+	ErrTokenExpired = errors.New("срок действия токена истек")
+	ErrSessionNotFound = errors.New("сессия не найдена")
+	ErrSessionRevoked = errors.New("сессия отозвана")
 )
 
 // Ошибки рейдов
@@ -33,10 +78,39 @@ var (
 	ErrRaidNotFound = errors.New("рейд не найден")
 	ErrCannotRaidSelf = errors.New("нельзя рейдить самого себя")
 	ErrPlayerNotInactive = errors.New("игрок активен")
+	ErrRaidCooldown = errors.New("рейд еще на перезарядке")
+	ErrTargetShielded = errors.New("цель под защитой от рейдов")
+	ErrTargetRecentlyRaided = errors.New("ты уже грабил этого игрока недавно")
+	ErrRaidDailyCapReached = errors.New("цель уже потеряла сегодня максимум золота")
+	ErrRankGapTooLarge = errors.New("цель вне твоей ранговой лиги")
+	ErrNothingToLoot = errors.New("у цели нечего взять")
+)
+
+// Ошибки Сенсея
+var (
+	ErrConversationNotFound = errors.New("диалог не найден")
+	ErrEmptyMessage = errors.New("пустое сообщение")
 )
 
 // Ошибки ИИ
 var (
 	ErrAIServiceUnavailable = errors.New("ИИ-сервис недоступен")
 	ErrAIAnalysisFailed = errors.New("не удалось проанализировать задание")
-)
\ No newline at end of file
+)
+
+// Ошибки уведомлений
+var (
+	ErrInvalidQuietHours = errors.New("тихие часы должны быть в диапазоне 0-23")
+	ErrRecipientUnavailable = errors.New("получатель недоступен для уведомлений")
+)
+
+// Ошибки журнала
+var (
+	ErrUnknownResource = errors.New("неизвестный ресурс")
+)
+
+// Ошибки хранилища
+var (
+	// ErrConcurrentModification - запись изменили между чтением и сохранением
+	ErrConcurrentModification = errors.New("данные изменились, повторите попытку")
+)
@@ -0,0 +1,137 @@
+// internal/domain/event.go
+package domain
+
+import (
+	"strconv"
+	"time"
+)
+
+// EventType - что произошло с игроком
+type EventType string
+
+const (
+	EventUserRegistered      EventType = "user_registered"
+	EventTaskCompleted       EventType = "task_completed"
+	EventLevelUp             EventType = "level_up"
+	EventRaidWon             EventType = "raid_won"
+	EventRaidLost            EventType = "raid_lost"
+	EventRaidDefended        EventType = "raid_defended" // цель рейда отбилась
+	EventRaided              EventType = "raided"        // цель рейда ограбили
+	EventTaskExpired         EventType = "task_expired"
+	EventLicenseExpired      EventType = "license_expired"
+	EventPenaltyEntered      EventType = "penalty_entered"
+	EventPenaltyCleared      EventType = "penalty_cleared"
+	EventEnergyRefilled      EventType = "energy_refilled"
+	EventStreakFreezeBought  EventType = "streak_freeze_bought"
+	EventAchievementUnlocked EventType = "achievement_unlocked"
+	EventItemPurchased       EventType = "item_purchased"
+	EventItemUsed            EventType = "item_used"
+	EventItemDropped         EventType = "item_dropped"
+)
+
+// EventTypes - все события, на которые можно подписаться
+var EventTypes = []EventType{
+	EventUserRegistered,
+	EventTaskCompleted,
+	EventLevelUp,
+	EventRaidWon,
+	EventRaidLost,
+	EventRaidDefended,
+	EventRaided,
+	EventTaskExpired,
+	EventLicenseExpired,
+	EventPenaltyEntered,
+	EventPenaltyCleared,
+	EventEnergyRefilled,
+	EventStreakFreezeBought,
+	EventAchievementUnlocked,
+	EventItemPurchased,
+	EventItemUsed,
+	EventItemDropped,
+}
+
+// Event - доменное событие. Сохраняется в outbox вместе с изменением, которое
+// его породило; поля, которые к событию не относятся, остаются нулевыми
+type Event struct {
+	// Номер в outbox; 0, пока событие не сохранено. Подписчики по нему отсеивают повторы
+	ID     int64     `json:"id,omitempty"`
+	Type   EventType `json:"type"`
+	UserID int64     `json:"user_id"`
+	// ID задания, рейда и т.п. в зависимости от Type; 0 - нет
+	RefID int64 `json:"ref_id,omitempty"`
+	// Второй участник рейда: цель для атакующего, атакующий для цели
+	OpponentID int64 `json:"opponent_id,omitempty"`
+
+	TaskType    TaskType      `json:"task_type,omitempty"`
+	Frequency   TaskFrequency `json:"frequency,omitempty"`
+	Urgent      bool          `json:"urgent,omitempty"`
+	Boss        bool          `json:"boss,omitempty"`
+	Level       int           `json:"level,omitempty"`
+	Rank        string        `json:"rank,omitempty"`
+	Streak      int           `json:"streak,omitempty"`
+	XP          int           `json:"xp,omitempty"`
+	Gold        int           `json:"gold,omitempty"`
+	Missed      int           `json:"missed,omitempty"` // проваленные квесты при входе в зону наказания
+	Achievement string        `json:"achievement,omitempty"`
+	Item        string        `json:"item,omitempty"`
+	Rarity      Rarity        `json:"rarity,omitempty"`
+	DropSource  DropSource    `json:"drop_source,omitempty"`
+
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// NewEvent - событие игрока userID, случившееся сейчас
+func NewEvent(eventType EventType, userID, refID int64) Event {
+	return Event{
+		Type:       eventType,
+		UserID:     userID,
+		RefID:      refID,
+		OccurredAt: time.Now(),
+	}
+}
+
+// LevelUpEvent - игрок получил новый уровень
+func LevelUpEvent(user *User) Event {
+	event := NewEvent(EventLevelUp, user.ID, 0)
+	event.Level = user.Level
+	event.Rank = user.GetRank()
+	return event
+}
+
+// eventFields - поля события, по которым правила достижений фильтруют и считают
+var eventFields = map[string]func(e Event) (string, int){
+	"task_type":   func(e Event) (string, int) { return string(e.TaskType), 0 },
+	"frequency":   func(e Event) (string, int) { return string(e.Frequency), 0 },
+	"rank":        func(e Event) (string, int) { return e.Rank, 0 },
+	"achievement": func(e Event) (string, int) { return e.Achievement, 0 },
+	"item":        func(e Event) (string, int) { return e.Item, 0 },
+	"rarity":      func(e Event) (string, int) { return string(e.Rarity), 0 },
+	"drop_source": func(e Event) (string, int) { return string(e.DropSource), 0 },
+	"urgent":      func(e Event) (string, int) { return strconv.FormatBool(e.Urgent), 0 },
+	"boss":        func(e Event) (string, int) { return strconv.FormatBool(e.Boss), 0 },
+	"level":       func(e Event) (string, int) { return strconv.Itoa(e.Level), e.Level },
+	"streak":      func(e Event) (string, int) { return strconv.Itoa(e.Streak), e.Streak },
+	"xp":          func(e Event) (string, int) { return strconv.Itoa(e.XP), e.XP },
+	"gold":        func(e Event) (string, int) { return strconv.Itoa(e.Gold), e.Gold },
+	"missed":      func(e Event) (string, int) { return strconv.Itoa(e.Missed), e.Missed },
+}
+
+// Field - значение поля события строкой и числом (0 для строковых полей)
+func (e Event) Field(name string) (text string, number int, ok bool) {
+	field, ok := eventFields[name]
+	if !ok {
+		return "", 0, false
+	}
+	text, number = field(e)
+	return text, number, true
+}
+
+// IsKnownEvent - есть ли такое событие
+func IsKnownEvent(eventType EventType) bool {
+	for _, known := range EventTypes {
+		if known == eventType {
+			return true
+		}
+	}
+	return false
+}
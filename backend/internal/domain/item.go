@@ -0,0 +1,424 @@
+// internal/domain/item.go
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// ItemKind - расходник или снаряжение
+type ItemKind string
+
+const (
+	ItemConsumable ItemKind = "consumable" // тратится при использовании
+	ItemEquipment  ItemKind = "equipment"  // надевается и дает прибавку к характеристикам
+)
+
+// ItemEffect - что делает расходник
+type ItemEffect string
+
+const (
+	EffectEnergy       ItemEffect = "energy"        // восстанавливает Amount энергии
+	EffectXPBoost      ItemEffect = "xp_boost"      // +Amount% опыта за задания на Minutes минут
+	EffectStreakFreeze ItemEffect = "streak_freeze" // одна заморозка серии
+	EffectRaidShield   ItemEffect = "raid_shield"   // защита от рейдов на Minutes минут
+)
+
+// EquipmentSlot - куда надевается снаряжение; в слоте один предмет
+type EquipmentSlot string
+
+const (
+	SlotWeapon    EquipmentSlot = "weapon"
+	SlotArmor     EquipmentSlot = "armor"
+	SlotAccessory EquipmentSlot = "accessory"
+)
+
+// EquipmentSlots - все слоты снаряжения
+var EquipmentSlots = []EquipmentSlot{SlotWeapon, SlotArmor, SlotAccessory}
+
+// Rarity - редкость предмета: чем реже, тем меньше шанс выпасть
+type Rarity string
+
+const (
+	RarityCommon    Rarity = "common"
+	RarityUncommon  Rarity = "uncommon"
+	RarityRare      Rarity = "rare"
+	RarityEpic      Rarity = "epic"
+	RarityLegendary Rarity = "legendary"
+)
+
+// rarities - вес при выпадении и золото за дубликат снаряжения
+var rarities = map[Rarity]struct{ weight, salvage int }{
+	RarityCommon:    {weight: 60, salvage: 20},
+	RarityUncommon:  {weight: 25, salvage: 50},
+	RarityRare:      {weight: 10, salvage: 150},
+	RarityEpic:      {weight: 4, salvage: 400},
+	RarityLegendary: {weight: 1, salvage: 1000},
+}
+
+// SalvageGold - сколько золота дают за выпавший дубликат
+func (r Rarity) SalvageGold() int {
+	return rarities[r].salvage
+}
+
+// DropSource - откуда выпадают предметы
+type DropSource string
+
+const (
+	DropUrgent DropSource = "urgent" // выполненный срочный вызов
+	DropRaid   DropSource = "raid"   // выигранный рейд
+	DropBoss   DropSource = "boss"   // выполненное задание-босс, см. Task.IsBoss
+)
+
+// Stats - характеристики игрока или прибавка к ним
+type Stats struct {
+	Strength     int `json:"strength"`
+	Agility      int `json:"agility"`
+	Intelligence int `json:"intelligence"`
+	Insight      int `json:"insight"`
+}
+
+// Add - сумма характеристик
+func (s Stats) Add(other Stats) Stats {
+	return Stats{
+		Strength:     s.Strength + other.Strength,
+		Agility:      s.Agility + other.Agility,
+		Intelligence: s.Intelligence + other.Intelligence,
+		Insight:      s.Insight + other.Insight,
+	}
+}
+
+// Get - значение характеристики, которую прокачивают задания типа attr
+func (s Stats) Get(attr TaskType) int {
+	switch attr {
+	case TypeStrength:
+		return s.Strength
+	case TypeAgility:
+		return s.Agility
+	case TypeIntelligence:
+		return s.Intelligence
+	case TypeInsight:
+		return s.Insight
+	default:
+		return 0
+	}
+}
+
+// IsZero - прибавки нет
+func (s Stats) IsZero() bool {
+	return s == Stats{}
+}
+
+// Item - товар из каталога магазина
+type Item struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Icon        string   `json:"icon"`
+	Kind        ItemKind `json:"kind"`
+	Rarity      Rarity   `json:"rarity"`
+	// Цена в магазине; 0 - не продается, только выпадает
+	Price int `json:"price"`
+	// Откуда предмет может выпасть; пусто - только из магазина
+	Drops []DropSource `json:"drops,omitempty"`
+
+	// Расходники
+	Effect  ItemEffect `json:"effect,omitempty"`
+	Amount  int        `json:"amount,omitempty"`
+	Minutes int        `json:"minutes,omitempty"`
+
+	// Снаряжение
+	Slot  EquipmentSlot `json:"slot,omitempty"`
+	Stats Stats         `json:"stats"`
+}
+
+// ParseItemCatalog - каталог магазина из JSON с проверкой товаров
+func ParseItemCatalog(data []byte) ([]*Item, error) {
+	var items []*Item
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("каталог магазина: %w", err)
+	}
+
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		if item.Rarity == "" {
+			item.Rarity = RarityCommon
+		}
+		if err := item.validate(); err != nil {
+			return nil, fmt.Errorf("предмет %q: %w", item.ID, err)
+		}
+		if seen[item.ID] {
+			return nil, fmt.Errorf("предмет %q описан дважды", item.ID)
+		}
+		seen[item.ID] = true
+	}
+	return items, nil
+}
+
+func (i *Item) validate() error {
+	switch {
+	case i.ID == "" || i.Name == "":
+		return fmt.Errorf("нужны id и name")
+	case i.Price < 0:
+		return fmt.Errorf("цена не может быть отрицательной")
+	case i.Price == 0 && len(i.Drops) == 0:
+		return fmt.Errorf("предмет нельзя ни купить, ни получить")
+	}
+
+	if _, ok := rarities[i.Rarity]; !ok {
+		return fmt.Errorf("неизвестная rarity %q", i.Rarity)
+	}
+	for _, source := range i.Drops {
+		switch source {
+		case DropUrgent, DropRaid, DropBoss:
+		default:
+			return fmt.Errorf("неизвестный источник %q в drops", source)
+		}
+	}
+
+	switch i.Kind {
+	case ItemConsumable:
+		switch i.Effect {
+		case EffectEnergy:
+			if i.Amount <= 0 {
+				return fmt.Errorf("amount должен быть больше нуля")
+			}
+		case EffectXPBoost:
+			if i.Amount <= 0 || i.Minutes <= 0 {
+				return fmt.Errorf("amount и minutes должны быть больше нуля")
+			}
+		case EffectRaidShield:
+			if i.Minutes <= 0 {
+				return fmt.Errorf("minutes должен быть больше нуля")
+			}
+		case EffectStreakFreeze:
+		default:
+			return fmt.Errorf("неизвестный effect %q", i.Effect)
+		}
+		return nil
+	case ItemEquipment:
+		if !i.Slot.valid() {
+			return fmt.Errorf("неизвестный slot %q", i.Slot)
+		}
+		if i.Stats.IsZero() {
+			return fmt.Errorf("снаряжение без прибавки к характеристикам")
+		}
+		if i.Stats.Strength < 0 || i.Stats.Agility < 0 || i.Stats.Intelligence < 0 || i.Stats.Insight < 0 {
+			return fmt.Errorf("прибавка не может быть отрицательной")
+		}
+		return nil
+	default:
+		return fmt.Errorf("неизвестный kind %q", i.Kind)
+	}
+}
+
+func (s EquipmentSlot) valid() bool {
+	for _, slot := range EquipmentSlots {
+		if slot == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ForSale - продается ли предмет в магазине
+func (i *Item) ForSale() bool {
+	return i.Price > 0
+}
+
+// DropsFrom - может ли предмет выпасть из source
+func (i *Item) DropsFrom(source DropSource) bool {
+	for _, s := range i.Drops {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
+
+// RollDrop - с шансом chance выбирает предмет, выпадающий из source;
+// редкие предметы выпадают реже. nil - ничего не выпало
+func RollDrop(catalog []*Item, source DropSource, chance float64, rng *rand.Rand) *Item {
+	if rng.Float64() >= chance {
+		return nil
+	}
+
+	var candidates []*Item
+	total := 0
+	for _, item := range catalog {
+		if item.DropsFrom(source) {
+			candidates = append(candidates, item)
+			total += rarities[item.Rarity].weight
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+
+	roll := rng.Intn(total)
+	for _, item := range candidates {
+		roll -= rarities[item.Rarity].weight
+		if roll < 0 {
+			return item
+		}
+	}
+	return nil
+}
+
+// Duration - сколько действует расходник
+func (i *Item) Duration() time.Duration {
+	return time.Duration(i.Minutes) * time.Minute
+}
+
+// InventoryItem - предметы одного вида в инвентаре игрока
+type InventoryItem struct {
+	ID       int64  `json:"id" gorm:"primaryKey"`
+	UserID   int64  `json:"-" gorm:"not null;uniqueIndex:idx_inventory_items"`
+	ItemID   string `json:"item_id" gorm:"not null;uniqueIndex:idx_inventory_items"`
+	Quantity int    `json:"quantity" gorm:"not null"`
+	Equipped bool   `json:"equipped" gorm:"not null"`
+
+	// Version - оптимистическая блокировка: растет при каждом сохранении
+	Version   int64     `json:"-" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"-"`
+
+	// Товар каталога; заполняет сервис
+	Item *Item `json:"item,omitempty" gorm:"-"`
+}
+
+// NewInventoryItem - пустая ячейка инвентаря под предмет
+func NewInventoryItem(userID int64, item *Item) *InventoryItem {
+	return &InventoryItem{
+		UserID: userID,
+		ItemID: item.ID,
+		Item:   item,
+	}
+}
+
+// Take - забирает один предмет из ячейки
+func (i *InventoryItem) Take() error {
+	if i.Quantity <= 0 {
+		return ErrItemNotOwned
+	}
+	i.Quantity--
+	return nil
+}
+
+// Loot - кладет в ячейку выпавший предмет. Второй такой же предмет снаряжения
+// или расходник сверх maxStack обмениваются на золото: возвращает, сколько
+// золота за него дают; 0 - предмет лег в ячейку
+func (i *InventoryItem) Loot(item *Item, maxStack int) int {
+	if (item.Kind == ItemEquipment && i.Quantity > 0) || i.Quantity >= maxStack {
+		return item.Rarity.SalvageGold()
+	}
+	i.Quantity++
+	return 0
+}
+
+// UseItem - применяет расходник к игроку. Журнал ведется по текущей причине
+func (u *User) UseItem(item *Item, now time.Time, energy EnergyConfig, streaks StreakRules) error {
+	if item.Kind != ItemConsumable {
+		return ErrItemNotUsable
+	}
+
+	switch item.Effect {
+	case EffectEnergy:
+		u.RegenerateEnergy(now, energy)
+		if u.Energy >= u.MaxEnergy {
+			return ErrEnergyFull
+		}
+		u.RestoreEnergy(item.Amount)
+	case EffectXPBoost:
+		return u.boostXP(item.Amount, item.Duration(), now)
+	case EffectStreakFreeze:
+		if u.StreakFreezes >= streaks.MaxFreezes {
+			return ErrTooManyStreakFreezes
+		}
+		u.StreakFreezes++
+	case EffectRaidShield:
+		until := now.Add(item.Duration())
+		if u.RaidShieldUntil == nil || until.After(*u.RaidShieldUntil) {
+			u.RaidShieldUntil = &until
+		}
+	default:
+		return ErrItemNotUsable
+	}
+	return nil
+}
+
+// XPBoost - действующая прибавка к опыту за задания в процентах
+func (u *User) XPBoost(now time.Time) int {
+	if u.XPBoostUntil == nil || !now.Before(*u.XPBoostUntil) {
+		return 0
+	}
+	return u.XPBoostPercent
+}
+
+// boostXP - свиток той же силы продлевает действующий, более сильный
+// заменяет его, а более слабый поверх сильного не читается
+func (u *User) boostXP(percent int, duration time.Duration, now time.Time) error {
+	current := u.XPBoost(now)
+	if current > percent {
+		return ErrXPBoostActive
+	}
+
+	start := now
+	if current == percent {
+		start = *u.XPBoostUntil
+	}
+	until := start.Add(duration)
+	u.XPBoostPercent = percent
+	u.XPBoostUntil = &until
+	return nil
+}
+
+// HasRaidShield - игрок под щитом от рейдов
+func (u *User) HasRaidShield(now time.Time) bool {
+	return u.RaidShieldUntil != nil && now.Before(*u.RaidShieldUntil)
+}
+
+// DropRaidShield - нападая, игрок теряет свой щит
+func (u *User) DropRaidShield() {
+	u.RaidShieldUntil = nil
+}
+
+// BaseStats - характеристики, прокачанные заданиями
+func (u *User) BaseStats() Stats {
+	return Stats{
+		Strength:     u.Strength,
+		Agility:      u.Agility,
+		Intelligence: u.Intelligence,
+		Insight:      u.Insight,
+	}
+}
+
+// EffectiveStats - характеристики вместе с надетым снаряжением
+func (u *User) EffectiveStats() Stats {
+	return u.BaseStats().Add(u.Gear)
+}
+
+// Прибавка к наградам за задания от снаряжения: процентов за очко
+// соответствующей характеристики и потолок
+const (
+	GearBonusPerPoint = 2
+	MaxGearBonus      = 50
+)
+
+// GearBonus - прибавка снаряжения к наградам за задания типа attr, в процентах
+func (u *User) GearBonus(attr TaskType) int {
+	return min(u.Gear.Get(attr)*GearBonusPerPoint, MaxGearBonus)
+}
+
+// GearOf - суммарная прибавка надетых предметов
+func GearOf(inventory []*InventoryItem) Stats {
+	var gear Stats
+	for _, slot := range inventory {
+		if slot.Equipped && slot.Quantity > 0 && slot.Item != nil {
+			gear = gear.Add(slot.Item.Stats)
+		}
+	}
+	return gear
+}
@@ -0,0 +1,151 @@
+// internal/domain/item_test.go
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUseItem(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	in := func(minutes int) *time.Time {
+		at := now.Add(time.Duration(minutes) * time.Minute)
+		return &at
+	}
+	streaks := StreakRules{MaxFreezes: 2}
+
+	potion := &Item{Kind: ItemConsumable, Effect: EffectEnergy, Amount: 30}
+	scroll := func(percent int) *Item {
+		return &Item{Kind: ItemConsumable, Effect: EffectXPBoost, Amount: percent, Minutes: 60}
+	}
+	freeze := &Item{Kind: ItemConsumable, Effect: EffectStreakFreeze}
+	shield := &Item{Kind: ItemConsumable, Effect: EffectRaidShield, Minutes: 60}
+	katana := &Item{Kind: ItemEquipment, Slot: SlotWeapon}
+
+	for _, tc := range []struct {
+		name string
+		user User
+		item *Item
+		err  error
+		want func(u *User) bool
+	}{
+		{
+			name: "energy restored up to max",
+			user: User{Energy: 80, MaxEnergy: 100, LastEnergyUpdate: now},
+			item: potion,
+			want: func(u *User) bool { return u.Energy == 100 },
+		},
+		{
+			name: "energy already full",
+			user: User{Energy: 100, MaxEnergy: 100, LastEnergyUpdate: now},
+			item: potion,
+			err:  ErrEnergyFull,
+		},
+		{
+			// 20 минут регенерации по 0.2 в минуту добивают энергию до полной
+			name: "energy full after regeneration",
+			user: User{Level: 1, Energy: 96, MaxEnergy: 100, LastEnergyUpdate: now.Add(-20 * time.Minute)},
+			item: potion,
+			err:  ErrEnergyFull,
+		},
+		{
+			name: "xp scroll without boost",
+			user: User{},
+			item: scroll(20),
+			want: func(u *User) bool { return u.XPBoostPercent == 20 && u.XPBoostUntil.Equal(*in(60)) },
+		},
+		{
+			name: "same xp scroll extends",
+			user: User{XPBoostPercent: 20, XPBoostUntil: in(30)},
+			item: scroll(20),
+			want: func(u *User) bool { return u.XPBoostPercent == 20 && u.XPBoostUntil.Equal(*in(90)) },
+		},
+		{
+			name: "stronger xp scroll replaces",
+			user: User{XPBoostPercent: 20, XPBoostUntil: in(30)},
+			item: scroll(50),
+			want: func(u *User) bool { return u.XPBoostPercent == 50 && u.XPBoostUntil.Equal(*in(60)) },
+		},
+		{
+			name: "weaker xp scroll refused",
+			user: User{XPBoostPercent: 50, XPBoostUntil: in(30)},
+			item: scroll(20),
+			err:  ErrXPBoostActive,
+		},
+		{
+			name: "weaker xp scroll after boost expired",
+			user: User{XPBoostPercent: 50, XPBoostUntil: in(-1)},
+			item: scroll(20),
+			want: func(u *User) bool { return u.XPBoostPercent == 20 && u.XPBoostUntil.Equal(*in(60)) },
+		},
+		{
+			name: "freeze below cap",
+			user: User{StreakFreezes: 1},
+			item: freeze,
+			want: func(u *User) bool { return u.StreakFreezes == 2 },
+		},
+		{
+			name: "freeze at cap",
+			user: User{StreakFreezes: 2},
+			item: freeze,
+			err:  ErrTooManyStreakFreezes,
+		},
+		{
+			name: "shield extends a shorter one",
+			user: User{RaidShieldUntil: in(30)},
+			item: shield,
+			want: func(u *User) bool { return u.RaidShieldUntil.Equal(*in(60)) },
+		},
+		{
+			name: "shield never shortens a longer one",
+			user: User{RaidShieldUntil: in(120)},
+			item: shield,
+			want: func(u *User) bool { return u.RaidShieldUntil.Equal(*in(120)) },
+		},
+		{
+			name: "equipment is not usable",
+			user: User{},
+			item: katana,
+			err:  ErrItemNotUsable,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			u := tc.user
+			err := u.UseItem(tc.item, now, DefaultEnergyConfig(), streaks)
+			if err != tc.err {
+				t.Fatalf("err = %v, want %v", err, tc.err)
+			}
+			if tc.want != nil && !tc.want(&u) {
+				t.Errorf("unexpected state: %+v", u)
+			}
+		})
+	}
+}
+
+func TestLoot(t *testing.T) {
+	katana := &Item{Kind: ItemEquipment, Slot: SlotWeapon, Rarity: RarityRare}
+	potion := &Item{Kind: ItemConsumable, Effect: EffectEnergy, Amount: 30, Rarity: RarityCommon}
+
+	for _, tc := range []struct {
+		name         string
+		item         *Item
+		owned        int
+		wantSalvage  int
+		wantQuantity int
+	}{
+		{name: "first equipment", item: katana, owned: 0, wantQuantity: 1},
+		{name: "second equipment salvaged", item: katana, owned: 1, wantSalvage: RarityRare.SalvageGold(), wantQuantity: 1},
+		{name: "consumable stacks", item: potion, owned: 4, wantQuantity: 5},
+		{name: "consumable over stack salvaged", item: potion, owned: 5, wantSalvage: RarityCommon.SalvageGold(), wantQuantity: 5},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			slot := &InventoryItem{Quantity: tc.owned}
+			if salvage := slot.Loot(tc.item, 5); salvage != tc.wantSalvage {
+				t.Errorf("salvage = %d, want %d", salvage, tc.wantSalvage)
+			}
+			if slot.Quantity != tc.wantQuantity {
+				t.Errorf("quantity = %d, want %d", slot.Quantity, tc.wantQuantity)
+			}
+		})
+	}
+}
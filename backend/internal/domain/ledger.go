@@ -0,0 +1,69 @@
+// internal/domain/ledger.go
+package domain
+
+import "time"
+
+// LedgerResource - что изменилось у игрока
+type LedgerResource string
+
+const (
+	ResourceXP             LedgerResource = "xp"
+	ResourceGold           LedgerResource = "gold"
+	ResourceEnergy         LedgerResource = "energy"
+	ResourceSenseiRequests LedgerResource = "sensei_requests"
+)
+
+// LedgerResources - все ресурсы, которые ведет журнал
+var LedgerResources = []LedgerResource{ResourceXP, ResourceGold, ResourceEnergy, ResourceSenseiRequests}
+
+// LedgerReason - почему изменился баланс
+type LedgerReason string
+
+const (
+	ReasonOpeningBalance LedgerReason = "opening_balance" // остаток на момент появления журнала
+	ReasonSignup         LedgerReason = "signup"
+	ReasonTaskCreated    LedgerReason = "task_created"
+	ReasonTaskStarted    LedgerReason = "task_started"
+	ReasonTaskCompleted  LedgerReason = "task_completed"
+	ReasonUrgentDeclined LedgerReason = "urgent_declined"
+	ReasonTaskExpired    LedgerReason = "task_expired"
+	ReasonPenaltyDrain   LedgerReason = "penalty_drain"
+	ReasonEnergyRegen    LedgerReason = "energy_regen"
+	ReasonEnergyRefill   LedgerReason = "energy_refill"
+	ReasonStreakFreeze   LedgerReason = "streak_freeze"
+	ReasonAchievement    LedgerReason = "achievement"
+	ReasonShopPurchase   LedgerReason = "shop_purchase"
+	ReasonItemUsed       LedgerReason = "item_used"
+	ReasonItemSalvage    LedgerReason = "item_salvage"
+	ReasonRaidCost       LedgerReason = "raid_cost"
+	ReasonRaidLoot       LedgerReason = "raid_loot"
+	ReasonRaided         LedgerReason = "raided"
+	ReasonRaidDefeat     LedgerReason = "raid_defeat"
+	ReasonLevelUp        LedgerReason = "level_up"
+	ReasonSenseiRequest  LedgerReason = "sensei_request"
+	ReasonSenseiRefund   LedgerReason = "sensei_refund"
+	ReasonSenseiReset    LedgerReason = "sensei_reset"
+	ReasonSenseiPurchase LedgerReason = "sensei_purchase"
+	ReasonOther          LedgerReason = "other"
+)
+
+// LedgerEntry - запись журнала: одно изменение одного ресурса. Записи только добавляются
+type LedgerEntry struct {
+	ID       int64          `json:"id" gorm:"primaryKey"`
+	UserID   int64          `json:"-" gorm:"not null;index"`
+	Resource LedgerResource `json:"resource" gorm:"not null"`
+	Reason   LedgerReason   `json:"reason" gorm:"not null"`
+	// ID задания, рейда и т.п. в зависимости от Reason; 0 - нет
+	RefID        int64     `json:"ref_id,omitempty" gorm:"not null"`
+	Delta        int       `json:"delta" gorm:"not null"`
+	BalanceAfter int       `json:"balance_after" gorm:"not null"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// LedgerDiscrepancy - баланс игрока не сходится с суммой его записей
+type LedgerDiscrepancy struct {
+	UserID    int64          `json:"user_id"`
+	Resource  LedgerResource `json:"resource"`
+	Balance   int            `json:"balance"`
+	LedgerSum int            `json:"ledger_sum"`
+}
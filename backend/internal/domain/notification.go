@@ -0,0 +1,112 @@
+// internal/domain/notification.go
+package domain
+
+import "time"
+
+type NotificationKind string
+
+const (
+	NotificationUrgentReminder NotificationKind = "urgent_reminder"
+	NotificationTaskExpired    NotificationKind = "task_expired"
+	NotificationLevelUp        NotificationKind = "level_up"
+	NotificationLicenseExpired NotificationKind = "license_expired"
+	NotificationRaided         NotificationKind = "raided"
+	NotificationPenaltyZone    NotificationKind = "penalty_zone"
+	NotificationPenaltyCleared NotificationKind = "penalty_cleared"
+	NotificationAchievement    NotificationKind = "achievement"
+	NotificationItemDrop       NotificationKind = "item_drop"
+)
+
+type NotificationStatus string
+
+const (
+	NotificationPending NotificationStatus = "pending"
+	NotificationSent    NotificationStatus = "sent"
+	NotificationSkipped NotificationStatus = "skipped"
+	NotificationFailed  NotificationStatus = "failed"
+)
+
+// MaxNotificationAttempts - после стольких ошибок доставки уведомление бросаем
+const MaxNotificationAttempts = 5
+
+// Notification - уведомление игроку, очередь доставки
+type Notification struct {
+	ID     int64            `json:"id" gorm:"primaryKey"`
+	UserID int64            `json:"user_id" gorm:"not null;uniqueIndex:idx_notifications_dedup"`
+	Kind   NotificationKind `json:"kind" gorm:"not null"`
+
+	// Ключ дедупликации: одно и то же событие не уведомляем дважды
+	DedupKey string `json:"-" gorm:"not null;uniqueIndex:idx_notifications_dedup"`
+
+	Text     string             `json:"text" gorm:"not null"`
+	Status   NotificationStatus `json:"status" gorm:"index;default:pending"`
+	Attempts int                `json:"attempts" gorm:"default:0"`
+
+	// После этого момента уведомление теряет смысл (напоминание о прошедшем дедлайне)
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// Раньше не отправлять: так отложенное на тихие часы не задерживает остальную очередь
+	NextAttemptAt time.Time `json:"-" gorm:"not null"`
+
+	CreatedAt time.Time  `json:"created_at"`
+	SentAt    *time.Time `json:"sent_at,omitempty"`
+}
+
+// IsStale - уведомление устарело
+func (n *Notification) IsStale(now time.Time) bool {
+	return n.ExpiresAt != nil && now.After(*n.ExpiresAt)
+}
+
+// NotificationSettings - настройки уведомлений игрока
+type NotificationSettings struct {
+	UserID  int64 `json:"-" gorm:"primaryKey;autoIncrement:false"`
+	Enabled bool  `json:"enabled" gorm:"not null"`
+
+	// Тихие часы [QuietFrom, QuietTo), часы 0-23, могут переходить через полночь
+	// Без gorm default: нулевые значения (выключено, 0 часов) должны сохраняться как есть
+	QuietHoursEnabled bool `json:"quiet_hours_enabled" gorm:"not null"`
+	QuietFrom         int  `json:"quiet_from" gorm:"not null"`
+	QuietTo           int  `json:"quiet_to" gorm:"not null"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DefaultNotificationSettings - настройки для игрока, который их не менял
+func DefaultNotificationSettings(userID int64) *NotificationSettings {
+	return &NotificationSettings{
+		UserID:    userID,
+		Enabled:   true,
+		QuietFrom: 23,
+		QuietTo:   8,
+	}
+}
+
+// Validate - проверка часов
+func (s *NotificationSettings) Validate() error {
+	if s.QuietFrom < 0 || s.QuietFrom > 23 || s.QuietTo < 0 || s.QuietTo > 23 {
+		return ErrInvalidQuietHours
+	}
+	return nil
+}
+
+// IsQuietAt - попадает ли момент в тихие часы
+func (s *NotificationSettings) IsQuietAt(t time.Time) bool {
+	if !s.QuietHoursEnabled || s.QuietFrom == s.QuietTo {
+		return false
+	}
+
+	hour := t.Hour()
+	if s.QuietFrom < s.QuietTo {
+		return hour >= s.QuietFrom && hour < s.QuietTo
+	}
+	// Через полночь, например 23 -> 8
+	return hour >= s.QuietFrom || hour < s.QuietTo
+}
+
+// QuietUntil - когда закончатся тихие часы, идущие в момент t (по часам t)
+func (s *NotificationSettings) QuietUntil(t time.Time) time.Time {
+	end := time.Date(t.Year(), t.Month(), t.Day(), s.QuietTo, 0, 0, 0, t.Location())
+	if !end.After(t) {
+		end = time.Date(t.Year(), t.Month(), t.Day()+1, s.QuietTo, 0, 0, 0, t.Location())
+	}
+	return end
+}
@@ -0,0 +1,134 @@
+// internal/domain/outbox.go
+package domain
+
+import (
+	"time"
+)
+
+// OutboxEvent - доменное событие в исходящей очереди. Пишется в той же транзакции,
+// что и изменение; релей потом раскладывает его по подписчикам
+type OutboxEvent struct {
+	ID      int64     `json:"id" gorm:"primaryKey"`
+	Type    EventType `json:"type" gorm:"not null"`
+	UserID  int64     `json:"user_id" gorm:"not null;index"`
+	Payload Event     `json:"payload" gorm:"type:jsonb;serializer:json;not null"`
+	// Когда для события созданы доставки; nil - еще не разослано
+	DispatchedAt *time.Time `json:"dispatched_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// NewOutboxEvent - событие для сохранения в outbox
+func NewOutboxEvent(event Event) *OutboxEvent {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+	return &OutboxEvent{
+		Type:    event.Type,
+		UserID:  event.UserID,
+		Payload: event,
+	}
+}
+
+// Event - событие вместе с его номером в outbox
+func (o *OutboxEvent) Event() Event {
+	event := o.Payload
+	event.ID = o.ID
+	return event
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	// Все попытки исчерпаны: доставка ждет разбора в мертвой очереди
+	DeliveryDead DeliveryStatus = "dead"
+)
+
+// DeliveryPolicy - повторы доставки: пауза удваивается с каждой ошибкой
+type DeliveryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+func DefaultDeliveryPolicy() DeliveryPolicy {
+	return DeliveryPolicy{
+		MaxAttempts: 10,
+		BaseDelay:   10 * time.Second,
+		MaxDelay:    time.Hour,
+	}
+}
+
+// Backoff - пауза перед попыткой номер attempt+1
+func (p DeliveryPolicy) Backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, p.MaxDelay)
+}
+
+// EventDelivery - доставка одного события одному подписчику. Доставки
+// независимы: ошибка вебхука не задерживает достижения
+type EventDelivery struct {
+	ID            int64          `json:"id" gorm:"primaryKey"`
+	EventID       int64          `json:"event_id" gorm:"not null;uniqueIndex:idx_event_deliveries"`
+	Subscriber    string         `json:"subscriber" gorm:"not null;uniqueIndex:idx_event_deliveries"`
+	Status        DeliveryStatus `json:"status" gorm:"not null;default:pending"`
+	Attempts      int            `json:"attempts" gorm:"not null"`
+	NextAttemptAt time.Time      `json:"next_attempt_at" gorm:"not null"`
+	LastError     string         `json:"last_error,omitempty"`
+	DeliveredAt   *time.Time     `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+
+	Event *OutboxEvent `json:"event,omitempty"`
+}
+
+// NewEventDelivery - доставка, которую можно выполнить сразу
+func NewEventDelivery(eventID int64, subscriber string, now time.Time) *EventDelivery {
+	return &EventDelivery{
+		EventID:       eventID,
+		Subscriber:    subscriber,
+		Status:        DeliveryPending,
+		NextAttemptAt: now,
+	}
+}
+
+// Delivered - подписчик обработал событие
+func (d *EventDelivery) Delivered(now time.Time) {
+	d.Status = DeliveryDelivered
+	d.Attempts++
+	d.DeliveredAt = &now
+	d.LastError = ""
+}
+
+// Fail - попытка не удалась: повторим позже или, если попытки кончились, в мертвую очередь
+func (d *EventDelivery) Fail(err error, now time.Time, policy DeliveryPolicy) {
+	d.Attempts++
+	d.LastError = err.Error()
+	if d.Attempts >= policy.MaxAttempts {
+		d.Status = DeliveryDead
+		return
+	}
+	d.NextAttemptAt = now.Add(policy.Backoff(d.Attempts))
+}
+
+// Requeue - вернуть доставку из мертвой очереди с новым запасом попыток
+func (d *EventDelivery) Requeue(now time.Time) error {
+	if d.Status != DeliveryDead {
+		return ErrDeliveryNotDead
+	}
+	d.Status = DeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = now
+	return nil
+}
+
+// EventStat - сколько событий типа случилось за день (UTC)
+type EventStat struct {
+	Day       string    `json:"day" gorm:"primaryKey"`
+	EventType EventType `json:"event_type" gorm:"primaryKey"`
+	Count     int64     `json:"count" gorm:"not null"`
+}
@@ -0,0 +1,110 @@
+// internal/domain/penalty.go
+package domain
+
+import "time"
+
+// PenaltyRules - правила зоны наказания за невыполненные ежедневные квесты
+type PenaltyRules struct {
+	// Как часто зона отнимает ресурсы и сколько за раз.
+	// Сначала уходит энергия; когда ее не хватает, недостающая доля берется золотом
+	DrainInterval time.Duration
+	DrainEnergy   int
+	DrainGold     int
+
+	// Штрафной квест: пока он не выполнен, игрок остается в зоне
+	QuestTitle       string
+	QuestDescription string
+	QuestType        TaskType
+}
+
+func DefaultPenaltyRules() PenaltyRules {
+	return PenaltyRules{
+		DrainInterval:    time.Hour,
+		DrainEnergy:      10,
+		DrainGold:        10,
+		QuestTitle:       "Зона наказания: выживание",
+		QuestDescription: "Ежедневный квест провален. 100 отжиманий, 100 приседаний, 100 скручиваний и 10 км бега - без этого из зоны не выйти.",
+		QuestType:        TypeStrength,
+	}
+}
+
+// NewPenaltyQuest - штрафной квест: без наград и без цены, чтобы его можно было
+// выполнить даже с пустой шкалой энергии
+func NewPenaltyQuest(userID int64, rules PenaltyRules) *Task {
+	return &Task{
+		UserID:      userID,
+		Title:       rules.QuestTitle,
+		Description: rules.QuestDescription,
+		TaskType:    rules.QuestType,
+		Frequency:   FrequencyPenalty,
+		Status:      TaskStatusActive,
+		XPReward:    0,
+		GoldReward:  0,
+		StatBoost:   0,
+		EnergyCost:  0,
+		GoldCost:    0,
+	}
+}
+
+// InPenaltyZone - назначен ли игроку штрафной квест
+func (u *User) InPenaltyZone() bool {
+	return u.PenaltyTaskID != nil
+}
+
+// EnterPenaltyZone - отправляет игрока в зону до выполнения штрафного квеста
+func (u *User) EnterPenaltyZone(taskID int64, now time.Time) {
+	u.PenaltyTaskID = &taskID
+	u.PenaltySince = &now
+	u.PenaltyDrainedAt = &now
+}
+
+// LeavePenaltyZone - штрафной квест выполнен
+func (u *User) LeavePenaltyZone() {
+	u.PenaltyTaskID = nil
+	u.PenaltySince = nil
+	u.PenaltyDrainedAt = nil
+}
+
+// CheckPenalty - в зоне наказания награды дает только штрафной квест
+func (u *User) CheckPenalty(task *Task) error {
+	if u.InPenaltyZone() && task.ID != *u.PenaltyTaskID {
+		return ErrInPenaltyZone
+	}
+	return nil
+}
+
+// NextPenaltyDrain - когда зона в следующий раз отнимет ресурсы; nil вне зоны
+func (u *User) NextPenaltyDrain(rules PenaltyRules) *time.Time {
+	if !u.InPenaltyZone() || u.PenaltyDrainedAt == nil {
+		return nil
+	}
+	at := u.PenaltyDrainedAt.Add(rules.DrainInterval)
+	return &at
+}
+
+// DrainPenalty - отнимает ресурсы за каждый прошедший интервал. Возвращает потери
+func (u *User) DrainPenalty(now time.Time, rules PenaltyRules) (energy, gold int) {
+	next := u.NextPenaltyDrain(rules)
+	if next == nil || rules.DrainInterval <= 0 || now.Before(*next) {
+		return 0, 0
+	}
+
+	ticks := int(now.Sub(*u.PenaltyDrainedAt) / rules.DrainInterval)
+	drainedAt := u.PenaltyDrainedAt.Add(time.Duration(ticks) * rules.DrainInterval)
+	u.PenaltyDrainedAt = &drainedAt
+
+	u.Because(ReasonPenaltyDrain, *u.PenaltyTaskID)
+	for i := 0; i < ticks; i++ {
+		lost := min(rules.DrainEnergy, u.Energy)
+		u.LoseEnergy(lost)
+		energy += lost
+
+		// Золото - за недостающую энергию: без энергии вовсе берется вся ставка
+		if lost < rules.DrainEnergy && u.Gold > 0 {
+			before := u.Gold
+			u.AddGold(-rules.DrainGold * (rules.DrainEnergy - lost) / rules.DrainEnergy)
+			gold += before - u.Gold
+		}
+	}
+	return energy, gold
+}
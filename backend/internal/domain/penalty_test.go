@@ -0,0 +1,42 @@
+// internal/domain/penalty_test.go
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDrainPenalty(t *testing.T) {
+	rules := DefaultPenaltyRules()
+	since := time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name               string
+		energy, gold       int
+		elapsed            time.Duration
+		wantEnergy         int
+		wantGold, leftGold int
+	}{
+		{name: "before interval", energy: 50, gold: 100, elapsed: 59 * time.Minute, leftGold: 100},
+		{name: "enough energy", energy: 50, gold: 100, elapsed: 2 * time.Hour, wantEnergy: 20, leftGold: 100},
+		// 4 из 10 энергии: золотом берется только недостающие 6/10 ставки
+		{name: "energy short", energy: 4, gold: 100, elapsed: time.Hour, wantEnergy: 4, wantGold: 6, leftGold: 94},
+		{name: "then no energy", energy: 4, gold: 100, elapsed: 2 * time.Hour, wantEnergy: 4, wantGold: 16, leftGold: 84},
+		{name: "gold runs out", energy: 0, gold: 15, elapsed: 3 * time.Hour, wantGold: 15},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			drainedAt := since
+			u := &User{Energy: tc.energy, MaxEnergy: 100, Gold: tc.gold}
+			u.EnterPenaltyZone(1, since)
+			u.PenaltyDrainedAt = &drainedAt
+
+			energy, gold := u.DrainPenalty(since.Add(tc.elapsed), rules)
+			if energy != tc.wantEnergy || gold != tc.wantGold {
+				t.Errorf("drained %d energy, %d gold; want %d, %d", energy, gold, tc.wantEnergy, tc.wantGold)
+			}
+			if u.Gold != tc.leftGold {
+				t.Errorf("gold left = %d, want %d", u.Gold, tc.leftGold)
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+// internal/domain/quest.go
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QuestDateLayout - формат дня квеста в часовом поясе игрока
+const QuestDateLayout = "2006-01-02"
+
+type RecurrenceFreq string
+
+const (
+	RecurrenceDaily  RecurrenceFreq = "DAILY"
+	RecurrenceWeekly RecurrenceFreq = "WEEKLY"
+)
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Recurrence - расписание повтора, подмножество RRULE:
+//
+//	FREQ=DAILY                        каждый день
+//	FREQ=DAILY;INTERVAL=3             раз в три дня
+//	FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR  по будням
+//	FREQ=WEEKLY;INTERVAL=2;BYDAY=SA   через субботу
+type Recurrence struct {
+	Freq     RecurrenceFreq
+	Interval int
+	ByDay    []time.Weekday
+}
+
+// ParseRecurrence - разбор правила; регистр и порядок частей не важны
+func ParseRecurrence(rule string) (Recurrence, error) {
+	r := Recurrence{Interval: 1}
+
+	for _, part := range strings.Split(strings.ToUpper(strings.TrimSpace(rule)), ";") {
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return Recurrence{}, ErrInvalidRecurrence
+		}
+
+		switch key {
+		case "FREQ":
+			r.Freq = RecurrenceFreq(value)
+			if r.Freq != RecurrenceDaily && r.Freq != RecurrenceWeekly {
+				return Recurrence{}, ErrInvalidRecurrence
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 || n > 365 {
+				return Recurrence{}, ErrInvalidRecurrence
+			}
+			r.Interval = n
+		case "BYDAY":
+			r.ByDay = nil
+			for _, code := range strings.Split(value, ",") {
+				day, ok := weekdayCodes[code]
+				if !ok {
+					return Recurrence{}, ErrInvalidRecurrence
+				}
+				r.ByDay = append(r.ByDay, day)
+			}
+		default:
+			return Recurrence{}, ErrInvalidRecurrence
+		}
+	}
+
+	if r.Freq == "" {
+		return Recurrence{}, ErrInvalidRecurrence
+	}
+	return r, nil
+}
+
+// String - правило в каноническом виде
+func (r Recurrence) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			for code, d := range weekdayCodes {
+				if d == day {
+					codes = append(codes, code)
+				}
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	return strings.Join(parts, ";")
+}
+
+// OccursOn - выпадает ли день day при отсчете интервалов от start.
+// Оба дня - календарные даты в поясе игрока (время суток не важно)
+func (r Recurrence) OccursOn(start, day time.Time) bool {
+	start, day = civilDay(start), civilDay(day)
+	if day.Before(start) {
+		return false
+	}
+
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	days := int(day.Sub(start).Hours() / 24)
+	switch r.Freq {
+	case RecurrenceDaily:
+		return days%interval == 0 && r.onDay(day.Weekday(), day.Weekday())
+	case RecurrenceWeekly:
+		// Недели считаем с понедельника, как в календаре
+		weeks := (days + mondayOffset(start)) / 7
+		return weeks%interval == 0 && r.onDay(day.Weekday(), start.Weekday())
+	}
+	return false
+}
+
+// onDay - без BYDAY подходит любой день для DAILY и день старта для WEEKLY
+func (r Recurrence) onDay(day, fallback time.Weekday) bool {
+	if len(r.ByDay) == 0 {
+		return day == fallback
+	}
+	for _, d := range r.ByDay {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// QuestTemplate - повторяющийся квест: по расписанию из него создаются ежедневные задания
+type QuestTemplate struct {
+	ID     int64 `json:"id" gorm:"primaryKey"`
+	UserID int64 `json:"user_id" gorm:"index;not null"`
+
+	Title       string   `json:"title" gorm:"not null"`
+	Description string   `json:"description"`
+	TaskType    TaskType `json:"task_type" gorm:"not null"`
+
+	// Правило повтора (Recurrence) и день, от которого считаются интервалы
+	Rule     string `json:"rule" gorm:"not null"`
+	StartsOn string `json:"starts_on" gorm:"not null"`
+
+	Active bool `json:"active" gorm:"not null"`
+	// Последний день, за который генератор уже отработал
+	LastRunOn string `json:"last_run_on,omitempty"`
+
+	// Серия выполнений подряд по расписанию
+	Streak          int    `json:"streak" gorm:"default:0"`
+	BestStreak      int    `json:"best_streak" gorm:"default:0"`
+	LastCompletedOn string `json:"last_completed_on,omitempty"`
+
+	// Version - оптимистическая блокировка: растет при каждом сохранении
+	Version   int64     `json:"-" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewQuestTemplate - шаблон, действующий с сегодняшнего дня игрока
+func NewQuestTemplate(userID int64, title, description string, taskType TaskType, rule Recurrence, today string) *QuestTemplate {
+	return &QuestTemplate{
+		UserID:      userID,
+		Title:       title,
+		Description: description,
+		TaskType:    taskType,
+		Rule:        rule.String(),
+		StartsOn:    today,
+		Active:      true,
+	}
+}
+
+// Recurrence - разобранное правило повтора
+func (q *QuestTemplate) Recurrence() (Recurrence, error) {
+	return ParseRecurrence(q.Rule)
+}
+
+// IsDue - нужно ли создавать задание за день today
+func (q *QuestTemplate) IsDue(today string) (bool, error) {
+	if !q.Active || q.LastRunOn >= today {
+		return false, nil
+	}
+
+	rule, err := q.Recurrence()
+	if err != nil {
+		return false, err
+	}
+	start, err := time.Parse(QuestDateLayout, q.StartsOn)
+	if err != nil {
+		return false, err
+	}
+	day, err := time.Parse(QuestDateLayout, today)
+	if err != nil {
+		return false, err
+	}
+
+	return rule.OccursOn(start, day), nil
+}
+
+// Spawn - задание на день today; день отмечается отработанным
+func (q *QuestTemplate) Spawn(today string) *Task {
+	q.LastRunOn = today
+
+	templateID := q.ID
+	task := NewDailyTask(q.UserID, q.Title, q.TaskType)
+	task.Description = q.Description
+	task.TemplateID = &templateID
+	task.QuestDate = today
+	return task
+}
+
+// Skip - день не по расписанию, отмечаем отработанным без задания
+func (q *QuestTemplate) Skip(today string) {
+	q.LastRunOn = today
+}
+
+// QuestDate - календарный день момента t в поясе loc
+func QuestDate(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format(QuestDateLayout)
+}
+
+func civilDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// mondayOffset - сколько дней прошло с понедельника недели t
+func mondayOffset(t time.Time) int {
+	return (int(t.Weekday()) + 6) % 7
+}
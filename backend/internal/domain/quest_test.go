@@ -0,0 +1,162 @@
+// internal/domain/quest_test.go
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func location(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("LoadLocation(%q): %v", name, err)
+	}
+	return loc
+}
+
+// at - момент "2006-01-02 15:04" в поясе tz
+func at(t *testing.T, tz, value string) time.Time {
+	t.Helper()
+
+	moment, err := time.ParseInLocation("2006-01-02 15:04", value, location(t, tz))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return moment
+}
+
+func TestParseRecurrence(t *testing.T) {
+	for _, tc := range []struct {
+		rule string
+		want string
+		err  bool
+	}{
+		{rule: "FREQ=DAILY", want: "FREQ=DAILY"},
+		{rule: " freq=daily;interval=3 ", want: "FREQ=DAILY;INTERVAL=3"},
+		{rule: "INTERVAL=2;BYDAY=SA;FREQ=WEEKLY", want: "FREQ=WEEKLY;INTERVAL=2;BYDAY=SA"},
+		{rule: "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", want: "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"},
+		{rule: "FREQ=DAILY;INTERVAL=1;", want: "FREQ=DAILY"},
+		{rule: "", err: true},
+		{rule: "INTERVAL=2", err: true},
+		{rule: "FREQ=MONTHLY", err: true},
+		{rule: "FREQ=DAILY;INTERVAL=0", err: true},
+		{rule: "FREQ=DAILY;INTERVAL=366", err: true},
+		{rule: "FREQ=DAILY;INTERVAL=x", err: true},
+		{rule: "FREQ=WEEKLY;BYDAY=MO,XX", err: true},
+		{rule: "FREQ=WEEKLY;COUNT=3", err: true},
+		{rule: "FREQ", err: true},
+	} {
+		t.Run(tc.rule, func(t *testing.T) {
+			r, err := ParseRecurrence(tc.rule)
+			if tc.err {
+				if err != ErrInvalidRecurrence {
+					t.Fatalf("err = %v, want ErrInvalidRecurrence", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := r.String(); got != tc.want {
+				t.Errorf("String() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestOccursOn(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		rule       string
+		start, day time.Time
+		want       bool
+	}{
+		{"daily on start", "FREQ=DAILY", at(t, "UTC", "2026-10-14 23:59"), at(t, "UTC", "2026-10-14 00:00"), true},
+		{"before start", "FREQ=DAILY", at(t, "UTC", "2026-10-14 00:00"), at(t, "UTC", "2026-10-13 23:59"), false},
+		{"every third day", "FREQ=DAILY;INTERVAL=3", at(t, "UTC", "2026-10-14 12:00"), at(t, "UTC", "2026-10-20 08:00"), true},
+		{"between third days", "FREQ=DAILY;INTERVAL=3", at(t, "UTC", "2026-10-14 12:00"), at(t, "UTC", "2026-10-19 08:00"), false},
+		{"daily by weekday", "FREQ=DAILY;BYDAY=MO,WE", at(t, "UTC", "2026-10-14 00:00"), at(t, "UTC", "2026-10-19 00:00"), true},
+		{"daily off weekday", "FREQ=DAILY;BYDAY=MO,WE", at(t, "UTC", "2026-10-14 00:00"), at(t, "UTC", "2026-10-18 00:00"), false},
+		{"weekly defaults to start weekday", "FREQ=WEEKLY", at(t, "UTC", "2026-10-14 00:00"), at(t, "UTC", "2026-10-21 00:00"), true},
+		{"weekly other weekday", "FREQ=WEEKLY", at(t, "UTC", "2026-10-14 00:00"), at(t, "UTC", "2026-10-22 00:00"), false},
+
+		// Через субботу от среды: первая суббота в неделе старта, дальше через неделю
+		{"biweekly saturday, start week", "FREQ=WEEKLY;INTERVAL=2;BYDAY=SA", at(t, "UTC", "2026-10-14 00:00"), at(t, "UTC", "2026-10-17 00:00"), true},
+		{"biweekly saturday, odd week", "FREQ=WEEKLY;INTERVAL=2;BYDAY=SA", at(t, "UTC", "2026-10-14 00:00"), at(t, "UTC", "2026-10-24 00:00"), false},
+		{"biweekly saturday, even week", "FREQ=WEEKLY;INTERVAL=2;BYDAY=SA", at(t, "UTC", "2026-10-14 00:00"), at(t, "UTC", "2026-10-31 00:00"), true},
+		// Старт в воскресенье: неделя старта началась в понедельник 12-го,
+		// поэтому суббота 24-го - уже следующая неделя, а не "шесть дней спустя"
+		{"biweekly saturday from sunday, next week", "FREQ=WEEKLY;INTERVAL=2;BYDAY=SA", at(t, "UTC", "2026-10-18 00:00"), at(t, "UTC", "2026-10-24 00:00"), false},
+		{"biweekly saturday from sunday, week after", "FREQ=WEEKLY;INTERVAL=2;BYDAY=SA", at(t, "UTC", "2026-10-18 00:00"), at(t, "UTC", "2026-10-31 00:00"), true},
+		{"biweekly monday from sunday", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO", at(t, "UTC", "2026-10-18 00:00"), at(t, "UTC", "2026-10-19 00:00"), false},
+		{"biweekly monday from monday", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO", at(t, "UTC", "2026-10-19 00:00"), at(t, "UTC", "2026-11-02 00:00"), true},
+
+		// Переход на летнее время: сутки 8 марта в Нью-Йорке длятся 23 часа
+		{"every other day over spring forward", "FREQ=DAILY;INTERVAL=2", at(t, "America/New_York", "2026-03-07 23:00"), at(t, "America/New_York", "2026-03-09 00:30"), true},
+		{"day after spring forward", "FREQ=DAILY;INTERVAL=2", at(t, "America/New_York", "2026-03-07 23:00"), at(t, "America/New_York", "2026-03-08 23:30"), false},
+		// Переход на зимнее: сутки 25 октября в Берлине длятся 25 часов
+		{"weekly over fall back", "FREQ=WEEKLY;BYDAY=SU", at(t, "Europe/Berlin", "2026-10-18 00:00"), at(t, "Europe/Berlin", "2026-10-25 23:59"), true},
+		{"biweekly over fall back", "FREQ=WEEKLY;INTERVAL=2;BYDAY=SU", at(t, "Europe/Berlin", "2026-10-18 00:00"), at(t, "Europe/Berlin", "2026-11-01 00:00"), true},
+
+		// Считаются календарные даты в поясе игрока, а не прошедшие часы
+		{"late start, early day", "FREQ=DAILY;INTERVAL=2", at(t, "Pacific/Auckland", "2026-10-14 23:50"), at(t, "Pacific/Auckland", "2026-10-16 00:10"), true},
+		{"same instant, different dates", "FREQ=WEEKLY;BYDAY=SA", at(t, "UTC", "2026-10-14 00:00"), at(t, "Asia/Tokyo", "2026-10-17 08:00").In(location(t, "America/Los_Angeles")), false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := ParseRecurrence(tc.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := r.OccursOn(tc.start, tc.day); got != tc.want {
+				t.Errorf("OccursOn(%s, %s) = %v, want %v", tc.start, tc.day, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestQuestDate(t *testing.T) {
+	moment := at(t, "UTC", "2026-03-08 23:30")
+
+	for tz, want := range map[string]string{
+		"UTC":                 "2026-03-08",
+		"Asia/Tokyo":          "2026-03-09",
+		"America/Los_Angeles": "2026-03-08",
+		"Pacific/Kiritimati":  "2026-03-09",
+		"Pacific/Pago_Pago":   "2026-03-08",
+	} {
+		if got := QuestDate(moment, location(t, tz)); got != want {
+			t.Errorf("QuestDate in %s = %s, want %s", tz, got, want)
+		}
+	}
+}
+
+func TestQuestTemplateIsDue(t *testing.T) {
+	template := &QuestTemplate{Rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=SA", StartsOn: "2026-10-14", Active: true}
+
+	for _, tc := range []struct {
+		today, lastRun string
+		want           bool
+	}{
+		{today: "2026-10-17", want: true},
+		{today: "2026-10-17", lastRun: "2026-10-17", want: false},
+		{today: "2026-10-24", lastRun: "2026-10-23", want: false},
+		{today: "2026-10-31", lastRun: "2026-10-30", want: true},
+	} {
+		template.LastRunOn = tc.lastRun
+		due, err := template.IsDue(tc.today)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if due != tc.want {
+			t.Errorf("IsDue(%s) after %q = %v, want %v", tc.today, tc.lastRun, due, tc.want)
+		}
+	}
+
+	template.LastRunOn = ""
+	template.Active = false
+	if due, _ := template.IsDue("2026-10-17"); due {
+		t.Error("inactive template is due")
+	}
+}
@@ -1 +1,64 @@
-package domain
\ No newline at end of file
+// internal/domain/raid.go
+package domain
+
+import "time"
+
+type RaidOutcome string
+
+const (
+	RaidOutcomeSuccess RaidOutcome = "success"
+	RaidOutcomeDefeat  RaidOutcome = "defeat"
+)
+
+// Raid - нападение на неактивного игрока
+type Raid struct {
+	ID         int64 `json:"id" gorm:"primaryKey"`
+	AttackerID int64 `json:"attacker_id" gorm:"index;not null"`
+	TargetID   int64 `json:"target_id" gorm:"index;not null"`
+
+	// Во что обошелся рейд атакующему и что он принес
+	Cost       int         `json:"cost" gorm:"default:0"`
+	GoldLooted int         `json:"gold_looted" gorm:"default:0"`
+	XPGained   int         `json:"xp_gained" gorm:"default:0"`
+	EnergyLost int         `json:"energy_lost" gorm:"default:0"`
+	Outcome    RaidOutcome `json:"outcome" gorm:"not null"`
+
+	// Бой целиком: сид, снимки бойцов и лог раундов для анимации
+	Battle *Battle `json:"battle,omitempty" gorm:"type:jsonb;serializer:json"`
+
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// NewRaid - рейд, начатый сейчас
+func NewRaid(attackerID, targetID int64, cost int) *Raid {
+	return &Raid{
+		AttackerID: attackerID,
+		TargetID:   targetID,
+		Cost:       cost,
+		StartedAt:  time.Now(),
+	}
+}
+
+// Finish - фиксирует исход боя и добычу
+func (r *Raid) Finish(battle *Battle, gold, xp int) {
+	r.Battle = battle
+	r.Outcome = RaidOutcomeDefeat
+	if battle.AttackerWon {
+		r.Outcome = RaidOutcomeSuccess
+	}
+	r.GoldLooted = gold
+	r.XPGained = xp
+	r.FinishedAt = time.Now()
+}
+
+// Succeeded - атакующий победил
+func (r *Raid) Succeeded() bool {
+	return r.Outcome == RaidOutcomeSuccess
+}
+
+// Involves - участвовал ли игрок в рейде с любой стороны
+func (r *Raid) Involves(userID int64) bool {
+	return r.AttackerID == userID || r.TargetID == userID
+}
@@ -0,0 +1,56 @@
+// internal/domain/sensei.go
+package domain
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	SenseiRoleUser      = "user"
+	SenseiRoleAssistant = "assistant"
+)
+
+// SenseiConversation - диалог игрока с Сенсеем
+type SenseiConversation struct {
+	ID     int64  `json:"id" gorm:"primaryKey"`
+	UserID int64  `json:"user_id" gorm:"index;not null"`
+	Title  string `json:"title"`
+
+	// Сжатое содержание старых реплик; реплики с ID <= SummarizedUntilID
+	// в промпт целиком уже не попадают
+	Summary           string `json:"summary,omitempty" gorm:"type:text"`
+	SummarizedUntilID int64  `json:"-" gorm:"default:0"`
+
+	MessageCount int       `json:"message_count" gorm:"default:0"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// SenseiMessage - одна реплика диалога
+type SenseiMessage struct {
+	ID             int64     `json:"id" gorm:"primaryKey"`
+	ConversationID int64     `json:"conversation_id" gorm:"index;not null"`
+	Role           string    `json:"role" gorm:"not null"`
+	Content        string    `json:"content" gorm:"type:text;not null"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// NewSenseiConversation - новый диалог, заголовок по первому вопросу
+func NewSenseiConversation(userID int64, firstMessage string) *SenseiConversation {
+	title := strings.TrimSpace(firstMessage)
+	if utf8.RuneCountInString(title) > 60 {
+		title = string([]rune(title)[:60]) + "…"
+	}
+
+	return &SenseiConversation{
+		UserID: userID,
+		Title:  title,
+	}
+}
+
+// BelongsTo - диалог принадлежит игроку
+func (c *SenseiConversation) BelongsTo(userID int64) bool {
+	return c.UserID == userID
+}
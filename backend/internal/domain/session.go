@@ -0,0 +1,27 @@
+// internal/domain/session.go
+package domain
+
+import "time"
+
+// Session - сессия входа, к которой привязан refresh-токен
+type Session struct {
+	ID        string     `json:"id" gorm:"primaryKey"`
+	UserID    int64      `json:"user_id" gorm:"index;not null"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// IsActive - сессия не отозвана и не истекла
+func (s *Session) IsActive() bool {
+	return s.RevokedAt == nil && time.Now().Before(s.ExpiresAt)
+}
+
+// Revoke - отзывает сессию
+func (s *Session) Revoke() {
+	if s.RevokedAt != nil {
+		return
+	}
+	now := time.Now()
+	s.RevokedAt = &now
+}
@@ -0,0 +1,205 @@
+// internal/domain/streak.go
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// StreakRules - серии дней подряд с выполненными заданиями и бонусы за них
+type StreakRules struct {
+	// Прибавка к множителю за каждый день серии после первого и потолок множителя
+	XPPerDay   float64
+	GoldPerDay float64
+	MaxBonus   float64
+
+	// Прибавка за серию выполнений одного повторяющегося квеста
+	QuestPerDay float64
+
+	// Заморозка закрывает один пропущенный день. Каждые FreezeEvery дней серии
+	// дают заморозку бесплатно; больше MaxFreezes не накопить
+	FreezeCost  int
+	FreezeEvery int
+	MaxFreezes  int
+}
+
+func DefaultStreakRules() StreakRules {
+	return StreakRules{
+		XPPerDay:    0.05,
+		GoldPerDay:  0.03,
+		MaxBonus:    1.0,
+		QuestPerDay: 0.02,
+		FreezeCost:  100,
+		FreezeEvery: 7,
+		MaxFreezes:  2,
+	}
+}
+
+// StreakBonus - множители наград за серии
+type StreakBonus struct {
+	XP   float64 `json:"xp"`
+	Gold float64 `json:"gold"`
+}
+
+// Bonus - множители для серии игрока и серии квеста (0, если задание не из шаблона)
+func (r StreakRules) Bonus(streak, questStreak int) StreakBonus {
+	days := float64(max(streak-1, 0))
+	quest := r.QuestPerDay * float64(max(questStreak-1, 0))
+
+	return StreakBonus{
+		XP:   1 + math.Min(r.XPPerDay*days+quest, r.MaxBonus),
+		Gold: 1 + math.Min(r.GoldPerDay*days+quest, r.MaxBonus),
+	}
+}
+
+// StreakStatus - серия игрока для профиля
+type StreakStatus struct {
+	Days    int `json:"days"`
+	Best    int `json:"best"`
+	Freezes int `json:"freezes"`
+	// Сегодня еще ничего не выполнено: серия сгорит (или съест заморозку) в RiskEndsAt
+	AtRisk     bool        `json:"at_risk"`
+	RiskEndsAt *time.Time  `json:"risk_ends_at,omitempty"`
+	Bonus      StreakBonus `json:"bonus"`
+	// Множители, которые получит следующее задание сегодня
+	NextBonus StreakBonus `json:"next_bonus"`
+}
+
+// RecordStreak - отмечает выполнение в день today (дата в поясе игрока).
+// Пропуски закрываются заморозками, если их хватает. Возвращает потраченные заморозки
+func (u *User) RecordStreak(today string, rules StreakRules) int {
+	// Тот же день; раньше - игрок сменил пояс на западный
+	if u.StreakLastDay != "" && today <= u.StreakLastDay {
+		return 0
+	}
+
+	used := 0
+	missed := daysBetween(u.StreakLastDay, today) - 1
+	switch {
+	case u.StreakLastDay == "":
+		u.StreakDays = 1
+	case missed == 0:
+		u.StreakDays++
+	case missed <= u.StreakFreezes:
+		used = missed
+		u.StreakFreezes -= missed
+		u.StreakDays++
+	default:
+		u.StreakDays = 1
+	}
+	u.StreakLastDay = today
+
+	if u.StreakDays > u.StreakBest {
+		u.StreakBest = u.StreakDays
+	}
+	if rules.FreezeEvery > 0 && u.StreakDays%rules.FreezeEvery == 0 && u.StreakFreezes < rules.MaxFreezes {
+		u.StreakFreezes++
+	}
+
+	return used
+}
+
+// CurrentStreak - серия на день today: 0, если ее уже не спасти заморозками
+func (u *User) CurrentStreak(today string) int {
+	if u.StreakLastDay == "" {
+		return 0
+	}
+	if missed := daysBetween(u.StreakLastDay, today) - 1; missed > u.StreakFreezes {
+		return 0
+	}
+	return u.StreakDays
+}
+
+// BuyStreakFreeze - заморозка серии за золото
+func (u *User) BuyStreakFreeze(rules StreakRules) error {
+	if u.StreakFreezes >= rules.MaxFreezes {
+		return ErrTooManyStreakFreezes
+	}
+	if err := u.SpendGold(rules.FreezeCost); err != nil {
+		return err
+	}
+	u.StreakFreezes++
+	return nil
+}
+
+// Streak - серия игрока на момент now
+func (u *User) Streak(now time.Time, rules StreakRules) *StreakStatus {
+	loc := u.Location()
+	today := QuestDate(now, loc)
+	current := u.CurrentStreak(today)
+
+	status := &StreakStatus{
+		Days:    current,
+		Best:    u.StreakBest,
+		Freezes: u.StreakFreezes,
+		Bonus:   rules.Bonus(current, 0),
+	}
+
+	next := current
+	if u.StreakLastDay < today {
+		next++
+		if current > 0 {
+			status.AtRisk = true
+			local := now.In(loc)
+			midnight := time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, loc)
+			status.RiskEndsAt = &midnight
+		}
+	}
+	status.NextBonus = rules.Bonus(next, 0)
+
+	return status
+}
+
+// RecordCompletion - серия квеста: растет, если выполнен и предыдущий день по расписанию
+func (q *QuestTemplate) RecordCompletion(questDate string) {
+	if questDate == "" || questDate <= q.LastCompletedOn {
+		return
+	}
+
+	previous := ""
+	if rule, err := q.Recurrence(); err == nil {
+		previous = rule.Previous(q.StartsOn, questDate)
+	}
+
+	if previous != "" && previous == q.LastCompletedOn {
+		q.Streak++
+	} else {
+		q.Streak = 1
+	}
+	q.LastCompletedOn = questDate
+	if q.Streak > q.BestStreak {
+		q.BestStreak = q.Streak
+	}
+}
+
+// Previous - предыдущий день по расписанию до day, "" если его нет
+func (r Recurrence) Previous(start, day string) string {
+	from, err := time.Parse(QuestDateLayout, start)
+	if err != nil {
+		return ""
+	}
+	to, err := time.Parse(QuestDateLayout, day)
+	if err != nil {
+		return ""
+	}
+
+	for d := to.AddDate(0, 0, -1); !d.Before(from); d = d.AddDate(0, 0, -1) {
+		if r.OccursOn(from, d) {
+			return d.Format(QuestDateLayout)
+		}
+	}
+	return ""
+}
+
+// daysBetween - сколько календарных дней от from до to
+func daysBetween(from, to string) int {
+	a, err := time.Parse(QuestDateLayout, from)
+	if err != nil {
+		return 0
+	}
+	b, err := time.Parse(QuestDateLayout, to)
+	if err != nil {
+		return 0
+	}
+	return int(math.Round(b.Sub(a).Hours() / 24))
+}
@@ -0,0 +1,153 @@
+// internal/domain/streak_test.go
+package domain
+
+import "testing"
+
+func TestRecordStreak(t *testing.T) {
+	rules := StreakRules{FreezeEvery: 7, MaxFreezes: 2}
+
+	for _, tc := range []struct {
+		name        string
+		user        User
+		today       string
+		wantDays    int
+		wantFreezes int
+		wantUsed    int
+	}{
+		{name: "first completion", user: User{}, today: "2026-10-17", wantDays: 1},
+		{name: "next day", user: User{StreakDays: 3, StreakLastDay: "2026-10-16"}, today: "2026-10-17", wantDays: 4},
+		{name: "same day", user: User{StreakDays: 3, StreakBest: 3, StreakLastDay: "2026-10-17"}, today: "2026-10-17", wantDays: 3},
+		// Игрок сменил пояс на западный: у него снова вчера
+		{name: "west timezone", user: User{StreakDays: 3, StreakBest: 3, StreakLastDay: "2026-10-17"}, today: "2026-10-16", wantDays: 3},
+		{
+			name:  "missed day covered by freeze",
+			user:  User{StreakDays: 3, StreakLastDay: "2026-10-15", StreakFreezes: 1},
+			today: "2026-10-17", wantDays: 4, wantUsed: 1,
+		},
+		{
+			name:  "missed days covered by freezes",
+			user:  User{StreakDays: 3, StreakLastDay: "2026-10-14", StreakFreezes: 2},
+			today: "2026-10-17", wantDays: 4, wantUsed: 2,
+		},
+		{
+			name:  "not enough freezes resets",
+			user:  User{StreakDays: 3, StreakLastDay: "2026-10-14", StreakFreezes: 1},
+			today: "2026-10-17", wantDays: 1, wantFreezes: 1,
+		},
+		{name: "freeze earned", user: User{StreakDays: 6, StreakLastDay: "2026-10-16"}, today: "2026-10-17", wantDays: 7, wantFreezes: 1},
+		{
+			name:  "freeze spent and earned back",
+			user:  User{StreakDays: 6, StreakLastDay: "2026-10-15", StreakFreezes: 1},
+			today: "2026-10-17", wantDays: 7, wantFreezes: 1, wantUsed: 1,
+		},
+		{
+			name:  "freeze not earned over cap",
+			user:  User{StreakDays: 13, StreakLastDay: "2026-10-16", StreakFreezes: 2},
+			today: "2026-10-17", wantDays: 14, wantFreezes: 2,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			u := tc.user
+			used := u.RecordStreak(tc.today, rules)
+			if u.StreakDays != tc.wantDays || u.StreakFreezes != tc.wantFreezes || used != tc.wantUsed {
+				t.Errorf("days %d, freezes %d, used %d; want %d, %d, %d",
+					u.StreakDays, u.StreakFreezes, used, tc.wantDays, tc.wantFreezes, tc.wantUsed)
+			}
+			if u.StreakBest < u.StreakDays {
+				t.Errorf("best %d below current %d", u.StreakBest, u.StreakDays)
+			}
+		})
+	}
+}
+
+func TestRecordStreakEarnsFreezesUpToMax(t *testing.T) {
+	rules := StreakRules{FreezeEvery: 7, MaxFreezes: 2}
+	u := &User{}
+
+	earned := map[int]int{6: 0, 7: 1, 13: 1, 14: 2, 21: 2}
+	day := at(t, "UTC", "2026-10-01 00:00")
+	for n := 1; n <= 21; n++ {
+		u.RecordStreak(day.AddDate(0, 0, n-1).Format(QuestDateLayout), rules)
+		if want, ok := earned[n]; ok && u.StreakFreezes != want {
+			t.Errorf("day %d: freezes = %d, want %d", n, u.StreakFreezes, want)
+		}
+	}
+	if u.StreakDays != 21 || u.StreakBest != 21 {
+		t.Errorf("days %d, best %d; want 21", u.StreakDays, u.StreakBest)
+	}
+}
+
+func TestCurrentStreak(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		user  User
+		today string
+		want  int
+	}{
+		{name: "no streak", user: User{}, today: "2026-10-17", want: 0},
+		{name: "done today", user: User{StreakDays: 5, StreakLastDay: "2026-10-17"}, today: "2026-10-17", want: 5},
+		{name: "done yesterday", user: User{StreakDays: 5, StreakLastDay: "2026-10-16"}, today: "2026-10-17", want: 5},
+		{name: "missed without freezes", user: User{StreakDays: 5, StreakLastDay: "2026-10-15"}, today: "2026-10-17", want: 0},
+		{name: "missed with freeze", user: User{StreakDays: 5, StreakLastDay: "2026-10-15", StreakFreezes: 1}, today: "2026-10-17", want: 5},
+		{name: "missed more than freezes", user: User{StreakDays: 5, StreakLastDay: "2026-10-13", StreakFreezes: 2}, today: "2026-10-17", want: 0},
+		{name: "west timezone", user: User{StreakDays: 5, StreakLastDay: "2026-10-17"}, today: "2026-10-16", want: 5},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.user.CurrentStreak(tc.today); got != tc.want {
+				t.Errorf("CurrentStreak = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRecurrencePrevious(t *testing.T) {
+	// 2026-10-14 - среда
+	const start = "2026-10-14"
+
+	for _, tc := range []struct {
+		rule, start, day, want string
+	}{
+		{rule: "FREQ=DAILY", start: start, day: "2026-10-17", want: "2026-10-16"},
+		{rule: "FREQ=DAILY", start: start, day: start, want: ""},
+		{rule: "FREQ=DAILY;INTERVAL=3", start: start, day: "2026-10-20", want: "2026-10-17"},
+		{rule: "FREQ=WEEKLY", start: start, day: "2026-10-28", want: "2026-10-21"},
+		{rule: "FREQ=WEEKLY;BYDAY=MO,WE,FR", start: start, day: "2026-10-19", want: "2026-10-16"},
+		{rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=SA", start: start, day: "2026-10-31", want: "2026-10-17"},
+		{rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=SA", start: start, day: "2026-10-17", want: ""},
+		{rule: "FREQ=DAILY", start: "bad", day: "2026-10-17", want: ""},
+	} {
+		t.Run(tc.rule+" "+tc.day, func(t *testing.T) {
+			r, err := ParseRecurrence(tc.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := r.Previous(tc.start, tc.day); got != tc.want {
+				t.Errorf("Previous = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestQuestStreakAcrossSchedule(t *testing.T) {
+	q := &QuestTemplate{Rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=SA", StartsOn: "2026-10-14"}
+
+	for _, step := range []struct {
+		day        string
+		streak     int
+		bestStreak int
+	}{
+		{"2026-10-17", 1, 1},
+		// Между субботами через неделю пропусков нет
+		{"2026-10-31", 2, 2},
+		{"2026-11-14", 3, 3},
+		// Повторная отметка того же дня ничего не меняет
+		{"2026-11-14", 3, 3},
+		// 28 ноября пропущено
+		{"2026-12-12", 1, 3},
+	} {
+		q.RecordCompletion(step.day)
+		if q.Streak != step.streak || q.BestStreak != step.bestStreak {
+			t.Errorf("%s: streak %d, best %d; want %d, %d", step.day, q.Streak, q.BestStreak, step.streak, step.bestStreak)
+		}
+	}
+}
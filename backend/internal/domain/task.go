@@ -19,6 +19,8 @@ const (
 	FrequencyDaily  TaskFrequency = "daily"
 	FrequencyCustom TaskFrequency = "custom"
 	FrequencyUrgent TaskFrequency = "urgent"
+	// Штрафной квест зоны наказания
+	FrequencyPenalty TaskFrequency = "penalty"
 )
 
 type TaskType string
@@ -53,13 +55,20 @@ type Task struct {
 	UrgentUntil *time.Time    `json:"urgent_until,omitempty"`
 	Penalty     int           `json:"penalty" gorm:"default:0"`
 	
+	// Ежедневный квест: из какого шаблона и за какой день игрока создан
+	TemplateID  *int64        `json:"template_id,omitempty"`
+	QuestDate   string        `json:"quest_date,omitempty"`
+	
+	// Version - оптимистическая блокировка: растет при каждом сохранении
+	Version     int64         `json:"-" gorm:"not null"`
+	
 	StartedAt   *time.Time    `json:"started_at,omitempty"`
 	CompletedAt *time.Time    `json:"completed_at,omitempty"`
 	CreatedAt   time.Time     `json:"created_at"`
 	UpdatedAt   time.Time     `json:"updated_at"`
 	
 	AIAnalyzed  bool          `json:"ai_analyzed" gorm:"default:false"`
-	AIDifficulty int          `json:"ai_difficulty" gorm:"default:1"`
+	AIDifficulty int          `json:"ai_difficulty" gorm:"column:ai_difficulty;default:1"`
 }
 
 // CanStart - проверяет можно ли начать
@@ -139,6 +148,27 @@ func (t *Task) GetRewards() TaskRewards {
 	}
 }
 
+// GetRewardsWithStreak - награды с множителями серии
+func (t *Task) GetRewardsWithStreak(bonus StreakBonus) TaskRewards {
+	rewards := t.GetRewards()
+	rewards.XP = int(float64(rewards.XP) * bonus.XP)
+	rewards.Gold = int(float64(rewards.Gold) * bonus.Gold)
+	rewards.StreakBonus = &bonus
+	return rewards
+}
+
+// BossDifficulty - с какой сложности (1-10) задание считается боссом
+const BossDifficulty = 9
+
+// IsBoss - задание-босс: за победу над ним выпадает добыча DropBoss.
+// Боссом бывает только задание от системы - срочный вызов или квест по
+// расписанию. Свое задание игрок формулирует сам, а оценка сложности
+// детерминирована: подобрав текст, он выпускал бы боссов сколько угодно
+func (t *Task) IsBoss() bool {
+	issued := t.IsUrgent || t.TemplateID != nil
+	return issued && t.AIDifficulty >= BossDifficulty
+}
+
 // CalculateRewards - расчет наград по сложности
 func (t *Task) CalculateRewards() {
 	difficulty := t.AIDifficulty
@@ -185,6 +215,31 @@ type TaskRewards struct {
 	Gold      int    `json:"gold"`
 	StatBoost int    `json:"stat_boost"`
 	StatType  string `json:"stat_type"`
+	// Множители серии, уже учтенные в XP и Gold
+	StreakBonus *StreakBonus `json:"streak_bonus,omitempty"`
+	// Прибавка снаряжения в процентах, уже учтенная в XP и Gold
+	GearBonus int `json:"gear_bonus,omitempty"`
+	// Прибавка свитка опыта в процентах, уже учтенная в XP
+	XPBoost int `json:"xp_boost,omitempty"`
+}
+
+// ApplyGearBonus - прибавка снаряжения к опыту и золоту
+func (r *TaskRewards) ApplyGearBonus(percent int) {
+	if percent <= 0 {
+		return
+	}
+	r.XP += r.XP * percent / 100
+	r.Gold += r.Gold * percent / 100
+	r.GearBonus = percent
+}
+
+// ApplyXPBoost - прибавка свитка опыта
+func (r *TaskRewards) ApplyXPBoost(percent int) {
+	if percent <= 0 {
+		return
+	}
+	r.XP += r.XP * percent / 100
+	r.XPBoost = percent
 }
 
 // Конструкторы
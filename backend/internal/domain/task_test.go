@@ -0,0 +1,35 @@
+// internal/domain/task_test.go
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIsBoss(t *testing.T) {
+	templateID := int64(1)
+	quest := NewDailyTask(1, "Планка", TypeStrength)
+	quest.TemplateID = &templateID
+
+	custom := NewCustomTask(1, "Пробежать марафон", "", TypeAgility)
+	custom.AIAnalyzed = true
+
+	for _, tc := range []struct {
+		name       string
+		task       *Task
+		difficulty int
+		want       bool
+	}{
+		{"hard urgent call", NewUrgentCall(1, "Вызов", TypeStrength, time.Hour), BossDifficulty, true},
+		{"easy urgent call", NewUrgentCall(1, "Вызов", TypeStrength, time.Hour), BossDifficulty - 1, false},
+		{"hard scheduled quest", quest, 10, true},
+		{"hard custom task", custom, 10, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.task.AIDifficulty = tc.difficulty
+			if got := tc.task.IsBoss(); got != tc.want {
+				t.Errorf("IsBoss = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
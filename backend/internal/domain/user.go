@@ -36,10 +36,47 @@ type User struct {
 	SenseiRequests    int       `json:"sensei_requests" gorm:"default:5"`
 	SenseiResetsAt    time.Time `json:"sensei_resets_at"`
 	
+	// С какого момента считается восстановление энергии
+	LastEnergyUpdate  time.Time `json:"last_energy_update"`
+	
+	// Часовой пояс IANA: по нему наступает новый день квестов и тихие часы
+	Timezone          string    `json:"timezone" gorm:"default:UTC"`
+	
+	// Зона наказания: пока назначен штрафной квест, награды заблокированы,
+	// а энергия и золото утекают
+	PenaltyTaskID     *int64     `json:"penalty_task_id,omitempty"`
+	PenaltySince      *time.Time `json:"penalty_since,omitempty"`
+	PenaltyDrainedAt  *time.Time `json:"-"`
+	
+	// Серия дней подряд с выполненными заданиями; StreakLastDay - день в поясе игрока
+	StreakDays        int       `json:"streak_days" gorm:"default:0"`
+	StreakBest        int       `json:"streak_best" gorm:"default:0"`
+	StreakLastDay     string    `json:"streak_last_day,omitempty"`
+	StreakFreezes     int       `json:"streak_freezes" gorm:"default:0"`
+	
+	// Титул за достижение, выбранный игроком; "" - без титула
+	Title             string    `json:"title,omitempty"`
+	
+	// Действие предметов: прибавка к опыту и щит от рейдов
+	XPBoostPercent    int        `json:"xp_boost_percent" gorm:"default:0"`
+	XPBoostUntil      *time.Time `json:"xp_boost_until,omitempty"`
+	RaidShieldUntil   *time.Time `json:"raid_shield_until,omitempty"`
+	
+	// Прибавка надетого снаряжения; базовые характеристики она не меняет.
+	// Не хранится: заполняется по инвентарю и каталогу (core.Armory)
+	Gear              Stats     `json:"gear" gorm:"-"`
+	
 	// Метаданные
+	// Version - оптимистическая блокировка: растет при каждом сохранении
+	Version      int64     `json:"-" gorm:"not null"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 	LastActiveAt time.Time `json:"last_active_at"`
+	
+	// Журнал изменений ресурсов, еще не сохраненный репозиторием
+	ledger       []*LedgerEntry
+	reason       LedgerReason
+	reasonRefID  int64
 }
 
 // CalculateXPToNextLevel - расчет XP для следующего уровня
@@ -51,6 +88,7 @@ func (u *User) CalculateXPToNextLevel() int {
 // AddXP - добавляет опыт, возвращает true если level up
 func (u *User) AddXP(xp int) bool {
 	u.XP += xp
+	u.journal(ResourceXP, xp, u.XP)
 	u.XPToNextLvl = u.CalculateXPToNextLevel()
 	
 	if u.XP >= u.XPToNextLvl {
@@ -62,21 +100,30 @@ func (u *User) AddXP(xp int) bool {
 
 // LevelUp - повышает уровень
 func (u *User) LevelUp() {
+	reason, refID := u.reason, u.reasonRefID
+	defer u.Because(reason, refID)
+	u.Because(ReasonLevelUp, int64(u.Level+1))
+	
 	u.Level++
 	u.XP = u.XP - u.XPToNextLvl
+	u.journal(ResourceXP, -u.XPToNextLvl, u.XP)
 	u.XPToNextLvl = u.CalculateXPToNextLevel()
 	
 	u.MaxEnergy += 10
+	u.journal(ResourceEnergy, u.MaxEnergy-u.Energy, u.MaxEnergy)
 	u.Energy = u.MaxEnergy
 	u.Gold += u.Level * 10
+	u.journal(ResourceGold, u.Level*10, u.Gold)
 }
 
 // AddGold - добавляет золото
 func (u *User) AddGold(amount int) {
+	before := u.Gold
 	u.Gold += amount
 	if u.Gold < 0 {
 		u.Gold = 0
 	}
+	u.journal(ResourceGold, u.Gold-before, u.Gold)
 }
 
 // SpendGold - тратит золото
@@ -85,6 +132,7 @@ func (u *User) SpendGold(amount int) error {
 		return ErrInsufficientGold
 	}
 	u.Gold -= amount
+	u.journal(ResourceGold, -amount, u.Gold)
 	return nil
 }
 
@@ -98,16 +146,21 @@ func (u *User) SpendEnergy(amount int) error {
 	if !u.HasEnoughEnergy(amount) {
 		return ErrInsufficientEnergy
 	}
+	wasFull := u.Energy >= u.MaxEnergy
 	u.Energy -= amount
+	u.journal(ResourceEnergy, -amount, u.Energy)
+	u.touchEnergy(wasFull)
 	return nil
 }
 
 // RestoreEnergy - восстанавливает энергию
 func (u *User) RestoreEnergy(amount int) {
+	before := u.Energy
 	u.Energy += amount
 	if u.Energy > u.MaxEnergy {
 		u.Energy = u.MaxEnergy
 	}
+	u.journal(ResourceEnergy, u.Energy-before, u.Energy)
 }
 
 // IsLicenseValid - проверяет лицензию
@@ -127,32 +180,66 @@ func (u *User) RevokeLicense() {
 	u.LicenseActive = false
 }
 
+// SenseiWeeklyRequests - бесплатные запросы к Сенсею на неделю
+const SenseiWeeklyRequests = 5
+
 // CanUseSensei - проверяет запросы к Сенсею
 func (u *User) CanUseSensei() bool {
 	if u.SenseiRequests <= 0 {
-		if time.Now().After(u.SenseiResetsAt) {
-			u.SenseiRequests = 5
-			u.SenseiResetsAt = time.Now().AddDate(0, 0, 7)
-		}
+		u.ResetSenseiIfDue(time.Now())
 	}
 	return u.SenseiRequests > 0
 }
 
+// ResetSenseiIfDue - недельное пополнение запросов, если подошел срок.
+// Купленные сверх нормы запросы не сгорают
+func (u *User) ResetSenseiIfDue(now time.Time) bool {
+	if !now.After(u.SenseiResetsAt) {
+		return false
+	}
+	
+	if u.SenseiRequests < SenseiWeeklyRequests {
+		u.journalAs(ReasonSenseiReset, ResourceSenseiRequests, SenseiWeeklyRequests-u.SenseiRequests, SenseiWeeklyRequests)
+		u.SenseiRequests = SenseiWeeklyRequests
+	}
+	u.SenseiResetsAt = now.AddDate(0, 0, 7)
+	return true
+}
+
+// LoseEnergy - отнимает энергию, но не ниже нуля
+func (u *User) LoseEnergy(amount int) {
+	before := u.Energy
+	u.Energy -= amount
+	if u.Energy < 0 {
+		u.Energy = 0
+	}
+	u.journal(ResourceEnergy, u.Energy-before, u.Energy)
+	u.touchEnergy(before >= u.MaxEnergy)
+}
+
 // UseSenseiRequest - использует запрос
 func (u *User) UseSenseiRequest() error {
 	if !u.CanUseSensei() {
 		return ErrNoSenseiRequests
 	}
 	u.SenseiRequests--
+	u.journal(ResourceSenseiRequests, -1, u.SenseiRequests)
 	return nil
 }
 
+// RefundSenseiRequest - возвращает запрос, на который Сенсей не ответил
+func (u *User) RefundSenseiRequest() {
+	u.SenseiRequests++
+	u.journal(ResourceSenseiRequests, 1, u.SenseiRequests)
+}
+
 // BuySenseiRequest - покупка запроса
 func (u *User) BuySenseiRequest(cost int) error {
 	if err := u.SpendGold(cost); err != nil {
 		return err
 	}
 	u.SenseiRequests++
+	u.journal(ResourceSenseiRequests, 1, u.SenseiRequests)
 	return nil
 }
 
@@ -188,6 +275,32 @@ func (u *User) GetRank() string {
 	}
 }
 
+// Минимальный уровень рангов E, D, C, B, A, S
+var rankMinLevels = []int{1, 10, 20, 30, 40, 50}
+
+// RankTier - номер ранга: 0 для E ... 5 для S
+func (u *User) RankTier() int {
+	tier := 0
+	for i, min := range rankMinLevels {
+		if u.Level >= min {
+			tier = i
+		}
+	}
+	return tier
+}
+
+// RankLevelRange - уровни рангов с fromTier по toTier включительно.
+// Номера за пределами E..S обрезаются; maxLevel 0 - без верхней границы
+func RankLevelRange(fromTier, toTier int) (minLevel, maxLevel int) {
+	if fromTier < 0 {
+		fromTier = 0
+	}
+	if toTier >= len(rankMinLevels)-1 {
+		return rankMinLevels[fromTier], 0
+	}
+	return rankMinLevels[fromTier], rankMinLevels[toTier+1] - 1
+}
+
 // IsInactive - проверяет неактивность (для рейдов)
 func (u *User) IsInactive() bool {
 	return time.Since(u.LastActiveAt) > 3*24*time.Hour
@@ -196,4 +309,91 @@ func (u *User) IsInactive() bool {
 // UpdateActivity - обновляет активность
 func (u *User) UpdateActivity() {
 	u.LastActiveAt = time.Now()
-}
\ No newline at end of file
+}
+
+// DisplayName - имя игрока для других игроков и сообщений: @username из Telegram,
+// а если его нет - имя
+func (u *User) DisplayName() string {
+	if u.Username != "" {
+		return "@" + u.Username
+	}
+	return u.FirstName
+}
+
+// Location - часовой пояс игрока; неизвестный или пустой считается UTC
+func (u *User) Location() *time.Location {
+	if u.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// SetTimezone - меняет часовой пояс, имя в формате IANA ("Europe/Moscow")
+func (u *User) SetTimezone(name string) error {
+	// "Local" - пояс сервера, а не игрока
+	if name == "" || name == "Local" {
+		return ErrInvalidTimezone
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return ErrInvalidTimezone
+	}
+	u.Timezone = name
+	return nil
+}
+
+// Because - причина и связанный ID для следующих изменений ресурсов
+func (u *User) Because(reason LedgerReason, refID int64) *User {
+	u.reason = reason
+	u.reasonRefID = refID
+	return u
+}
+
+// OpenLedger - записывает стартовые балансы нового игрока
+func (u *User) OpenLedger() {
+	u.Because(ReasonSignup, 0)
+	u.journal(ResourceXP, u.XP, u.XP)
+	u.journal(ResourceGold, u.Gold, u.Gold)
+	u.journal(ResourceEnergy, u.Energy, u.Energy)
+	u.journal(ResourceSenseiRequests, u.SenseiRequests, u.SenseiRequests)
+}
+
+// TakeLedger - забирает несохраненные записи журнала
+func (u *User) TakeLedger() []*LedgerEntry {
+	entries := u.ledger
+	u.ledger = nil
+	for _, entry := range entries {
+		entry.UserID = u.ID
+	}
+	return entries
+}
+
+func (u *User) journal(resource LedgerResource, delta, balance int) {
+	reason := u.reason
+	if reason == "" {
+		reason = ReasonOther
+	}
+	u.journalAs(reason, resource, delta, balance)
+}
+
+func (u *User) journalAs(reason LedgerReason, resource LedgerResource, delta, balance int) {
+	if delta == 0 {
+		return
+	}
+	
+	refID := u.reasonRefID
+	if reason != u.reason {
+		refID = 0
+	}
+	
+	u.ledger = append(u.ledger, &LedgerEntry{
+		Resource:     resource,
+		Reason:       reason,
+		RefID:        refID,
+		Delta:        delta,
+		BalanceAfter: balance,
+	})
+}
@@ -0,0 +1,73 @@
+// internal/env/env.go
+package env
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Настройки API и бота из окружения. Неверное значение - ошибка конфигурации:
+// процесс падает при запуске, а не работает с молча подставленным умолчанием
+
+// Duration - длительность из окружения (формат Go duration)
+func Duration(key string, fallback time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Fatalf("Неверное значение %s: %v", key, err)
+	}
+	return d
+}
+
+// Int - целое число из окружения
+func Int(key string, fallback int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Fatalf("Неверное значение %s: %v", key, err)
+	}
+	return n
+}
+
+// Float - дробное число из окружения
+func Float(key string, fallback float64) float64 {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		log.Fatalf("Неверное значение %s: %v", key, err)
+	}
+	return f
+}
+
+// DurationList - список длительностей через запятую, например "1h,15m"
+func DurationList(key string, fallback []time.Duration) []time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+
+	var result []time.Duration
+	for _, part := range strings.Split(v, ",") {
+		d, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil {
+			log.Fatalf("Неверное значение %s: %v", key, err)
+		}
+		result = append(result, d)
+	}
+	return result
+}
@@ -0,0 +1,60 @@
+// internal/env/env_test.go
+package env
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEnvFallback(t *testing.T) {
+	t.Setenv("DOJO_TEST_VALUE", "")
+
+	if got := Duration("DOJO_TEST_VALUE", time.Minute); got != time.Minute {
+		t.Errorf("Duration = %v", got)
+	}
+	if got := Int("DOJO_TEST_VALUE", 7); got != 7 {
+		t.Errorf("Int = %d", got)
+	}
+	if got := Float("DOJO_TEST_VALUE", 0.5); got != 0.5 {
+		t.Errorf("Float = %v", got)
+	}
+	if got := DurationList("DOJO_TEST_VALUE", []time.Duration{time.Hour}); !reflect.DeepEqual(got, []time.Duration{time.Hour}) {
+		t.Errorf("DurationList = %v", got)
+	}
+}
+
+func TestEnvParse(t *testing.T) {
+	t.Setenv("DOJO_TEST_DURATION", "90s")
+	t.Setenv("DOJO_TEST_INT", "42")
+	t.Setenv("DOJO_TEST_FLOAT", "0.25")
+	t.Setenv("DOJO_TEST_LIST", "1h, 15m")
+
+	if got := Duration("DOJO_TEST_DURATION", 0); got != 90*time.Second {
+		t.Errorf("Duration = %v", got)
+	}
+	if got := Int("DOJO_TEST_INT", 0); got != 42 {
+		t.Errorf("Int = %d", got)
+	}
+	if got := Float("DOJO_TEST_FLOAT", 0); got != 0.25 {
+		t.Errorf("Float = %v", got)
+	}
+	if got := DurationList("DOJO_TEST_LIST", nil); !reflect.DeepEqual(got, []time.Duration{time.Hour, 15 * time.Minute}) {
+		t.Errorf("DurationList = %v", got)
+	}
+}
+
+func TestStreaksAndEnergy(t *testing.T) {
+	t.Setenv("STREAK_FREEZE_COST", "250")
+	t.Setenv("ENERGY_REGEN_PER_MINUTE", "0.5")
+
+	if got := Streaks().FreezeCost; got != 250 {
+		t.Errorf("FreezeCost = %d", got)
+	}
+	if got := Energy().PerMinute; got != 0.5 {
+		t.Errorf("PerMinute = %v", got)
+	}
+	if len(ItemCatalog()) == 0 {
+		t.Error("embedded catalog is empty")
+	}
+}
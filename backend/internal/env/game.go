@@ -0,0 +1,47 @@
+// internal/env/game.go
+package env
+
+import (
+	"log"
+	"os"
+
+	"dojo/internal/domain"
+	"dojo/shop"
+)
+
+// Energy - скорость восстановления энергии и цена полного восстановления
+func Energy() domain.EnergyConfig {
+	config := domain.DefaultEnergyConfig()
+	config.PerMinute = Float("ENERGY_REGEN_PER_MINUTE", config.PerMinute)
+	config.PerLevel = Float("ENERGY_REGEN_PER_LEVEL", config.PerLevel)
+	config.RefillCost = Int("ENERGY_REFILL_COST", config.RefillCost)
+	return config
+}
+
+// Streaks - бонусы за серию дней и цена заморозки
+func Streaks() domain.StreakRules {
+	rules := domain.DefaultStreakRules()
+	rules.XPPerDay = Float("STREAK_XP_PER_DAY", rules.XPPerDay)
+	rules.GoldPerDay = Float("STREAK_GOLD_PER_DAY", rules.GoldPerDay)
+	rules.MaxBonus = Float("STREAK_MAX_BONUS", rules.MaxBonus)
+	rules.FreezeCost = Int("STREAK_FREEZE_COST", rules.FreezeCost)
+	return rules
+}
+
+// ItemCatalog - каталог магазина: вшитый или из SHOP_CATALOG_FILE
+func ItemCatalog() []*domain.Item {
+	data := shop.Default
+	if path := os.Getenv("SHOP_CATALOG_FILE"); path != "" {
+		var err error
+		data, err = os.ReadFile(path)
+		if err != nil {
+			log.Fatalf("Неверное значение SHOP_CATALOG_FILE: %v", err)
+		}
+	}
+
+	items, err := domain.ParseItemCatalog(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return items
+}
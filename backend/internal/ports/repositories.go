@@ -4,6 +4,7 @@ package ports
 import (
 	"context"
 	"dojo/internal/domain"
+	"time"
 )
 
 // UserRepository - интерфейс работы с пользователями
@@ -13,7 +14,13 @@ type UserRepository interface {
 	GetByTelegramID(ctx context.Context, telegramID int64) (*domain.User, error)
 	Update(ctx context.Context, user *domain.User) error
 	List(ctx context.Context, limit, offset int) ([]*domain.User, error)
-	GetInactivePlayers(ctx context.Context, limit int) ([]*domain.User, error)
+	// GetInactivePlayers - неактивные игроки с уровнем в [minLevel, maxLevel]; maxLevel 0 - без верхней границы
+	GetInactivePlayers(ctx context.Context, minLevel, maxLevel, limit int) ([]*domain.User, error)
+	GetWithExpiredLicense(ctx context.Context, limit int) ([]*domain.User, error)
+	// GetSenseiResetDue - игроки, у которых срок пополнения запросов к Сенсею наступил до before
+	GetSenseiResetDue(ctx context.Context, before time.Time, limit int) ([]*domain.User, error)
+	// GetPenaltyDrainDue - игроки в зоне наказания, с которых последний раз брали ресурсы до before
+	GetPenaltyDrainDue(ctx context.Context, before time.Time, limit int) ([]*domain.User, error)
 	UpdateActivity(ctx context.Context, userID int64) error
 }
 
@@ -22,18 +29,164 @@ type TaskRepository interface {
 	Create(ctx context.Context, task *domain.Task) error
 	GetByID(ctx context.Context, id int64) (*domain.Task, error)
 	GetByUserID(ctx context.Context, userID int64) ([]*domain.Task, error)
+	GetRecentByUserID(ctx context.Context, userID int64, limit int) ([]*domain.Task, error)
 	GetActiveByUserID(ctx context.Context, userID int64) ([]*domain.Task, error)
-	GetDailyTasks(ctx context.Context, userID int64) ([]*domain.Task, error)
+	// GetDailyTasks - ежедневные квесты игрока за день date (в его часовом поясе)
+	GetDailyTasks(ctx context.Context, userID int64, date string) ([]*domain.Task, error)
+	// CreateQuest - задание из шаблона; false, если за этот день оно уже создано
+	CreateQuest(ctx context.Context, task *domain.Task) (bool, error)
+	// CreatePenaltyQuest - штрафной квест; нулевые награды и цена сохраняются как есть
+	CreatePenaltyQuest(ctx context.Context, task *domain.Task) error
+	// GetPenaltyQuests - штрафные квесты игрока, от новых к старым
+	GetPenaltyQuests(ctx context.Context, userID int64, limit, offset int) ([]*domain.Task, error)
+	// GetOverdueQuests - невыполненные квесты из шаблонов, чей день в поясе владельца уже закончился
+	GetOverdueQuests(ctx context.Context, now time.Time, limit int) ([]*domain.Task, error)
 	Update(ctx context.Context, task *domain.Task) error
 	Delete(ctx context.Context, id int64) error
 	GetUrgentTasks(ctx context.Context, userID int64) ([]*domain.Task, error)
-	ExpireOldTasks(ctx context.Context) error
+	// GetUrgentDueBefore - незавершенные срочные задания с дедлайном до before, ближайшие первыми
+	GetUrgentDueBefore(ctx context.Context, before time.Time, limit int) ([]*domain.Task, error)
+	// GetUrgentDueBetween - то же с дедлайном в (after, before]: еще не истекшие
+	GetUrgentDueBetween(ctx context.Context, after, before time.Time, limit int) ([]*domain.Task, error)
+}
+
+// QuestTemplateRepository - шаблоны повторяющихся квестов
+type QuestTemplateRepository interface {
+	Create(ctx context.Context, template *domain.QuestTemplate) error
+	GetByID(ctx context.Context, id int64) (*domain.QuestTemplate, error)
+	// GetByUserID - активные шаблоны игрока
+	GetByUserID(ctx context.Context, userID int64) ([]*domain.QuestTemplate, error)
+	Update(ctx context.Context, template *domain.QuestTemplate) error
+	// GetDue - активные шаблоны, у которых в поясе владельца уже наступил
+	// день после LastRunOn
+	GetDue(ctx context.Context, now time.Time, limit int) ([]*domain.QuestTemplate, error)
+}
+
+// AchievementRepository - прогресс игроков по достижениям
+type AchievementRepository interface {
+	// Get - прогресс игрока; domain.ErrAchievementNotFound, если достижение еще не начато
+	Get(ctx context.Context, userID int64, achievementID string) (*domain.UserAchievement, error)
+	GetByUserID(ctx context.Context, userID int64) ([]*domain.UserAchievement, error)
+	// Create - false, если запись уже создал параллельный запрос
+	Create(ctx context.Context, achievement *domain.UserAchievement) (bool, error)
+	Update(ctx context.Context, achievement *domain.UserAchievement) error
+}
+
+// InventoryRepository - предметы в инвентаре игроков
+type InventoryRepository interface {
+	// Get - ячейка с предметом; domain.ErrItemNotOwned, если предмета никогда не было
+	Get(ctx context.Context, userID int64, itemID string) (*domain.InventoryItem, error)
+	// GetByUserID - непустые ячейки игрока
+	GetByUserID(ctx context.Context, userID int64) ([]*domain.InventoryItem, error)
+	// Create - false, если ячейку уже создал параллельный запрос
+	Create(ctx context.Context, item *domain.InventoryItem) (bool, error)
+	Update(ctx context.Context, item *domain.InventoryItem) error
+}
+
+// OutboxRepository - исходящие доменные события и их доставка подписчикам
+type OutboxRepository interface {
+	// Append - сохраняет события в транзакции из ctx, если она открыта
+	Append(ctx context.Context, events []*domain.OutboxEvent) error
+	// GetUndispatched - события, для которых еще не созданы доставки, по порядку
+	GetUndispatched(ctx context.Context, limit int) ([]*domain.OutboxEvent, error)
+	// Dispatch - создает доставки события и отмечает его разосланным
+	Dispatch(ctx context.Context, event *domain.OutboxEvent, deliveries []*domain.EventDelivery) error
+	// GetDueDeliveries - ожидающие доставки, чье время попытки наступило, вместе с событиями
+	GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*domain.EventDelivery, error)
+	GetDelivery(ctx context.Context, id int64) (*domain.EventDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *domain.EventDelivery) error
+	// GetDeadDeliveries - мертвая очередь, новые сверху
+	GetDeadDeliveries(ctx context.Context, limit, offset int) ([]*domain.EventDelivery, error)
+	// PurgeDelivered - удаляет события старше before, доставленные всем подписчикам
+	PurgeDelivered(ctx context.Context, before time.Time) (int, error)
+}
+
+// EventStatsRepository - дневные счетчики событий для аналитики
+type EventStatsRepository interface {
+	Increment(ctx context.Context, day string, eventType domain.EventType) error
+	// GetSince - счетчики с дня from включительно
+	GetSince(ctx context.Context, from string) ([]*domain.EventStat, error)
+}
+
+// RaidRepository - история рейдов
+type RaidRepository interface {
+	Create(ctx context.Context, raid *domain.Raid) error
+	GetByID(ctx context.Context, id int64) (*domain.Raid, error)
+	// GetByAttackerID / GetByTargetID - свежие первыми
+	GetByAttackerID(ctx context.Context, attackerID int64, limit, offset int) ([]*domain.Raid, error)
+	GetByTargetID(ctx context.Context, targetID int64, limit, offset int) ([]*domain.Raid, error)
+	// GetByTargetsSince - рейды на любую из целей, начатые не раньше since
+	GetByTargetsSince(ctx context.Context, targetIDs []int64, since time.Time) ([]*domain.Raid, error)
+}
+
+// SessionRepository - интерфейс работы с сессиями (refresh-токены)
+type SessionRepository interface {
+	Create(ctx context.Context, session *domain.Session) error
+	GetByID(ctx context.Context, id string) (*domain.Session, error)
+	// Revoke - ErrSessionRevoked, если сессию уже отозвали (или ее нет)
+	Revoke(ctx context.Context, id string) error
+	RevokeAllByUserID(ctx context.Context, userID int64) error
+}
+
+// NotificationRepository - очередь уведомлений и настройки игроков
+type NotificationRepository interface {
+	// Enqueue - добавляет уведомление, false если такое уже было (по DedupKey)
+	Enqueue(ctx context.Context, n *domain.Notification) (bool, error)
+	GetPending(ctx context.Context, now time.Time, limit int) ([]*domain.Notification, error)
+	Update(ctx context.Context, n *domain.Notification) error
+	GetSettings(ctx context.Context, userID int64) (*domain.NotificationSettings, error)
+	SaveSettings(ctx context.Context, settings *domain.NotificationSettings) error
+}
+
+// SenseiRepository - диалоги с Сенсеем
+type SenseiRepository interface {
+	CreateConversation(ctx context.Context, conversation *domain.SenseiConversation) error
+	GetConversation(ctx context.Context, id int64) (*domain.SenseiConversation, error)
+	GetLatestConversation(ctx context.Context, userID int64) (*domain.SenseiConversation, error)
+	ListConversations(ctx context.Context, userID int64, limit, offset int) ([]*domain.SenseiConversation, error)
+	UpdateConversation(ctx context.Context, conversation *domain.SenseiConversation) error
+	DeleteConversation(ctx context.Context, id int64) error
+	DeleteAllByUserID(ctx context.Context, userID int64) error
+	AddMessage(ctx context.Context, message *domain.SenseiMessage) error
+	// GetMessages - реплики диалога с ID больше afterID по возрастанию
+	GetMessages(ctx context.Context, conversationID, afterID int64) ([]*domain.SenseiMessage, error)
+}
+
+// LedgerRepository - журнал изменений ресурсов. Записи добавляет UserRepository
+// при сохранении игрока, здесь только чтение
+type LedgerRepository interface {
+	// ListByUserID - записи игрока от новых к старым; пустой resource - все ресурсы
+	ListByUserID(ctx context.Context, userID int64, resource domain.LedgerResource, limit, offset int) ([]*domain.LedgerEntry, error)
+	// Audit - балансы, не совпадающие с суммой записей журнала
+	Audit(ctx context.Context, limit int) ([]*domain.LedgerDiscrepancy, error)
+}
+
+// LeaderElector - лидерство среди реплик: фоновые задачи выполняет только лидер
+type LeaderElector interface {
+	// TryLead - захватывает или подтверждает лидерство; false - лидер другая реплика
+	TryLead(ctx context.Context) (bool, error)
+	// Resign - отдает лидерство, например при остановке
+	Resign(ctx context.Context) error
+}
+
+// TxManager - единица работы: вызовы репозиториев с ctx, переданным в fn,
+// выполняются в одной транзакции. Ошибка из fn откатывает все изменения
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 // AIService - интерфейс ИИ-сервиса
 type AIService interface {
 	AnalyzeTask(ctx context.Context, title, description string) (*TaskAnalysis, error)
+	// Chat - ответ Сенсея; в history могут быть сообщения с ролью "system"
+	// (контекст игрока, сжатая история), их надо передать модели как системные
 	Chat(ctx context.Context, userID int64, message string, history []ChatMessage) (string, error)
+	// ChatStream - то же, что Chat, но ответ приходит кусками. Ошибка возвращается,
+	// если поток не удалось открыть; оборванный поток присылает последним ChatDelta с Err.
+	// Канал закрывается по окончании ответа или отмене ctx
+	ChatStream(ctx context.Context, userID int64, message string, history []ChatMessage) (<-chan ChatDelta, error)
+	// Summarize - сжимает реплики в краткое содержание с учетом предыдущего
+	Summarize(ctx context.Context, previousSummary string, messages []ChatMessage) (string, error)
 	GenerateUrgentCall(ctx context.Context, userID int64) (*UrgentCallSuggestion, error)
 }
 
@@ -47,12 +200,25 @@ type TaskAnalysis struct {
 	Explanation string
 }
 
+// Роли ChatMessage
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
 // ChatMessage - сообщение в чате
 type ChatMessage struct {
 	Role    string
 	Content string
 }
 
+// ChatDelta - очередной фрагмент потокового ответа
+type ChatDelta struct {
+	Text string
+	Err  error
+}
+
 // UrgentCallSuggestion - предложение срочного вызова
 type UrgentCallSuggestion struct {
 	Title       string
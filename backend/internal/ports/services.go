@@ -1 +1,41 @@
+// internal/ports/services.go
 package ports
+
+import (
+	"context"
+	"dojo/internal/domain"
+	"time"
+)
+
+// TokenManager - выпуск и проверка токенов сессии
+type TokenManager interface {
+	IssueAccessToken(userID int64, sessionID string) (string, time.Time, error)
+	IssueRefreshToken(userID int64, sessionID string, expiresAt time.Time) (string, error)
+	ParseAccessToken(token string) (*TokenClaims, error)
+	ParseRefreshToken(token string) (*TokenClaims, error)
+}
+
+// TokenClaims - проверенные данные токена
+type TokenClaims struct {
+	UserID    int64
+	SessionID string
+	ExpiresAt time.Time
+}
+
+// EventPublisher - публикация доменных событий. Вызывается внутри транзакции
+// изменения: событие сохраняется вместе с ним или не сохраняется вовсе
+type EventPublisher interface {
+	Publish(ctx context.Context, events ...domain.Event) error
+}
+
+// WebhookSender - отправка события во внешний вебхук
+type WebhookSender interface {
+	Send(ctx context.Context, event domain.Event) error
+}
+
+// Notifier - канал доставки уведомлений игроку
+type Notifier interface {
+	// Send - доставляет уведомление; domain.ErrRecipientUnavailable,
+	// если игрок заблокировал бота и повторять бессмысленно
+	Send(ctx context.Context, user *domain.User, n *domain.Notification) error
+}
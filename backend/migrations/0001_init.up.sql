@@ -0,0 +1,80 @@
+-- Базовая схема: пользователи и задания.
+-- IF NOT EXISTS - чтобы базы, созданные AutoMigrate, приняли миграцию без ошибок
+
+CREATE TABLE IF NOT EXISTS users (
+    id                 BIGSERIAL PRIMARY KEY,
+    telegram_id        BIGINT      NOT NULL,
+    username           TEXT,
+    first_name         TEXT,
+    photo_url          TEXT,
+
+    level              BIGINT      DEFAULT 1,
+    xp                 BIGINT      DEFAULT 0,
+    xp_to_next_lvl     BIGINT,
+
+    gold               BIGINT      DEFAULT 0,
+    energy             BIGINT      DEFAULT 100,
+    max_energy         BIGINT      DEFAULT 100,
+
+    strength           BIGINT      DEFAULT 1,
+    agility            BIGINT      DEFAULT 1,
+    intelligence       BIGINT      DEFAULT 1,
+    insight            BIGINT      DEFAULT 1,
+
+    license_active     BOOLEAN     DEFAULT TRUE,
+    last_license_check TIMESTAMPTZ,
+    license_expires_at TIMESTAMPTZ,
+
+    sensei_requests    BIGINT      DEFAULT 5,
+    sensei_resets_at   TIMESTAMPTZ,
+
+    created_at         TIMESTAMPTZ,
+    updated_at         TIMESTAMPTZ,
+    last_active_at     TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_telegram_id ON users (telegram_id);
+
+CREATE TABLE IF NOT EXISTS tasks (
+    id            BIGSERIAL PRIMARY KEY,
+    user_id       BIGINT      NOT NULL,
+
+    title         TEXT        NOT NULL,
+    description   TEXT,
+
+    task_type     TEXT        NOT NULL,
+    frequency     TEXT        DEFAULT 'custom',
+    status        TEXT        DEFAULT 'active',
+
+    xp_reward     BIGINT      DEFAULT 10,
+    gold_reward   BIGINT      DEFAULT 5,
+    stat_boost    BIGINT      DEFAULT 1,
+
+    energy_cost   BIGINT      DEFAULT 10,
+    gold_cost     BIGINT      DEFAULT 0,
+
+    is_urgent     BOOLEAN     DEFAULT FALSE,
+    urgent_until  TIMESTAMPTZ,
+    penalty       BIGINT      DEFAULT 0,
+
+    started_at    TIMESTAMPTZ,
+    completed_at  TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ,
+    updated_at    TIMESTAMPTZ,
+
+    ai_analyzed   BOOLEAN     DEFAULT FALSE,
+    ai_difficulty BIGINT      DEFAULT 1
+);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_user_id ON tasks (user_id);
+
+-- AutoMigrate называл эту колонку a_idifficulty
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'tasks' AND column_name = 'a_idifficulty'
+    ) THEN
+        ALTER TABLE tasks RENAME COLUMN a_idifficulty TO ai_difficulty;
+    END IF;
+END $$;
@@ -0,0 +1,26 @@
+-- Очередь уведомлений и настройки игроков
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT      NOT NULL,
+    kind       TEXT        NOT NULL,
+    dedup_key  TEXT        NOT NULL,
+    text       TEXT        NOT NULL,
+    status     TEXT        DEFAULT 'pending',
+    attempts   BIGINT      DEFAULT 0,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    sent_at    TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_dedup ON notifications (user_id, dedup_key);
+CREATE INDEX IF NOT EXISTS idx_notifications_status ON notifications (status);
+
+CREATE TABLE IF NOT EXISTS notification_settings (
+    user_id             BIGINT PRIMARY KEY,
+    enabled             BOOLEAN     NOT NULL,
+    quiet_hours_enabled BOOLEAN     NOT NULL,
+    quiet_from          BIGINT      NOT NULL,
+    quiet_to            BIGINT      NOT NULL,
+    updated_at          TIMESTAMPTZ
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS sensei_messages;
+DROP TABLE IF EXISTS sensei_conversations;
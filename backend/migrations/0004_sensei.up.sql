@@ -0,0 +1,24 @@
+-- Диалоги с Сенсеем
+
+CREATE TABLE IF NOT EXISTS sensei_conversations (
+    id                  BIGSERIAL PRIMARY KEY,
+    user_id             BIGINT      NOT NULL,
+    title               TEXT,
+    summary             TEXT,
+    summarized_until_id BIGINT      DEFAULT 0,
+    message_count       BIGINT      DEFAULT 0,
+    created_at          TIMESTAMPTZ,
+    updated_at          TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_sensei_conversations_user_id ON sensei_conversations (user_id);
+
+CREATE TABLE IF NOT EXISTS sensei_messages (
+    id              BIGSERIAL PRIMARY KEY,
+    conversation_id BIGINT      NOT NULL,
+    role            TEXT        NOT NULL,
+    content         TEXT        NOT NULL,
+    created_at      TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_sensei_messages_conversation_id ON sensei_messages (conversation_id);
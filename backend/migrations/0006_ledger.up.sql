@@ -0,0 +1,27 @@
+-- Журнал изменений XP, золота, энергии и запросов к Сенсею
+
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id            BIGSERIAL PRIMARY KEY,
+    user_id       BIGINT      NOT NULL,
+    resource      TEXT        NOT NULL,
+    reason        TEXT        NOT NULL,
+    ref_id        BIGINT      NOT NULL DEFAULT 0,
+    delta         BIGINT      NOT NULL,
+    balance_after BIGINT      NOT NULL,
+    created_at    TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_user_id ON ledger_entries (user_id, id);
+
+-- Текущие балансы становятся первыми записями, чтобы сверка сходилась
+INSERT INTO ledger_entries (user_id, resource, reason, delta, balance_after, created_at)
+SELECT u.id, b.resource, 'opening_balance', b.balance, b.balance, NOW()
+FROM users u
+CROSS JOIN LATERAL (VALUES
+    ('xp', u.xp),
+    ('gold', u.gold),
+    ('energy', u.energy),
+    ('sensei_requests', u.sensei_requests)
+) AS b (resource, balance)
+WHERE b.balance <> 0
+  AND NOT EXISTS (SELECT 1 FROM ledger_entries e WHERE e.user_id = u.id);
@@ -0,0 +1,17 @@
+-- История рейдов
+
+CREATE TABLE IF NOT EXISTS raids (
+    id          BIGSERIAL PRIMARY KEY,
+    attacker_id BIGINT      NOT NULL,
+    target_id   BIGINT      NOT NULL,
+    cost        BIGINT      DEFAULT 0,
+    gold_looted BIGINT      DEFAULT 0,
+    xp_gained   BIGINT      DEFAULT 0,
+    outcome     TEXT        NOT NULL,
+    started_at  TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_raids_attacker_id ON raids (attacker_id);
+CREATE INDEX IF NOT EXISTS idx_raids_target_id ON raids (target_id);
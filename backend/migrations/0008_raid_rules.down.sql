@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_raids_target_started;
@@ -0,0 +1,3 @@
+-- Проверки перезарядок и защиты ищут недавние рейды на цель
+
+CREATE INDEX IF NOT EXISTS idx_raids_target_started ON raids (target_id, started_at);
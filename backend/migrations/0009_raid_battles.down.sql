@@ -0,0 +1,2 @@
+ALTER TABLE raids DROP COLUMN IF EXISTS battle;
+ALTER TABLE raids DROP COLUMN IF EXISTS energy_lost;
@@ -0,0 +1,4 @@
+-- Бой рейда: исход зависит от характеристик, лог раундов хранится целиком
+
+ALTER TABLE raids ADD COLUMN IF NOT EXISTS energy_lost BIGINT DEFAULT 0;
+ALTER TABLE raids ADD COLUMN IF NOT EXISTS battle JSONB;
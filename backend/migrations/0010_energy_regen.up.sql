@@ -0,0 +1,4 @@
+-- Ленивое восстановление энергии: сколько накопилось, считается от отметки
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_energy_update TIMESTAMPTZ;
+UPDATE users SET last_energy_update = NOW() WHERE last_energy_update IS NULL;
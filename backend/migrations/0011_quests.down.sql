@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_tasks_quest;
+ALTER TABLE tasks DROP COLUMN IF EXISTS quest_date;
+ALTER TABLE tasks DROP COLUMN IF EXISTS template_id;
+
+DROP TABLE IF EXISTS quest_templates;
+
+ALTER TABLE users DROP COLUMN IF EXISTS timezone;
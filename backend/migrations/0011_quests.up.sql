@@ -0,0 +1,28 @@
+-- Повторяющиеся квесты и часовой пояс игрока
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone TEXT DEFAULT 'UTC';
+
+CREATE TABLE IF NOT EXISTS quest_templates (
+    id          BIGSERIAL PRIMARY KEY,
+    user_id     BIGINT      NOT NULL,
+    title       TEXT        NOT NULL,
+    description TEXT,
+    task_type   TEXT        NOT NULL,
+    rule        TEXT        NOT NULL,
+    starts_on   TEXT        NOT NULL,
+    active      BOOLEAN     NOT NULL,
+    last_run_on TEXT,
+    version     BIGINT      NOT NULL DEFAULT 0,
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_quest_templates_user_id ON quest_templates (user_id);
+CREATE INDEX IF NOT EXISTS idx_quest_templates_active ON quest_templates (last_run_on) WHERE active;
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS template_id BIGINT;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS quest_date TEXT;
+
+-- Одно задание из шаблона за день: генератор можно запускать сколько угодно раз
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tasks_quest ON tasks (template_id, quest_date)
+    WHERE template_id IS NOT NULL;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_tasks_open_quests;
+DROP INDEX IF EXISTS idx_users_penalty_drained_at;
+
+ALTER TABLE users DROP COLUMN IF EXISTS penalty_drained_at;
+ALTER TABLE users DROP COLUMN IF EXISTS penalty_since;
+ALTER TABLE users DROP COLUMN IF EXISTS penalty_task_id;
@@ -0,0 +1,12 @@
+-- Зона наказания за невыполненные ежедневные квесты
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS penalty_task_id BIGINT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS penalty_since TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS penalty_drained_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_users_penalty_drained_at ON users (penalty_drained_at)
+    WHERE penalty_task_id IS NOT NULL;
+
+-- Проверка конца дня ищет только незавершенные квесты из шаблонов
+CREATE INDEX IF NOT EXISTS idx_tasks_open_quests ON tasks (quest_date)
+    WHERE template_id IS NOT NULL AND status IN ('active', 'in_progress');
@@ -0,0 +1,8 @@
+ALTER TABLE quest_templates DROP COLUMN IF EXISTS last_completed_on;
+ALTER TABLE quest_templates DROP COLUMN IF EXISTS best_streak;
+ALTER TABLE quest_templates DROP COLUMN IF EXISTS streak;
+
+ALTER TABLE users DROP COLUMN IF EXISTS streak_freezes;
+ALTER TABLE users DROP COLUMN IF EXISTS streak_last_day;
+ALTER TABLE users DROP COLUMN IF EXISTS streak_best;
+ALTER TABLE users DROP COLUMN IF EXISTS streak_days;
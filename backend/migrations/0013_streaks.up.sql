@@ -0,0 +1,10 @@
+-- Серии дней подряд у игрока и у повторяющихся квестов
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS streak_days BIGINT DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS streak_best BIGINT DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS streak_last_day TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS streak_freezes BIGINT DEFAULT 0;
+
+ALTER TABLE quest_templates ADD COLUMN IF NOT EXISTS streak BIGINT DEFAULT 0;
+ALTER TABLE quest_templates ADD COLUMN IF NOT EXISTS best_streak BIGINT DEFAULT 0;
+ALTER TABLE quest_templates ADD COLUMN IF NOT EXISTS last_completed_on TEXT;
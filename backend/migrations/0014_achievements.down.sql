@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS user_achievements;
+
+ALTER TABLE users DROP COLUMN IF EXISTS title;
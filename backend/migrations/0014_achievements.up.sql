@@ -0,0 +1,17 @@
+-- Достижения: прогресс игроков и выбранный титул
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS title TEXT;
+
+CREATE TABLE IF NOT EXISTS user_achievements (
+    id             BIGSERIAL PRIMARY KEY,
+    user_id        BIGINT      NOT NULL,
+    achievement_id TEXT        NOT NULL,
+    progress       BIGINT      NOT NULL DEFAULT 0,
+    unlocked_at    TIMESTAMPTZ,
+    version        BIGINT      NOT NULL DEFAULT 0,
+    created_at     TIMESTAMPTZ,
+    updated_at     TIMESTAMPTZ
+);
+
+-- Одна запись прогресса на игрока и достижение
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_achievements ON user_achievements (user_id, achievement_id);
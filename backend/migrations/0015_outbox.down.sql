@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS event_stats;
+DROP TABLE IF EXISTS event_deliveries;
+DROP TABLE IF EXISTS outbox_events;
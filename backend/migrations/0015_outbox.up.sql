@@ -0,0 +1,39 @@
+-- Outbox доменных событий, доставки подписчикам и дневная статистика
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id            BIGSERIAL PRIMARY KEY,
+    type          TEXT        NOT NULL,
+    user_id       BIGINT      NOT NULL,
+    payload       JSONB       NOT NULL,
+    dispatched_at TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_user_id ON outbox_events (user_id);
+-- Релей забирает еще не разосланные события по порядку
+CREATE INDEX IF NOT EXISTS idx_outbox_events_undispatched ON outbox_events (id) WHERE dispatched_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS event_deliveries (
+    id              BIGSERIAL PRIMARY KEY,
+    event_id        BIGINT      NOT NULL REFERENCES outbox_events (id) ON DELETE CASCADE,
+    subscriber      TEXT        NOT NULL,
+    status          TEXT        NOT NULL DEFAULT 'pending',
+    attempts        BIGINT      NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    last_error      TEXT,
+    delivered_at    TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ,
+    updated_at      TIMESTAMPTZ
+);
+
+-- Одна доставка события каждому подписчику
+CREATE UNIQUE INDEX IF NOT EXISTS idx_event_deliveries ON event_deliveries (event_id, subscriber);
+CREATE INDEX IF NOT EXISTS idx_event_deliveries_due ON event_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_event_deliveries_dead ON event_deliveries (updated_at) WHERE status = 'dead';
+
+CREATE TABLE IF NOT EXISTS event_stats (
+    day        TEXT   NOT NULL,
+    event_type TEXT   NOT NULL,
+    count      BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, event_type)
+);
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS inventory_items;
+
+ALTER TABLE users DROP COLUMN IF EXISTS gear_insight;
+ALTER TABLE users DROP COLUMN IF EXISTS gear_intelligence;
+ALTER TABLE users DROP COLUMN IF EXISTS gear_agility;
+ALTER TABLE users DROP COLUMN IF EXISTS gear_strength;
+ALTER TABLE users DROP COLUMN IF EXISTS raid_shield_until;
+ALTER TABLE users DROP COLUMN IF EXISTS xp_boost_until;
+ALTER TABLE users DROP COLUMN IF EXISTS xp_boost_percent;
@@ -0,0 +1,23 @@
+-- Магазин: инвентарь игроков, действие предметов и прибавка снаряжения
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS xp_boost_percent BIGINT DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS xp_boost_until TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS raid_shield_until TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS gear_strength BIGINT DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS gear_agility BIGINT DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS gear_intelligence BIGINT DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS gear_insight BIGINT DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS inventory_items (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT      NOT NULL,
+    item_id    TEXT        NOT NULL,
+    quantity   BIGINT      NOT NULL DEFAULT 0,
+    equipped   BOOLEAN     NOT NULL DEFAULT FALSE,
+    version    BIGINT      NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+-- Одна ячейка на игрока и предмет
+CREATE UNIQUE INDEX IF NOT EXISTS idx_inventory_items ON inventory_items (user_id, item_id);
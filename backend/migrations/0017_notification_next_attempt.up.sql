@@ -0,0 +1,8 @@
+-- Отложенные уведомления: в тихие часы строка ждет своего времени и не
+-- загораживает остальную очередь
+
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMPTZ;
+UPDATE notifications SET next_attempt_at = COALESCE(created_at, NOW()) WHERE next_attempt_at IS NULL;
+ALTER TABLE notifications ALTER COLUMN next_attempt_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_notifications_due ON notifications (next_attempt_at) WHERE status = 'pending';
@@ -0,0 +1,4 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS gear_strength BIGINT DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS gear_agility BIGINT DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS gear_intelligence BIGINT DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS gear_insight BIGINT DEFAULT 0;
@@ -0,0 +1,7 @@
+-- Прибавка снаряжения больше не хранится у игрока: ее считают по инвентарю
+-- и текущему каталогу, иначе смена каталога оставляла бы старые значения
+
+ALTER TABLE users DROP COLUMN IF EXISTS gear_strength;
+ALTER TABLE users DROP COLUMN IF EXISTS gear_agility;
+ALTER TABLE users DROP COLUMN IF EXISTS gear_intelligence;
+ALTER TABLE users DROP COLUMN IF EXISTS gear_insight;
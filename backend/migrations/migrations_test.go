@@ -0,0 +1,174 @@
+// migrations/migrations_test.go
+package migrations
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func sql(text string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(text)}
+}
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_add_items.up.sql":   sql("CREATE TABLE items ();"),
+		"0002_add_items.down.sql": sql("DROP TABLE items;"),
+		"0001_init.down.sql":      sql("DROP TABLE users;"),
+		"0001_init.up.sql":        sql("CREATE TABLE users ();"),
+		"0010_later.up.sql":       sql("SELECT 1;"),
+		"0010_later.down.sql":     sql("SELECT 1;"),
+		// Посторонние файлы и каталоги пропускаются
+		"migrations.go":        sql("package migrations"),
+		"README.md":            sql("# migrations"),
+		"0003_Bad-Name.up.sql": sql("SELECT 1;"),
+		"0004_dir.up.sql/x":    sql(""),
+	}
+
+	migrations, err := load(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, m := range migrations {
+		got = append(got, m.Name)
+	}
+	if want := []string{"init", "add_items", "later"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("names = %q, want %q", got, want)
+	}
+	if m := migrations[1]; m.Version != 2 || m.Up != "CREATE TABLE items ();" || m.Down != "DROP TABLE items;" {
+		t.Errorf("add_items = %+v", m)
+	}
+}
+
+func TestLoadRejects(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		fsys fstest.MapFS
+		want string
+	}{
+		{
+			name: "missing down",
+			fsys: fstest.MapFS{"0001_init.up.sql": sql("CREATE TABLE users ();")},
+			want: "нет up или down",
+		},
+		{
+			name: "missing up",
+			fsys: fstest.MapFS{"0001_init.down.sql": sql("DROP TABLE users;")},
+			want: "нет up или down",
+		},
+		{
+			name: "empty up",
+			fsys: fstest.MapFS{
+				"0001_init.up.sql":   sql("  \n"),
+				"0001_init.down.sql": sql("DROP TABLE users;"),
+			},
+			want: "нет up или down",
+		},
+		{
+			name: "two names for one version",
+			fsys: fstest.MapFS{
+				"0001_init.up.sql":    sql("CREATE TABLE users ();"),
+				"0001_init.down.sql":  sql("DROP TABLE users;"),
+				"0001_users.up.sql":   sql("CREATE TABLE users ();"),
+				"0001_users.down.sql": sql("DROP TABLE users;"),
+			},
+			want: "два имени",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := load(tc.fsys)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("err = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestEmbeddedMigrationsLoad(t *testing.T) {
+	migrations, err := load(Files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, m := range migrations {
+		if m.Version != int64(i+1) {
+			t.Fatalf("version %d at position %d: numbering has a gap", m.Version, i)
+		}
+	}
+}
+
+func TestCreate(t *testing.T) {
+	dir := t.TempDir()
+
+	paths, err := Create(dir, "Init")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join(dir, "0001_init.up.sql"), filepath.Join(dir, "0001_init.down.sql")}
+	if !reflect.DeepEqual(paths, want) {
+		t.Fatalf("paths = %q, want %q", paths, want)
+	}
+
+	// Номер - следующий за последним, даже если версии идут с пропуском
+	if err := os.WriteFile(filepath.Join(dir, "0007_manual.up.sql"), []byte("SELECT 1;"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "0007_manual.down.sql"), []byte("SELECT 1;"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err = Create(dir, " add items ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := filepath.Base(paths[0]); got != "0008_add_items.up.sql" {
+		t.Errorf("next migration = %s, want 0008_add_items.up.sql", got)
+	}
+
+	if _, err := Create(dir, "добавить-предметы"); err == nil {
+		t.Error("Create accepted a non-latin name")
+	}
+}
+
+func TestMergeStatus(t *testing.T) {
+	appliedAt := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	migrations := []Migration{
+		{Version: 1, Name: "init"},
+		{Version: 2, Name: "add_items"},
+		{Version: 4, Name: "later"},
+	}
+	applied := map[int64]appliedMigration{
+		1: {Version: 1, Name: "init", AppliedAt: appliedAt},
+		2: {Version: 2, Name: "add_items", AppliedAt: appliedAt},
+		// Применена новой версией кода, которую потом откатили
+		3: {Version: 3, Name: "rolled_back_code", AppliedAt: appliedAt},
+	}
+
+	statuses := merge(migrations, applied)
+
+	type row struct {
+		version int64
+		applied bool
+		missing bool
+	}
+	var got []row
+	for _, s := range statuses {
+		got = append(got, row{s.Version, s.AppliedAt != nil, s.Missing})
+	}
+	want := []row{{1, true, false}, {2, true, false}, {3, true, true}, {4, false, false}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("statuses = %+v, want %+v", got, want)
+	}
+	if statuses[2].Name != "rolled_back_code" {
+		t.Errorf("missing migration name = %q", statuses[2].Name)
+	}
+	if len(applied) != 3 {
+		t.Error("merge modified the applied map")
+	}
+}
@@ -0,0 +1,10 @@
+// shop/shop.go
+package shop
+
+import _ "embed"
+
+// Default - каталог магазина, вшитый в бинарник. SHOP_CATALOG_FILE
+// подменяет его файлом того же формата без пересборки
+//
+//go:embed items.json
+var Default []byte